@@ -10,6 +10,7 @@ import (
 	"github.com/hieutt50/go-blockchain-explorer/internal/api"
 	"github.com/hieutt50/go-blockchain-explorer/internal/api/websocket"
 	"github.com/hieutt50/go-blockchain-explorer/internal/db"
+	"github.com/hieutt50/go-blockchain-explorer/internal/events"
 	"github.com/hieutt50/go-blockchain-explorer/internal/util"
 )
 
@@ -67,6 +68,20 @@ func main() {
 	go hub.Run(hubCtx)
 	util.Info("WebSocket hub started")
 
+	// Subscribe to worker events and forward them to the hub
+	eventsConfig, err := events.NewConfig()
+	if err != nil {
+		util.Error("failed to load events configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	subscriber, err := events.NewSubscriber(pool.Pool, hub, eventsConfig)
+	if err != nil {
+		util.Error("failed to create event subscriber", "error", err.Error())
+		os.Exit(1)
+	}
+	go subscriber.Run(hubCtx)
+	util.Info("event subscriber started", "channel", eventsConfig.Channel)
+
 	// Create API server with WebSocket hub
 	server := api.NewServerWithHub(pool, apiConfig, hub)
 	util.Info("API server initialized with WebSocket support")
@@ -61,7 +61,7 @@ func main() {
 	// Test 3: Query current data
 	fmt.Println(">>> Test Case 3: Current Data Status")
 	var blockCount int64
-	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM blocks WHERE orphaned = FALSE`).Scan(&blockCount)
+	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM blocks WHERE canonical = TRUE`).Scan(&blockCount)
 	if err != nil {
 		fmt.Printf("✗ Failed to query block count: %v\n", err)
 		os.Exit(1)
@@ -69,7 +69,11 @@ func main() {
 	fmt.Printf("✓ Blocks in database: %d\n", blockCount)
 
 	var txCount int64
-	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM transactions`).Scan(&txCount)
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.canonical = TRUE
+	`).Scan(&txCount)
 	if err != nil {
 		fmt.Printf("✗ Failed to query transaction count: %v\n", err)
 		os.Exit(1)
@@ -124,8 +128,8 @@ func main() {
 	err = pool.QueryRow(ctx, `
 		SELECT COUNT(DISTINCT b.height)
 		FROM blocks b
-		INNER JOIN transactions t ON b.height = t.block_height
-		WHERE b.orphaned = FALSE
+		INNER JOIN transactions t ON b.hash = t.block_hash
+		WHERE b.canonical = TRUE
 	`).Scan(&blockWithTxs)
 	if err != nil {
 		fmt.Printf("✗ Failed to query blocks with transactions: %v\n", err)
@@ -160,7 +164,7 @@ func main() {
 	if blockCount > 0 {
 		var sampleHeight int64
 		err = pool.QueryRow(ctx, `
-			SELECT height FROM blocks WHERE orphaned = FALSE LIMIT 1
+			SELECT height FROM blocks WHERE canonical = TRUE LIMIT 1
 		`).Scan(&sampleHeight)
 
 		if err == nil {
@@ -186,8 +190,8 @@ func main() {
 		var orphanedTxs int64
 		err = pool.QueryRow(ctx, `
 			SELECT COUNT(*) FROM transactions t
-			LEFT JOIN blocks b ON t.block_height = b.height
-			WHERE b.height IS NULL
+			LEFT JOIN blocks b ON t.block_hash = b.hash
+			WHERE b.hash IS NULL
 		`).Scan(&orphanedTxs)
 
 		if orphanedTxs > 0 {
@@ -196,12 +200,15 @@ func main() {
 			fmt.Println("✓ No orphaned transactions (foreign keys valid)")
 		}
 
-		// Check for duplicate transactions
+		// Check for duplicate transactions on the canonical chain
+		// (a transaction may legitimately also be kept in an orphaned block)
 		var duplicateTxs int64
 		err = pool.QueryRow(ctx, `
 			SELECT COUNT(*) FROM (
-				SELECT hash, COUNT(*) FROM transactions
-				GROUP BY hash HAVING COUNT(*) > 1
+				SELECT t.hash, COUNT(*) FROM transactions t
+				JOIN blocks b ON b.hash = t.block_hash
+				WHERE b.canonical = TRUE
+				GROUP BY t.hash HAVING COUNT(*) > 1
 			) AS dup
 		`).Scan(&duplicateTxs)
 
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/db"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+)
+
+// verifyBatchSize is the number of blocks fetched per RPC batch by verify
+const verifyBatchSize = 50
+
+// runReindex re-ingests [from, to] through the backfill coordinator
+// Blocks that differ from the chain are replaced (kept as orphaned); the range is checkpointed afterwards
+func runReindex(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	from, to := heightRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireRange(fs, *from, *to); err != nil {
+		return err
+	}
+
+	e, err := connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	if err := reindex(ctx, e, []index.HeightRange{{Start: *from, End: *to}}); err != nil {
+		return err
+	}
+	fmt.Printf("reindexed heights %d-%d (%d blocks)\n", *from, *to, *to-*from+1)
+	return nil
+}
+
+// reindex backfills every range with one coordinator
+func reindex(ctx context.Context, e *env, ranges []index.HeightRange) error {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	coordinator, err := e.backfillCoordinator(ranges[0].Start, ranges[len(ranges)-1].End)
+	if err != nil {
+		return err
+	}
+	for _, r := range ranges {
+		if err := coordinator.Backfill(ctx, r.Start, r.End); err != nil {
+			return fmt.Errorf("failed to reindex heights %d-%d: %w", r.Start, r.End, err)
+		}
+	}
+	return nil
+}
+
+// runVerify compares the stored canonical blocks and transaction hashes of [from, to] with RPC
+// Missing heights and mismatching blocks are listed; with --repair they are re-indexed.
+func runVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	from, to := heightRangeFlags(fs)
+	repair := fs.Bool("repair", false, "re-index missing and mismatching heights")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireRange(fs, *from, *to); err != nil {
+		return err
+	}
+
+	e, err := connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	gaps, err := e.store.FindMissingRanges(ctx, *from, *to)
+	if err != nil {
+		return err
+	}
+	missing := make(map[uint64]bool)
+	for _, gap := range gaps {
+		fmt.Printf("missing heights %d-%d\n", gap.Start, gap.End)
+		for h := gap.Start; h <= gap.End; h++ {
+			missing[h] = true
+		}
+	}
+
+	var bad []uint64
+	var mismatched int
+	for start := *from; start <= *to; start += verifyBatchSize {
+		end := min(start+verifyBatchSize-1, *to)
+
+		heights := make([]uint64, 0, end-start+1)
+		for h := start; h <= end; h++ {
+			if missing[h] {
+				bad = append(bad, h)
+				continue
+			}
+			heights = append(heights, h)
+		}
+		if len(heights) == 0 {
+			continue
+		}
+
+		chainBlocks, err := e.rpc.GetBlocksByNumber(ctx, heights)
+		if err != nil {
+			return fmt.Errorf("failed to fetch blocks %d-%d: %w", start, end, err)
+		}
+		storedBlocks, err := e.store.GetBlocksWithTxHashes(ctx, heights)
+		if err != nil {
+			return err
+		}
+		if len(storedBlocks) != len(heights) {
+			return fmt.Errorf("heights %d-%d changed during verification, retry", start, end)
+		}
+		for i, stored := range storedBlocks {
+			mismatches := index.CompareBlock(stored, chainBlocks[i])
+			mismatches = append(mismatches, index.CompareTransactions(stored, chainBlocks[i])...)
+			for _, m := range mismatches {
+				fmt.Println(m.String())
+			}
+			if len(mismatches) > 0 {
+				mismatched++
+				bad = append(bad, stored.Height)
+			}
+		}
+	}
+
+	fmt.Printf("verified heights %d-%d: %d missing, %d mismatching\n", *from, *to, len(missing), mismatched)
+	if len(bad) == 0 {
+		return nil
+	}
+	if !*repair {
+		return errMismatches
+	}
+
+	ranges := index.GroupHeights(bad)
+	if err := reindex(ctx, e, ranges); err != nil {
+		return err
+	}
+	fmt.Printf("repaired %d heights in %d ranges\n", len(bad), len(ranges))
+	return nil
+}
+
+// runOrphan marks the canonical blocks of [from, to] as orphaned through the reorg handler
+// Finalized blocks are refused; the next live-tail or gap scan re-indexes the range.
+func runOrphan(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("orphan", flag.ContinueOnError)
+	from, to := heightRangeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireRange(fs, *from, *to); err != nil {
+		return err
+	}
+
+	e, err := connect(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	reorgConfig, err := index.NewReorgConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load reorg configuration: %w", err)
+	}
+	handler, err := index.NewReorgHandler(e.rpc, e.store, reorgConfig)
+	if err != nil {
+		return err
+	}
+
+	finality, err := index.NewFinalityTracker(e.rpc, e.store, index.DefaultFinalityConfig())
+	if err != nil {
+		return err
+	}
+	if err := finality.Load(ctx); err != nil {
+		return err
+	}
+	handler.SetFinality(finality)
+
+	if err := handler.OrphanRange(ctx, *from, *to); err != nil {
+		return err
+	}
+	fmt.Printf("orphaned canonical blocks at heights %d-%d\n", *from, *to)
+	return nil
+}
+
+// runGaps lists the heights without a canonical block between --from (default BACKFILL_START_HEIGHT)
+// and --to (default database head); with --fill they are backfilled
+func runGaps(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("gaps", flag.ContinueOnError)
+	from, to := heightRangeFlags(fs)
+	fill := fs.Bool("fill", false, "backfill the missing heights")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	set := flagsSet(fs)
+
+	e, err := connect(ctx, *fill)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	if !set["from"] {
+		config, err := index.NewConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load backfill configuration: %w", err)
+		}
+		*from = config.StartHeight
+	}
+	if !set["to"] {
+		latest, err := e.store.GetLatestBlock(ctx)
+		if err != nil {
+			return err
+		}
+		*to = latest.Height
+	}
+	if *from > *to {
+		return fmt.Errorf("--from (%d) must be <= --to (%d)", *from, *to)
+	}
+
+	gaps, err := e.store.FindMissingRanges(ctx, *from, *to)
+	if err != nil {
+		return err
+	}
+	var missing uint64
+	for _, gap := range gaps {
+		fmt.Printf("%d-%d (%d heights)\n", gap.Start, gap.End, gap.Count())
+		missing += gap.Count()
+	}
+	fmt.Printf("%d gaps, %d missing heights between %d and %d\n", len(gaps), missing, *from, *to)
+
+	if !*fill || len(gaps) == 0 {
+		return nil
+	}
+
+	coordinator, err := e.backfillCoordinator(*from, *to)
+	if err != nil {
+		return err
+	}
+	filled, err := coordinator.FillGaps(ctx, *from, *to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("filled %d heights\n", filled)
+	return nil
+}
+
+// runMigrate applies all migrations (up), rolls back the last one (down) or prints the version
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected up, down or version")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	path := fs.String("path", "migrations", "directory containing the migration files")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	config, err := db.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load database configuration: %w", err)
+	}
+
+	switch action {
+	case "up":
+		if err := db.RunMigrations(config, *path); err != nil {
+			return err
+		}
+	case "down":
+		if err := db.RollbackMigrations(config, *path); err != nil {
+			return err
+		}
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate action %q (expected up, down or version)", action)
+	}
+
+	version, dirty, err := db.GetMigrationVersion(config, *path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("migration version %d (dirty: %t)\n", version, dirty)
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/db"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+	"github.com/hieutt50/go-blockchain-explorer/internal/store"
+)
+
+// explorerctl is the operator tool for maintaining the index without restarting the worker
+// Configuration comes from the same environment variables as the worker (DB_*, RPC_*, BACKFILL_*, ...)
+const usage = `Usage: explorerctl <command> [flags]
+
+Commands:
+  reindex --from N --to M          Re-ingest a height range from RPC (replaces stored blocks)
+  verify  --from N --to M [--repair]
+                                   Compare stored hashes, tx counts and tx hashes against RPC
+  orphan  --from N --to M          Mark the canonical blocks in a range as orphaned
+  gaps    [--from N] [--to M] [--fill]
+                                   List (and optionally refill) missing heights
+  migrate up|down|version [--path DIR]
+                                   Apply, roll back one or show database migrations
+
+Run "explorerctl <command> -h" for command flags.
+`
+
+// errMismatches is returned by verify when stored blocks differ from the chain (exit code 2)
+var errMismatches = errors.New("stored blocks differ from the chain")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	commands := map[string]func(ctx context.Context, args []string) error{
+		"reindex": runReindex,
+		"verify":  runVerify,
+		"orphan":  runOrphan,
+		"gaps":    runGaps,
+		"migrate": runMigrate,
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if err := command(ctx, os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "explorerctl %s: %v\n", os.Args[1], err)
+		if errors.Is(err, errMismatches) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// heightRangeFlags registers the --from and --to flags shared by the range commands
+func heightRangeFlags(fs *flag.FlagSet) (*uint64, *uint64) {
+	from := fs.Uint64("from", 0, "first height of the range (inclusive)")
+	to := fs.Uint64("to", 0, "last height of the range (inclusive)")
+	return from, to
+}
+
+// flagsSet returns the names of the flags given on the command line
+func flagsSet(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// requireRange checks that both --from and --to were given and form a valid range
+func requireRange(fs *flag.FlagSet, from, to uint64) error {
+	set := flagsSet(fs)
+	if !set["from"] || !set["to"] {
+		return fmt.Errorf("--from and --to are required")
+	}
+	if from > to {
+		return fmt.Errorf("--from (%d) must be <= --to (%d)", from, to)
+	}
+	return nil
+}
+
+// env holds the connections used by a command; RPC is only set up when requested
+type env struct {
+	pool  *db.Pool
+	store *store.IndexerAdapter
+	rpc   *rpc.Pool
+}
+
+// connect opens the database and, with withRPC, the RPC provider pool
+func connect(ctx context.Context, withRPC bool) (*env, error) {
+	dbConfig, err := db.NewConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database configuration: %w", err)
+	}
+	pool, err := db.NewPool(ctx, dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	e := &env{
+		pool:  pool,
+		store: store.NewIndexerAdapter(pool),
+	}
+	if !withRPC {
+		return e, nil
+	}
+
+	rpcConfig, err := rpc.NewConfig()
+	if err != nil {
+		e.close()
+		return nil, fmt.Errorf("failed to load RPC configuration: %w", err)
+	}
+	e.rpc, err = rpc.NewPool(rpcConfig)
+	if err != nil {
+		e.close()
+		return nil, fmt.Errorf("failed to create RPC provider pool: %w", err)
+	}
+	return e, nil
+}
+
+// close releases the connections
+func (e *env) close() {
+	if e.rpc != nil {
+		e.rpc.Close()
+	}
+	e.pool.Close()
+}
+
+// backfillCoordinator creates a backfill coordinator for [from, to] with the worker's ingester and checkpoints
+func (e *env) backfillCoordinator(from, to uint64) (*index.BackfillCoordinator, error) {
+	config, err := index.NewConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backfill configuration: %w", err)
+	}
+	config.StartHeight = from
+	config.EndHeight = to
+
+	ingestConfig, err := index.NewIngestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ingest configuration: %w", err)
+	}
+	ingester, err := store.NewBlockIngester(e.rpc, ingestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block ingester: %w", err)
+	}
+
+	coordinator, err := index.NewBackfillCoordinator(e.rpc, e.store, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backfill coordinator: %w", err)
+	}
+	coordinator.SetIngester(ingester)
+	coordinator.SetStateStore(e.store)
+	return coordinator, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hieutt50/go-blockchain-explorer/internal/db"
+	"github.com/hieutt50/go-blockchain-explorer/internal/events"
 	"github.com/hieutt50/go-blockchain-explorer/internal/index"
 	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
 	"github.com/hieutt50/go-blockchain-explorer/internal/store"
@@ -55,6 +57,11 @@ func main() {
 	}
 	util.Info("RPC configuration loaded",
 		"rpc_url", maskAPIKey(rpcConfig.RPCURL),
+		"providers", len(rpcConfig.RPCURLs),
+		"head_subscription", rpcConfig.WSURL != "",
+		"rate_limit", rpcConfig.RateLimit,
+		"max_concurrency", rpcConfig.MaxConcurrency,
+		"breaker_failure_threshold", rpcConfig.BreakerFailureThreshold,
 	)
 
 	// Load indexer configurations
@@ -65,7 +72,11 @@ func main() {
 	}
 	util.Info("backfill configuration loaded",
 		"workers", backfillConfig.Workers,
+		"min_workers", backfillConfig.MinWorkers,
+		"max_workers", backfillConfig.MaxWorkers,
 		"batch_size", backfillConfig.BatchSize,
+		"fetch_batch_size", backfillConfig.FetchBatchSize,
+		"gap_scan_interval", backfillConfig.GapScanInterval,
 		"start_height", backfillConfig.StartHeight,
 		"end_height", backfillConfig.EndHeight,
 	)
@@ -77,6 +88,29 @@ func main() {
 	}
 	util.Info("livetail configuration loaded",
 		"poll_interval", livetailConfig.PollInterval,
+		"catchup_workers", livetailConfig.CatchUpWorkers,
+		"catchup_window", livetailConfig.CatchUpWindow,
+		"catchup_distance", livetailConfig.CatchUpDistance,
+	)
+
+	ingestConfig, err := index.NewIngestConfig()
+	if err != nil {
+		util.Error("failed to load ingest configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("ingest configuration loaded",
+		"receipt_mode", ingestConfig.ReceiptMode,
+		"trace_calls", ingestConfig.TraceCalls,
+	)
+
+	eventsConfig, err := events.NewConfig()
+	if err != nil {
+		util.Error("failed to load events configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("events configuration loaded",
+		"channel", eventsConfig.Channel,
+		"buffer_size", eventsConfig.BufferSize,
 	)
 
 	reorgConfig, err := index.NewReorgConfig()
@@ -86,6 +120,38 @@ func main() {
 	}
 	util.Info("reorg configuration loaded",
 		"max_depth", reorgConfig.MaxDepth,
+		"recovery_max_depth", reorgConfig.RecoveryMaxDepth,
+	)
+
+	finalityConfig, err := index.NewFinalityConfig()
+	if err != nil {
+		util.Error("failed to load finality configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("finality configuration loaded",
+		"poll_interval", finalityConfig.PollInterval,
+	)
+
+	integrityConfig, err := index.NewIntegrityConfig()
+	if err != nil {
+		util.Error("failed to load integrity configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("integrity configuration loaded",
+		"interval", integrityConfig.Interval,
+		"recent_blocks", integrityConfig.RecentBlocks,
+		"random_samples", integrityConfig.RandomSamples,
+		"continuity_window", integrityConfig.ContinuityWindow,
+	)
+
+	tokenMetadataConfig, err := index.NewTokenMetadataConfig()
+	if err != nil {
+		util.Error("failed to load token metadata configuration", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("token metadata configuration loaded",
+		"interval", tokenMetadataConfig.Interval,
+		"batch_size", tokenMetadataConfig.BatchSize,
 	)
 
 	// =============================================================================
@@ -111,18 +177,28 @@ func main() {
 	// Component Creation
 	// =============================================================================
 
-	// Create RPC client
-	rpcClient, err := rpc.NewClient(rpcConfig)
+	// Create RPC provider pool (fails over between RPC_URL and RPC_URLS endpoints)
+	rpcClient, err := rpc.NewPool(rpcConfig)
 	if err != nil {
-		util.Error("failed to create RPC client", "error", err.Error())
+		util.Error("failed to create RPC provider pool", "error", err.Error())
 		os.Exit(1)
 	}
-	util.Info("RPC client created")
+	defer rpcClient.Close()
+	go rpcClient.Run(ctx) // Periodic provider health checks
+	util.Info("RPC provider pool created", "providers", len(rpcConfig.RPCURLs))
 
 	// Create store adapter for indexer operations
 	storeAdapter := store.NewIndexerAdapter(pool)
 	util.Info("store adapter created")
 
+	// Create block ingester (parses transactions, fetches receipts and call traces if configured)
+	blockIngester, err := store.NewBlockIngester(rpcClient, ingestConfig)
+	if err != nil {
+		util.Error("failed to create block ingester", "error", err.Error())
+		os.Exit(1)
+	}
+	util.Info("block ingester created", "receipt_mode", ingestConfig.ReceiptMode, "trace_calls", ingestConfig.TraceCalls)
+
 	// =============================================================================
 	// Start Metrics Server
 	// =============================================================================
@@ -140,13 +216,43 @@ func main() {
 	// Backfill Phase (if needed)
 	// =============================================================================
 
+	// Create backfill coordinator with store for database insertion
+	backfillCoordinator, err := index.NewBackfillCoordinator(rpcClient, storeAdapter, backfillConfig)
+	if err != nil {
+		util.Error("failed to create backfill coordinator", "error", err.Error())
+		os.Exit(1)
+	}
+	backfillCoordinator.SetIngester(blockIngester)
+	backfillCoordinator.SetStateStore(storeAdapter) // indexer_state checkpoints and gap detection
+
 	// Check if backfill is needed
 	needsBackfill := true
+	backfillStartHeight := backfillConfig.StartHeight
 	latestBlock, err := storeAdapter.GetLatestBlock(ctx)
 	if err == nil && latestBlock != nil {
 		util.Info("database already contains blocks",
 			"latest_height", latestBlock.Height,
 		)
+
+		// Refill holes left by earlier runs (failed batches, crashes); checkpointed ranges are skipped
+		resumeHeight, err := backfillCoordinator.ResumeHeight(ctx, backfillConfig.StartHeight)
+		if err != nil {
+			util.Warn("failed to read backfill checkpoints, scanning from start height", "error", err.Error())
+			resumeHeight = backfillConfig.StartHeight
+		}
+		if resumeHeight <= latestBlock.Height {
+			filled, err := backfillCoordinator.FillGaps(ctx, resumeHeight, latestBlock.Height)
+			if err != nil {
+				util.Warn("gap refill incomplete, the periodic gap scanner will retry", "error", err.Error())
+			} else {
+				util.Info("gap scan completed",
+					"start_height", resumeHeight,
+					"end_height", latestBlock.Height,
+					"heights_refilled", filled,
+				)
+			}
+		}
+
 		// Only backfill if target end height is greater than current latest
 		if latestBlock.Height >= backfillConfig.EndHeight {
 			needsBackfill = false
@@ -155,10 +261,10 @@ func main() {
 				"target_height", backfillConfig.EndHeight,
 			)
 		} else {
-			// Adjust start height to continue from latest
-			backfillConfig.StartHeight = latestBlock.Height + 1
+			// Continue from latest (gaps below it were refilled above)
+			backfillStartHeight = latestBlock.Height + 1
 			util.Info("backfill needed - continuing from latest block",
-				"start_height", backfillConfig.StartHeight,
+				"start_height", backfillStartHeight,
 				"end_height", backfillConfig.EndHeight,
 			)
 		}
@@ -173,22 +279,22 @@ func main() {
 		util.Info("starting backfill phase")
 		backfillStart := time.Now()
 
-		// Create backfill coordinator with store for database insertion
-		backfillCoordinator, err := index.NewBackfillCoordinator(rpcClient, storeAdapter, backfillConfig)
-		if err != nil {
-			util.Error("failed to create backfill coordinator", "error", err.Error())
-			os.Exit(1)
-		}
-
 		// Run backfill - fetches blocks in parallel and stores them in database
-		err = backfillCoordinator.Backfill(ctx, backfillConfig.StartHeight, backfillConfig.EndHeight)
-		if err != nil {
+		err = backfillCoordinator.Backfill(ctx, backfillStartHeight, backfillConfig.EndHeight)
+		var backfillErr *index.BackfillError
+		if errors.As(err, &backfillErr) {
+			// The rest of the range is indexed; the gap scanner retries the dead-lettered heights
+			util.Warn("backfill completed with failed heights",
+				"failed_heights", len(backfillErr.Failed),
+				"duration_seconds", time.Since(backfillStart).Seconds(),
+			)
+		} else if err != nil {
 			util.Error("backfill failed", "error", err.Error())
 			// Don't exit - continue to live-tail which will catch up
 			util.Warn("continuing to live-tail despite backfill failure")
 		} else {
 			backfillDuration := time.Since(backfillStart)
-			blocksBackfilled := backfillConfig.EndHeight - backfillConfig.StartHeight + 1
+			blocksBackfilled := backfillConfig.EndHeight - backfillStartHeight + 1
 			util.Info("backfill phase completed",
 				"duration_seconds", backfillDuration.Seconds(),
 				"blocks_backfilled", blocksBackfilled,
@@ -197,6 +303,9 @@ func main() {
 		}
 	}
 
+	// Periodically refill gaps below the head (e.g. from failed backfill batches)
+	go backfillCoordinator.RunGapScanner(ctx)
+
 	// =============================================================================
 	// Create Reorg Handler
 	// =============================================================================
@@ -206,21 +315,71 @@ func main() {
 		util.Error("failed to create reorg handler", "error", err.Error())
 		os.Exit(1)
 	}
+	reorgHandler.SetRecorder(storeAdapter)  // Record reorg history for /v1/reorgs
+	reorgHandler.SetIngester(blockIngester) // Deep reorg recovery re-indexes full blocks
 	util.Info("reorg handler created")
 
+	// Track safe/finalized heights; the reorg handler refuses to orphan finalized blocks
+	if finalityConfig.PollInterval > 0 {
+		finalityTracker, err := index.NewFinalityTracker(rpcClient, storeAdapter, finalityConfig)
+		if err != nil {
+			util.Error("failed to create finality tracker", "error", err.Error())
+			os.Exit(1)
+		}
+		if err := finalityTracker.Load(ctx); err != nil {
+			util.Error("failed to load finalized height", "error", err.Error())
+			os.Exit(1)
+		}
+		reorgHandler.SetFinality(finalityTracker)
+		go finalityTracker.Run(ctx)
+		util.Info("finality tracker created",
+			"finalized_height", finalityTracker.FinalizedHeight(),
+		)
+	}
+
+	// Compare sampled stored blocks against RPC and check parent-hash continuity (mismatches surface in /health)
+	if integrityConfig.Interval > 0 {
+		integrityVerifier, err := index.NewIntegrityVerifier(rpcClient, storeAdapter, integrityConfig)
+		if err != nil {
+			util.Error("failed to create integrity verifier", "error", err.Error())
+			os.Exit(1)
+		}
+		go integrityVerifier.Run(ctx)
+		util.Info("integrity verifier created")
+	}
+
+	// Resolve name, symbol, decimals, total supply and standard of newly seen token contracts with eth_call
+	if tokenMetadataConfig.Interval > 0 {
+		tokenMetadataResolver, err := index.NewTokenMetadataResolver(rpcClient, storeAdapter, tokenMetadataConfig)
+		if err != nil {
+			util.Error("failed to create token metadata resolver", "error", err.Error())
+			os.Exit(1)
+		}
+		go tokenMetadataResolver.Run(ctx)
+		util.Info("token metadata resolver created")
+	}
+
 	// =============================================================================
 	// Live-Tail Phase
 	// =============================================================================
 
 	util.Info("starting live-tail phase")
 
+	// Create event publisher so the API process can stream new blocks and transactions
+	eventPublisher, err := events.NewPublisher(pool.Pool, eventsConfig)
+	if err != nil {
+		util.Error("failed to create event publisher", "error", err.Error())
+		os.Exit(1)
+	}
+	go eventPublisher.Run(ctx)
+
 	// Create live-tail coordinator
 	liveTailCoordinator, err := index.NewLiveTailCoordinator(
 		rpcClient,
 		storeAdapter,
-		nil, // no ingester needed - using default parser
+		blockIngester,
 		reorgHandler,
-		nil, // no WebSocket hub in worker
+		eventPublisher, // forwarded to the API WebSocket hub via Postgres NOTIFY
 		livetailConfig,
 	)
 	if err != nil {
@@ -228,6 +387,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Subscribe to new heads when a WebSocket endpoint is configured (polling remains the fallback)
+	if rpcConfig.WSURL != "" {
+		headSubscriber, err := rpc.NewHeadSubscriber(rpcConfig)
+		if err != nil {
+			util.Error("failed to create head subscriber", "error", err.Error())
+			os.Exit(1)
+		}
+		liveTailCoordinator.SetHeadSource(headSubscriber)
+		util.Info("live-tail head subscription enabled", "ws_url", maskAPIKey(rpcConfig.WSURL))
+	}
+
 	// Start live-tail in background goroutine
 	liveTailCtx, liveTailCancel := context.WithCancel(ctx)
 	defer liveTailCancel()
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/hex"
 	"errors"
+	"math/big"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -17,6 +18,9 @@ var (
 
 	// hashRegex validates transaction/block hashes (0x + 64 hex characters)
 	hashRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+	// tokenIDRegex validates NFT token IDs (decimal, at most 78 digits like 2^256-1)
+	tokenIDRegex = regexp.MustCompile(`^[0-9]{1,78}$`)
 )
 
 // handleListBlocks handles GET /v1/blocks - List recent blocks with pagination
@@ -118,6 +122,45 @@ func (s *Server) handleGetTransaction(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, tx)
 }
 
+// handleGetTransactionInternalTxs handles GET /v1/txs/{hash}/internal - Get internal transactions (traced calls) of a transaction
+func (s *Server) handleGetTransactionInternalTxs(w http.ResponseWriter, r *http.Request) {
+	// Parse transaction hash parameter
+	txHash := chi.URLParam(r, "hash")
+
+	// Validate hash format
+	if !hashRegex.MatchString(txHash) {
+		writeBadRequest(w, "invalid transaction hash format (expected 0x + 64 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=100, max=1000)
+	limit, offset := parsePagination(r, 100, 1000)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query internal transactions
+	internalTxs, total, err := st.GetTransactionInternalTxs(r.Context(), txHash, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeNotFound(w, "transaction not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"internal_transactions": internalTxs,
+		"total":                 total,
+		"limit":                 limit,
+		"offset":                offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
 // handleGetAddressTransactions handles GET /v1/address/{addr}/txs - Get transactions for address
 func (s *Server) handleGetAddressTransactions(w http.ResponseWriter, r *http.Request) {
 	// Parse address parameter
@@ -190,6 +233,411 @@ func (s *Server) handleGetBlockTransactions(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, http.StatusOK, response)
 }
 
+// handleGetBlockWithdrawals handles GET /v1/blocks/{height}/withdrawals - Get validator withdrawals for a block
+func (s *Server) handleGetBlockWithdrawals(w http.ResponseWriter, r *http.Request) {
+	// Parse block height parameter
+	heightParam := chi.URLParam(r, "height")
+
+	// Parse height as integer
+	height, err := strconv.ParseInt(heightParam, 10, 64)
+	if err != nil || height < 0 {
+		writeBadRequest(w, "invalid block height (expected non-negative integer)")
+		return
+	}
+
+	// Parse pagination (default limit=100, max=1000)
+	limit, offset := parsePagination(r, 100, 1000)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query withdrawals
+	withdrawals, total, err := st.GetBlockWithdrawals(r.Context(), height, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"withdrawals": withdrawals,
+		"total":       total,
+		"limit":       limit,
+		"offset":      offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetAddressWithdrawals handles GET /v1/address/{addr}/withdrawals - Get validator withdrawals received by an address
+func (s *Server) handleGetAddressWithdrawals(w http.ResponseWriter, r *http.Request) {
+	// Parse address parameter
+	address := chi.URLParam(r, "addr")
+
+	// Validate address format
+	if !addressRegex.MatchString(address) {
+		writeBadRequest(w, "invalid address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query withdrawals
+	withdrawals, total, err := st.GetAddressWithdrawals(r.Context(), address, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"address":     address,
+		"withdrawals": withdrawals,
+		"total":       total,
+		"limit":       limit,
+		"offset":      offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetAddressInternalTxs handles GET /v1/address/{addr}/internal - Get internal transactions sent or received by an address
+func (s *Server) handleGetAddressInternalTxs(w http.ResponseWriter, r *http.Request) {
+	// Parse address parameter
+	address := chi.URLParam(r, "addr")
+
+	// Validate address format
+	if !addressRegex.MatchString(address) {
+		writeBadRequest(w, "invalid address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query internal transactions
+	internalTxs, total, err := st.GetAddressInternalTxs(r.Context(), address, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"address":               address,
+		"internal_transactions": internalTxs,
+		"total":                 total,
+		"limit":                 limit,
+		"offset":                offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetAddressTokens handles GET /v1/address/{addr}/tokens - Get ERC-20 token balances of an address
+func (s *Server) handleGetAddressTokens(w http.ResponseWriter, r *http.Request) {
+	// Parse address parameter
+	address := chi.URLParam(r, "addr")
+
+	// Validate address format
+	if !addressRegex.MatchString(address) {
+		writeBadRequest(w, "invalid address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query token balances
+	tokens, total, err := st.GetAddressTokens(r.Context(), address, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"address": address,
+		"tokens":  tokens,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetToken handles GET /v1/tokens/{token} - Get the metadata of a token or NFT contract
+func (s *Server) handleGetToken(w http.ResponseWriter, r *http.Request) {
+	// Parse token address parameter
+	token := chi.URLParam(r, "token")
+
+	// Validate address format
+	if !addressRegex.MatchString(token) {
+		writeBadRequest(w, "invalid token address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query token metadata
+	t, err := st.GetToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeNotFound(w, "token not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// handleGetTokenHolders handles GET /v1/tokens/{token}/holders - Get holders of an ERC-20 token, largest balance first
+func (s *Server) handleGetTokenHolders(w http.ResponseWriter, r *http.Request) {
+	// Parse token address parameter
+	token := chi.URLParam(r, "token")
+
+	// Validate address format
+	if !addressRegex.MatchString(token) {
+		writeBadRequest(w, "invalid token address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query token holders
+	holders, total, err := st.GetTokenHolders(r.Context(), token, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"token_address": token,
+		"holders":       holders,
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetTokenTransfers handles GET /v1/tokens/{token}/transfers - Get transfers of an ERC-20 token, newest first
+func (s *Server) handleGetTokenTransfers(w http.ResponseWriter, r *http.Request) {
+	// Parse token address parameter
+	token := chi.URLParam(r, "token")
+
+	// Validate address format
+	if !addressRegex.MatchString(token) {
+		writeBadRequest(w, "invalid token address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query token transfers
+	transfers, total, err := st.GetTokenTransfers(r.Context(), token, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"token_address": token,
+		"transfers":     transfers,
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetAddressNFTs handles GET /v1/address/{addr}/nfts - Get ERC-721 and ERC-1155 tokens owned by an address
+func (s *Server) handleGetAddressNFTs(w http.ResponseWriter, r *http.Request) {
+	// Parse address parameter
+	address := chi.URLParam(r, "addr")
+
+	// Validate address format
+	if !addressRegex.MatchString(address) {
+		writeBadRequest(w, "invalid address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query owned NFTs
+	nfts, total, err := st.GetAddressNFTs(r.Context(), address, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"address": address,
+		"nfts":    nfts,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetNFT handles GET /v1/nfts/{contract}/{tokenId} - Get the owners and transfer history of an NFT
+func (s *Server) handleGetNFT(w http.ResponseWriter, r *http.Request) {
+	// Parse contract address and token ID parameters
+	contract := chi.URLParam(r, "contract")
+	tokenID := chi.URLParam(r, "tokenId")
+
+	// Validate address format
+	if !addressRegex.MatchString(contract) {
+		writeBadRequest(w, "invalid contract address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Validate token ID (decimal uint256)
+	if !tokenIDRegex.MatchString(tokenID) {
+		writeBadRequest(w, "invalid token ID (expected decimal integer below 2^256)")
+		return
+	}
+	if id, _ := new(big.Int).SetString(tokenID, 10); id.BitLen() > 256 {
+		writeBadRequest(w, "invalid token ID (expected decimal integer below 2^256)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query NFT owners and transfers
+	nft, total, err := st.GetNFT(r.Context(), contract, tokenID, limit, offset)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeNotFound(w, "NFT not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"contract_address": nft.ContractAddress,
+		"token_id":         nft.TokenID,
+		"owners":           nft.Owners,
+		"transfers":        nft.Transfers,
+		"total":            total,
+		"limit":            limit,
+		"offset":           offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetCollectionTokens handles GET /v1/nfts/{contract}/tokens - Get owned tokens of an NFT collection
+func (s *Server) handleGetCollectionTokens(w http.ResponseWriter, r *http.Request) {
+	// Parse contract address parameter
+	contract := chi.URLParam(r, "contract")
+
+	// Validate address format
+	if !addressRegex.MatchString(contract) {
+		writeBadRequest(w, "invalid contract address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query collection tokens
+	tokens, total, err := st.GetCollectionTokens(r.Context(), contract, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"contract_address": contract,
+		"tokens":           tokens,
+		"total":            total,
+		"limit":            limit,
+		"offset":           offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetCollectionTransfers handles GET /v1/nfts/{contract}/transfers - Get transfers of an NFT collection, newest first
+func (s *Server) handleGetCollectionTransfers(w http.ResponseWriter, r *http.Request) {
+	// Parse contract address parameter
+	contract := chi.URLParam(r, "contract")
+
+	// Validate address format
+	if !addressRegex.MatchString(contract) {
+		writeBadRequest(w, "invalid contract address format (expected 0x + 40 hex characters)")
+		return
+	}
+
+	// Parse pagination (default limit=50, max=100)
+	limit, offset := parsePagination(r, 50, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query collection transfers
+	transfers, total, err := st.GetCollectionTransfers(r.Context(), contract, limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"contract_address": contract,
+		"transfers":        transfers,
+		"total":            total,
+		"limit":            limit,
+		"offset":           offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
 // handleQueryLogs handles GET /v1/logs - Query event logs with filters
 func (s *Server) handleQueryLogs(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
@@ -255,7 +703,62 @@ func (s *Server) handleChainStats(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, stats)
 }
 
+// handleListReorgs handles GET /v1/reorgs - List recorded chain reorganizations
+func (s *Server) handleListReorgs(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination (default limit=25, max=100)
+	limit, offset := parsePagination(r, 25, 100)
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query reorgs
+	reorgs, total, err := st.ListReorgs(r.Context(), limit, offset)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Build response
+	response := map[string]interface{}{
+		"reorgs": reorgs,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleGetReorg handles GET /v1/reorgs/{id} - Get a reorg with its replaced and replacing blocks
+func (s *Server) handleGetReorg(w http.ResponseWriter, r *http.Request) {
+	// Parse reorg ID parameter
+	idParam := chi.URLParam(r, "id")
+
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil || id <= 0 {
+		writeBadRequest(w, "invalid reorg id (expected positive integer)")
+		return
+	}
+
+	// Create store
+	st := store.NewStore(s.pool.Pool)
+
+	// Query reorg
+	reorg, err := st.GetReorg(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeNotFound(w, "reorg not found")
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reorg)
+}
+
 // handleHealth handles GET /health - Health check endpoint
+// Degraded (e.g. open integrity mismatches) still returns 200; only unhealthy returns 503
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	// Create store
 	st := store.NewStore(s.pool.Pool)
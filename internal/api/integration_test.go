@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,10 +63,30 @@ func TestIntegrationAPI(t *testing.T) {
 		testGetBlockByHash(t, router)
 	})
 
+	t.Run("Withdrawals", func(t *testing.T) {
+		testWithdrawals(t, router)
+	})
+
+	t.Run("Internal Transactions", func(t *testing.T) {
+		testInternalTransactions(t, router)
+	})
+
+	t.Run("Tokens", func(t *testing.T) {
+		testTokens(t, router)
+	})
+
+	t.Run("NFTs", func(t *testing.T) {
+		testNFTs(t, router)
+	})
+
 	t.Run("Get Chain Stats", func(t *testing.T) {
 		testGetChainStats(t, router)
 	})
 
+	t.Run("List Reorgs", func(t *testing.T) {
+		testListReorgs(t, router)
+	})
+
 	t.Run("Pagination", func(t *testing.T) {
 		testPagination(t, router)
 	})
@@ -117,7 +139,7 @@ func testHealthCheck(t *testing.T, router http.Handler) {
 	err := json.Unmarshal(w.Body.Bytes(), &health)
 	require.NoError(t, err, "should parse health response")
 
-	assert.Contains(t, []string{"healthy", "unhealthy"}, health.Status)
+	assert.Contains(t, []string{"healthy", "degraded", "unhealthy"}, health.Status)
 	assert.NotEmpty(t, health.Version)
 }
 
@@ -217,6 +239,206 @@ func testGetBlockByHash(t *testing.T, router http.Handler) {
 	assert.Equal(t, hash, blockResponse.Hash)
 }
 
+func testWithdrawals(t *testing.T, router http.Handler) {
+	// Withdrawals of the latest block (none before Shanghai)
+	req := httptest.NewRequest("GET", "/v1/blocks?limit=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var listResponse struct {
+		Blocks []store.Block `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResponse))
+	if len(listResponse.Blocks) == 0 {
+		t.Skip("no blocks available for testing")
+	}
+	block := listResponse.Blocks[0]
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/v1/blocks/%d/withdrawals", block.Height), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Withdrawals []store.Withdrawal `json:"withdrawals"`
+		Total       int64              `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(len(response.Withdrawals)), response.Total)
+	if block.WithdrawalsRoot == nil {
+		assert.Empty(t, response.Withdrawals, "no withdrawals before Shanghai")
+	}
+
+	// Withdrawals of the first recipient are listed for its address
+	if len(response.Withdrawals) > 0 {
+		address := response.Withdrawals[0].Address
+		req = httptest.NewRequest("GET", "/v1/address/"+address+"/withdrawals", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var addressResponse struct {
+			Withdrawals []store.Withdrawal `json:"withdrawals"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &addressResponse))
+		require.NotEmpty(t, addressResponse.Withdrawals)
+		for _, withdrawal := range addressResponse.Withdrawals {
+			assert.Equal(t, address, withdrawal.Address)
+		}
+	}
+}
+
+func testInternalTransactions(t *testing.T, router http.Handler) {
+	// Internal transactions of an address (empty unless the worker traces calls)
+	address := "0x" + strings.Repeat("00", 20)
+	req := httptest.NewRequest("GET", "/v1/address/"+address+"/internal?limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		InternalTransactions []store.InternalTransaction `json:"internal_transactions"`
+		Total                int64                       `json:"total"`
+		Limit                int                         `json:"limit"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotNil(t, response.InternalTransactions)
+	assert.Equal(t, 10, response.Limit)
+	for _, internalTx := range response.InternalTransactions {
+		assert.True(t, internalTx.FromAddr == address || (internalTx.ToAddr != nil && *internalTx.ToAddr == address))
+	}
+}
+
+func testTokens(t *testing.T, router http.Handler) {
+	// Latest transfers of a token (empty unless the worker ingests receipts)
+	token := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	req := httptest.NewRequest("GET", "/v1/tokens/"+token+"/transfers?limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var transfersResponse struct {
+		Transfers []store.TokenTransfer `json:"transfers"`
+		Total     int64                 `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transfersResponse))
+	assert.NotNil(t, transfersResponse.Transfers)
+	for _, transfer := range transfersResponse.Transfers {
+		assert.Equal(t, token, transfer.TokenAddress)
+	}
+
+	// Holders are ordered by balance, largest first
+	req = httptest.NewRequest("GET", "/v1/tokens/"+token+"/holders", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var holdersResponse struct {
+		Holders []store.TokenBalance `json:"holders"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &holdersResponse))
+	assert.NotNil(t, holdersResponse.Holders)
+
+	// The largest holder's balances include the token
+	if len(holdersResponse.Holders) > 0 {
+		holder := holdersResponse.Holders[0].Holder
+		req = httptest.NewRequest("GET", "/v1/address/"+holder+"/tokens?limit=100", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var tokensResponse struct {
+			Tokens []store.TokenBalance `json:"tokens"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokensResponse))
+		assert.Contains(t, tokensResponse.Tokens, holdersResponse.Holders[0])
+	}
+
+	// A token with indexed transfers is queued for metadata resolution
+	if len(transfersResponse.Transfers) > 0 {
+		req = httptest.NewRequest("GET", "/v1/tokens/"+token, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var tokenResponse store.Token
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokenResponse))
+		assert.Equal(t, token, tokenResponse.Address)
+		if tokenResponse.ResolvedAt != nil {
+			assert.NotNil(t, tokenResponse.Standard)
+		}
+	}
+}
+
+func testNFTs(t *testing.T, router http.Handler) {
+	// Latest transfers of a collection (empty unless the worker ingests receipts)
+	contract := "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d"
+	req := httptest.NewRequest("GET", "/v1/nfts/"+contract+"/transfers?limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var transfersResponse struct {
+		Transfers []store.NFTTransfer `json:"transfers"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transfersResponse))
+	assert.NotNil(t, transfersResponse.Transfers)
+	for _, transfer := range transfersResponse.Transfers {
+		assert.Equal(t, contract, transfer.ContractAddress)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/nfts/"+contract+"/tokens", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var tokensResponse struct {
+		Tokens []store.NFTOwnership `json:"tokens"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokensResponse))
+	assert.NotNil(t, tokensResponse.Tokens)
+
+	// An owned token lists its owner, whose NFTs include the token
+	if len(tokensResponse.Tokens) > 0 {
+		owned := tokensResponse.Tokens[0]
+		req = httptest.NewRequest("GET", "/v1/nfts/"+contract+"/"+owned.TokenID, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var nftResponse struct {
+			store.NFT
+			Total int64 `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nftResponse))
+		assert.Equal(t, owned.TokenID, nftResponse.TokenID)
+		assert.Contains(t, nftResponse.Owners, owned)
+		assert.Positive(t, nftResponse.Total)
+
+		req = httptest.NewRequest("GET", "/v1/address/"+owned.Owner+"/nfts?limit=100", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var nftsResponse struct {
+			NFTs []store.NFTOwnership `json:"nfts"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nftsResponse))
+		assert.Contains(t, nftsResponse.NFTs, owned)
+	}
+}
+
 func testGetChainStats(t *testing.T, router http.Handler) {
 	req := httptest.NewRequest("GET", "/v1/stats/chain", nil)
 	w := httptest.NewRecorder()
@@ -234,6 +456,42 @@ func testGetChainStats(t *testing.T, router http.Handler) {
 	assert.NotZero(t, stats.LastUpdated)
 }
 
+func testListReorgs(t *testing.T, router http.Handler) {
+	req := httptest.NewRequest("GET", "/v1/reorgs?limit=10", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Reorgs []store.Reorg `json:"reorgs"`
+		Total  int64         `json:"total"`
+		Limit  int           `json:"limit"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, response.Limit)
+	assert.GreaterOrEqual(t, response.Total, int64(len(response.Reorgs)))
+
+	// Fetch the first reorg if any were recorded
+	if len(response.Reorgs) > 0 {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/v1/reorgs/%d", response.Reorgs[0].ID), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var reorg store.Reorg
+		err := json.Unmarshal(w.Body.Bytes(), &reorg)
+		require.NoError(t, err)
+		assert.Equal(t, response.Reorgs[0].ID, reorg.ID)
+		assert.Len(t, reorg.Blocks, reorg.Depth)
+	}
+}
+
 func testPagination(t *testing.T, router http.Handler) {
 	// Test with different pagination parameters
 	tests := []struct {
@@ -289,7 +547,26 @@ func testErrorHandling(t *testing.T, router http.Handler) {
 		{"invalid block hash", "/v1/blocks/0xinvalid", http.StatusBadRequest},
 		{"block not found", "/v1/blocks/999999999", http.StatusNotFound},
 		{"invalid address format", "/v1/address/invalid/txs", http.StatusBadRequest},
+		{"invalid withdrawals address", "/v1/address/invalid/withdrawals", http.StatusBadRequest},
+		{"invalid withdrawals block height", "/v1/blocks/-1/withdrawals", http.StatusBadRequest},
 		{"invalid tx hash", "/v1/txs/invalid", http.StatusBadRequest},
+		{"invalid internal txs hash", "/v1/txs/invalid/internal", http.StatusBadRequest},
+		{"internal txs of unknown tx", "/v1/txs/0x" + strings.Repeat("ab", 32) + "/internal", http.StatusNotFound},
+		{"invalid internal txs address", "/v1/address/invalid/internal", http.StatusBadRequest},
+		{"invalid tokens address", "/v1/address/invalid/tokens", http.StatusBadRequest},
+		{"invalid token address", "/v1/tokens/invalid", http.StatusBadRequest},
+		{"token not found", "/v1/tokens/0x" + strings.Repeat("ab", 20), http.StatusNotFound},
+		{"invalid token holders address", "/v1/tokens/invalid/holders", http.StatusBadRequest},
+		{"invalid token transfers address", "/v1/tokens/invalid/transfers", http.StatusBadRequest},
+		{"invalid nfts address", "/v1/address/invalid/nfts", http.StatusBadRequest},
+		{"invalid collection tokens address", "/v1/nfts/invalid/tokens", http.StatusBadRequest},
+		{"invalid collection transfers address", "/v1/nfts/invalid/transfers", http.StatusBadRequest},
+		{"invalid nft contract address", "/v1/nfts/invalid/1", http.StatusBadRequest},
+		{"invalid nft token id", "/v1/nfts/0x" + strings.Repeat("ab", 20) + "/0x01", http.StatusBadRequest},
+		{"nft token id above uint256", "/v1/nfts/0x" + strings.Repeat("ab", 20) + "/" + strings.Repeat("9", 78), http.StatusBadRequest},
+		{"nft not found", "/v1/nfts/0x" + strings.Repeat("ab", 20) + "/123456789", http.StatusNotFound},
+		{"invalid reorg id", "/v1/reorgs/invalid", http.StatusBadRequest},
+		{"reorg not found", "/v1/reorgs/999999999", http.StatusNotFound},
 	}
 
 	for _, tt := range tests {
@@ -61,13 +61,29 @@ func (s *Server) Router() http.Handler {
 		// Block endpoints
 		r.Get("/blocks", s.handleListBlocks)
 		r.Get("/blocks/{height}/transactions", s.handleGetBlockTransactions) // Must be before generic /{heightOrHash}
+		r.Get("/blocks/{height}/withdrawals", s.handleGetBlockWithdrawals)
 		r.Get("/blocks/{heightOrHash}", s.handleGetBlock)
 
 		// Transaction endpoints
 		r.Get("/txs/{hash}", s.handleGetTransaction)
+		r.Get("/txs/{hash}/internal", s.handleGetTransactionInternalTxs)
 
 		// Address endpoints
 		r.Get("/address/{addr}/txs", s.handleGetAddressTransactions)
+		r.Get("/address/{addr}/withdrawals", s.handleGetAddressWithdrawals)
+		r.Get("/address/{addr}/internal", s.handleGetAddressInternalTxs)
+		r.Get("/address/{addr}/tokens", s.handleGetAddressTokens)
+		r.Get("/address/{addr}/nfts", s.handleGetAddressNFTs)
+
+		// Token endpoints
+		r.Get("/tokens/{token}", s.handleGetToken)
+		r.Get("/tokens/{token}/holders", s.handleGetTokenHolders)
+		r.Get("/tokens/{token}/transfers", s.handleGetTokenTransfers)
+
+		// NFT endpoints
+		r.Get("/nfts/{contract}/tokens", s.handleGetCollectionTokens) // Must be before generic /{tokenId}
+		r.Get("/nfts/{contract}/transfers", s.handleGetCollectionTransfers)
+		r.Get("/nfts/{contract}/{tokenId}", s.handleGetNFT)
 
 		// Logs endpoints
 		r.Get("/logs", s.handleQueryLogs)
@@ -75,6 +91,10 @@ func (s *Server) Router() http.Handler {
 		// Stats endpoints
 		r.Get("/stats/chain", s.handleChainStats)
 
+		// Reorg endpoints
+		r.Get("/reorgs", s.handleListReorgs)
+		r.Get("/reorgs/{id}", s.handleGetReorg)
+
 		// WebSocket endpoint
 		if s.hub != nil {
 			wsConfig := websocket.LoadConfig()
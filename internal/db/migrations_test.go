@@ -72,7 +72,7 @@ func TestRunMigrations_WithConnection_Integration(t *testing.T) {
 	defer pool.Close()
 
 	// Verify tables exist
-	tables := []string{"blocks", "transactions", "logs"}
+	tables := []string{"blocks", "transactions", "logs", "reorgs", "reorg_blocks", "indexer_state", "backfill_failures", "integrity_mismatches", "withdrawals", "internal_transactions", "token_transfers", "token_balances", "nft_transfers", "nft_owners", "tokens"}
 	for _, table := range tables {
 		var exists bool
 		err := pool.QueryRow(ctx,
@@ -84,9 +84,9 @@ func TestRunMigrations_WithConnection_Integration(t *testing.T) {
 		assert.True(t, exists, "table %s should exist after migrations", table)
 	}
 
-	// Verify indexes exist (migration 2)
+	// Verify indexes exist (migrations 2 and 4)
 	indexes := []string{
-		"idx_blocks_orphaned_height",
+		"idx_blocks_canonical_height",
 		"idx_blocks_timestamp",
 		"idx_tx_block_height",
 		"idx_tx_from_addr_block",
@@ -95,6 +95,23 @@ func TestRunMigrations_WithConnection_Integration(t *testing.T) {
 		"idx_logs_tx_hash",
 		"idx_logs_address_topic0",
 		"idx_logs_address",
+		"idx_tx_block_hash",
+		"idx_logs_block_hash",
+		"idx_blocks_not_finalized",
+		"idx_integrity_mismatches_open",
+		"idx_tx_type_block_height",
+		"idx_withdrawals_address_block",
+		"idx_internal_txs_from_addr_block",
+		"idx_internal_txs_to_addr_block",
+		"idx_token_transfers_token_block",
+		"idx_token_transfers_block_hash",
+		"idx_token_balances_token_balance",
+		"idx_token_balances_holder",
+		"idx_nft_transfers_contract_block",
+		"idx_nft_transfers_token_block",
+		"idx_nft_transfers_block_hash",
+		"idx_nft_owners_owner",
+		"idx_tokens_pending",
 	}
 
 	for _, index := range indexes {
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// channelRegex validates Postgres NOTIFY channel names (unquoted identifier, max 63 bytes)
+var channelRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// Config holds configuration for the cross-process event bus
+type Config struct {
+	Channel    string // Postgres NOTIFY channel shared by worker and API
+	BufferSize int    // Events queued in the worker before new events are dropped
+}
+
+// NewConfig creates a new event bus configuration from environment variables
+// Falls back to sensible defaults if env vars are not set
+func NewConfig() (*Config, error) {
+	config := DefaultConfig()
+
+	if channel := os.Getenv("EVENTS_CHANNEL"); channel != "" {
+		config.Channel = channel
+	}
+
+	if bufferSizeStr := os.Getenv("EVENTS_BUFFER_SIZE"); bufferSizeStr != "" {
+		bufferSize, err := strconv.Atoi(bufferSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EVENTS_BUFFER_SIZE: %w", err)
+		}
+		config.BufferSize = bufferSize
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if !channelRegex.MatchString(c.Channel) {
+		return fmt.Errorf("channel must be a lowercase identifier of at most 63 characters, got %q", c.Channel)
+	}
+	if c.BufferSize <= 0 {
+		return fmt.Errorf("buffer_size must be > 0, got %d", c.BufferSize)
+	}
+	return nil
+}
+
+// DefaultConfig returns sensible defaults
+func DefaultConfig() *Config {
+	return &Config{
+		Channel:    "explorer_events",
+		BufferSize: 1024,
+	}
+}
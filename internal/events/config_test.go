@@ -0,0 +1,40 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		channel    string
+		bufferSize string
+		want       *Config
+		wantErr    bool
+	}{
+		{name: "defaults", want: DefaultConfig()},
+		{name: "custom values", channel: "explorer_events_test", bufferSize: "16", want: &Config{Channel: "explorer_events_test", BufferSize: 16}},
+		{name: "invalid channel", channel: "explorer-events", wantErr: true},
+		{name: "uppercase channel", channel: "Events", wantErr: true},
+		{name: "non-numeric buffer size", bufferSize: "abc", wantErr: true},
+		{name: "zero buffer size", bufferSize: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("EVENTS_CHANNEL", tt.channel)
+			t.Setenv("EVENTS_BUFFER_SIZE", tt.bufferSize)
+
+			config, err := NewConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, config)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/api/websocket"
+)
+
+// Event types carried on the bus
+const (
+	EventTypeBlock       = "block"
+	EventTypeTransaction = "tx"
+)
+
+// Event is the JSON payload sent from the worker to the API over NOTIFY
+// Block and transaction data reuse the WebSocket message shapes so the API can forward them as-is
+type Event struct {
+	Type  string                     `json:"type"`
+	Block *websocket.BlockData       `json:"block,omitempty"`
+	Tx    *websocket.TransactionData `json:"tx,omitempty"`
+}
+
+// maxPayloadSize is the Postgres NOTIFY payload limit (8000 bytes by default)
+const maxPayloadSize = 8000
+
+// encodeEvent serializes an event to a NOTIFY payload
+func encodeEvent(event Event) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event: %w", err)
+	}
+	if len(payload) >= maxPayloadSize {
+		return "", fmt.Errorf("event payload too large (%d bytes)", len(payload))
+	}
+	return string(payload), nil
+}
+
+// decodeEvent parses a NOTIFY payload and checks that it carries the data for its type
+func decodeEvent(payload string) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	switch event.Type {
+	case EventTypeBlock:
+		if event.Block == nil {
+			return nil, fmt.Errorf("block event without block data")
+		}
+	case EventTypeTransaction:
+		if event.Tx == nil {
+			return nil, fmt.Errorf("tx event without transaction data")
+		}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/api/websocket"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNotifier records pg_notify calls
+type mockNotifier struct {
+	mu       sync.Mutex
+	channels []string
+	payloads []string
+	err      error
+}
+
+func (m *mockNotifier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return pgconn.CommandTag{}, m.err
+	}
+	m.channels = append(m.channels, args[0].(string))
+	m.payloads = append(m.payloads, args[1].(string))
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func (m *mockNotifier) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.payloads)
+}
+
+// mockBroadcaster records events forwarded by the subscriber
+type mockBroadcaster struct {
+	blocks []websocket.BlockData
+	txs    []websocket.TransactionData
+}
+
+func (m *mockBroadcaster) BroadcastBlock(block websocket.BlockData) {
+	m.blocks = append(m.blocks, block)
+}
+
+func (m *mockBroadcaster) BroadcastTransaction(tx websocket.TransactionData) {
+	m.txs = append(m.txs, tx)
+}
+
+func TestNewPublisher_Validation(t *testing.T) {
+	_, err := NewPublisher(nil, DefaultConfig())
+	assert.Error(t, err)
+
+	_, err = NewPublisher(&mockNotifier{}, nil)
+	assert.Error(t, err)
+
+	_, err = NewPublisher(&mockNotifier{}, &Config{Channel: "events", BufferSize: 0})
+	assert.Error(t, err)
+}
+
+func TestPublisher_PublishesEvents(t *testing.T) {
+	notifier := &mockNotifier{}
+	publisher, err := NewPublisher(notifier, DefaultConfig())
+	require.NoError(t, err)
+
+	publisher.BroadcastBlock(index.BlockData{Height: 100, Hash: "0xabc", TxCount: 1, Timestamp: 1700000000, Miner: "0xminer", GasUsed: 21000})
+	publisher.BroadcastTransaction(index.TransactionData{Hash: "0xtx", FromAddr: "0xfrom", ToAddr: "0xto", ValueWei: "1000", BlockHeight: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publisher.Run(ctx)
+
+	require.Eventually(t, func() bool { return notifier.count() == 2 }, time.Second, 10*time.Millisecond)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	assert.Equal(t, []string{"explorer_events", "explorer_events"}, notifier.channels)
+
+	block, err := decodeEvent(notifier.payloads[0])
+	require.NoError(t, err)
+	assert.Equal(t, EventTypeBlock, block.Type)
+	assert.Equal(t, uint64(100), block.Block.Height)
+	assert.Equal(t, "0xminer", block.Block.Miner)
+
+	tx, err := decodeEvent(notifier.payloads[1])
+	require.NoError(t, err)
+	assert.Equal(t, EventTypeTransaction, tx.Type)
+	assert.Equal(t, "0xfrom", tx.Tx.FromAddr)
+	assert.Equal(t, "1000", tx.Tx.ValueWei)
+}
+
+func TestPublisher_DropsWhenQueueFull(t *testing.T) {
+	publisher, err := NewPublisher(&mockNotifier{}, &Config{Channel: "events", BufferSize: 2})
+	require.NoError(t, err)
+
+	// Run is not started, so the queue fills up
+	for i := 0; i < 5; i++ {
+		publisher.BroadcastBlock(index.BlockData{Height: uint64(i)})
+	}
+
+	stats := publisher.Stats()
+	assert.Equal(t, 2, stats["queued"])
+	assert.Equal(t, uint64(3), stats["dropped"])
+}
+
+func TestPublisher_CountsFailures(t *testing.T) {
+	notifier := &mockNotifier{err: errors.New("connection refused")}
+	publisher, err := NewPublisher(notifier, DefaultConfig())
+	require.NoError(t, err)
+
+	publisher.BroadcastBlock(index.BlockData{Height: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publisher.Run(ctx)
+
+	require.Eventually(t, func() bool { return publisher.Stats()["failed"] == uint64(1) }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(0), publisher.Stats()["published"])
+}
+
+func TestEncodeEvent_PayloadTooLarge(t *testing.T) {
+	_, err := encodeEvent(Event{Type: EventTypeBlock, Block: &websocket.BlockData{Miner: strings.Repeat("a", maxPayloadSize)}})
+	assert.Error(t, err)
+}
+
+func TestDecodeEvent_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "not json", payload: "not json"},
+		{name: "unknown type", payload: `{"type":"receipt"}`},
+		{name: "block without data", payload: `{"type":"block"}`},
+		{name: "tx without data", payload: `{"type":"tx","block":{"height":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeEvent(tt.payload)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSubscriber_Dispatch(t *testing.T) {
+	hub := &mockBroadcaster{}
+	subscriber := &Subscriber{hub: hub, config: DefaultConfig()}
+
+	block := websocket.BlockData{Height: 42, Hash: "0xblock", TxCount: 3}
+	blockPayload, err := encodeEvent(Event{Type: EventTypeBlock, Block: &block})
+	require.NoError(t, err)
+
+	tx := websocket.TransactionData{Hash: "0xtx", BlockHeight: 42}
+	txPayload, err := json.Marshal(Event{Type: EventTypeTransaction, Tx: &tx})
+	require.NoError(t, err)
+
+	subscriber.dispatch(blockPayload)
+	subscriber.dispatch(string(txPayload))
+	subscriber.dispatch(`{"type":"unknown"}`)
+	subscriber.dispatch("garbage")
+
+	assert.Equal(t, []websocket.BlockData{block}, hub.blocks)
+	assert.Equal(t, []websocket.TransactionData{tx}, hub.txs)
+}
+
+func TestNewSubscriber_Validation(t *testing.T) {
+	_, err := NewSubscriber(nil, &mockBroadcaster{}, DefaultConfig())
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/api/websocket"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// publishTimeout bounds a single NOTIFY round trip
+const publishTimeout = 5 * time.Second
+
+// Notifier executes SQL statements (implemented by *pgxpool.Pool)
+type Notifier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Publisher implements index.WebSocketBroadcaster by publishing events with Postgres NOTIFY
+// Events are queued and sent by Run, so broadcasting never blocks the live-tail loop.
+// NOTIFY is not durable: events published while no API process is listening are lost.
+type Publisher struct {
+	notifier Notifier
+	config   *Config
+	queue    chan Event
+
+	published uint64
+	dropped   uint64
+	failed    uint64
+}
+
+// NewPublisher creates a new event publisher
+func NewPublisher(notifier Notifier, config *Config) (*Publisher, error) {
+	if notifier == nil {
+		return nil, fmt.Errorf("notifier cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &Publisher{
+		notifier: notifier,
+		config:   config,
+		queue:    make(chan Event, config.BufferSize),
+	}, nil
+}
+
+// BroadcastBlock queues a new block event
+func (p *Publisher) BroadcastBlock(block index.BlockData) {
+	p.enqueue(Event{
+		Type: EventTypeBlock,
+		Block: &websocket.BlockData{
+			Height:    block.Height,
+			Hash:      block.Hash,
+			TxCount:   block.TxCount,
+			Timestamp: block.Timestamp,
+			Miner:     block.Miner,
+			GasUsed:   block.GasUsed,
+		},
+	})
+}
+
+// BroadcastTransaction queues a new transaction event
+func (p *Publisher) BroadcastTransaction(tx index.TransactionData) {
+	p.enqueue(Event{
+		Type: EventTypeTransaction,
+		Tx: &websocket.TransactionData{
+			Hash:        tx.Hash,
+			FromAddr:    tx.FromAddr,
+			ToAddr:      tx.ToAddr,
+			ValueWei:    tx.ValueWei,
+			BlockHeight: tx.BlockHeight,
+		},
+	})
+}
+
+// enqueue adds an event to the queue, dropping it if the queue is full
+func (p *Publisher) enqueue(event Event) {
+	select {
+	case p.queue <- event:
+	default:
+		atomic.AddUint64(&p.dropped, 1)
+		util.Warn("event queue full, dropping event", "type", event.Type)
+	}
+}
+
+// Run publishes queued events until the context is cancelled
+func (p *Publisher) Run(ctx context.Context) {
+	util.Info("event publisher started", "channel", p.config.Channel)
+
+	for {
+		select {
+		case <-ctx.Done():
+			util.Info("event publisher stopped",
+				"published", atomic.LoadUint64(&p.published),
+				"dropped", atomic.LoadUint64(&p.dropped),
+				"failed", atomic.LoadUint64(&p.failed),
+			)
+			return
+		case event := <-p.queue:
+			if err := p.publish(ctx, event); err != nil {
+				atomic.AddUint64(&p.failed, 1)
+				util.Warn("failed to publish event", "type", event.Type, "error", err.Error())
+				continue
+			}
+			atomic.AddUint64(&p.published, 1)
+		}
+	}
+}
+
+// publish sends a single event with pg_notify
+func (p *Publisher) publish(ctx context.Context, event Event) error {
+	payload, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	if _, err := p.notifier.Exec(publishCtx, "SELECT pg_notify($1, $2)", p.config.Channel, payload); err != nil {
+		return fmt.Errorf("failed to notify channel %s: %w", p.config.Channel, err)
+	}
+
+	return nil
+}
+
+// Stats returns publisher statistics
+func (p *Publisher) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"published": atomic.LoadUint64(&p.published),
+		"dropped":   atomic.LoadUint64(&p.dropped),
+		"failed":    atomic.LoadUint64(&p.failed),
+		"queued":    len(p.queue),
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/api/websocket"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// initialReconnectDelay is the wait before the first reconnect after losing the subscription
+	initialReconnectDelay = 1 * time.Second
+	// maxReconnectDelay caps the exponential reconnect backoff
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Broadcaster receives events forwarded by the subscriber (implemented by *websocket.Hub)
+type Broadcaster interface {
+	BroadcastBlock(block websocket.BlockData)
+	BroadcastTransaction(tx websocket.TransactionData)
+}
+
+// Subscriber listens for worker events with Postgres LISTEN and forwards them to the WebSocket hub
+// It holds one pool connection for as long as it runs and reconnects with backoff if it is lost
+type Subscriber struct {
+	pool   *pgxpool.Pool
+	hub    Broadcaster
+	config *Config
+}
+
+// NewSubscriber creates a new event subscriber
+func NewSubscriber(pool *pgxpool.Pool, hub Broadcaster, config *Config) (*Subscriber, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("pool cannot be nil")
+	}
+	if hub == nil {
+		return nil, fmt.Errorf("hub cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &Subscriber{
+		pool:   pool,
+		hub:    hub,
+		config: config,
+	}, nil
+}
+
+// Run listens for events until the context is cancelled
+func (s *Subscriber) Run(ctx context.Context) {
+	delay := initialReconnectDelay
+
+	for {
+		subscribed, err := s.listen(ctx)
+		if ctx.Err() != nil {
+			util.Info("event subscriber stopped", "channel", s.config.Channel)
+			return
+		}
+
+		// Reset backoff once a subscription was established
+		if subscribed {
+			delay = initialReconnectDelay
+		}
+
+		util.Warn("event subscription lost, reconnecting",
+			"channel", s.config.Channel,
+			"error", err.Error(),
+			"retry_in", delay.String(),
+		)
+
+		select {
+		case <-ctx.Done():
+			util.Info("event subscriber stopped", "channel", s.config.Channel)
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// listen subscribes on a dedicated connection and dispatches notifications until an error occurs
+// Returns whether the LISTEN succeeded
+func (s *Subscriber) listen(ctx context.Context) (bool, error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() {
+		// Stop listening before the connection goes back to the pool (best effort)
+		unlistenCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlistenCtx, "UNLISTEN *"); err != nil {
+			conn.Conn().Close(unlistenCtx)
+		}
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{s.config.Channel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("failed to listen on channel %s: %w", s.config.Channel, err)
+	}
+
+	util.Info("subscribed to worker events", "channel", s.config.Channel)
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		s.dispatch(notification.Payload)
+	}
+}
+
+// dispatch decodes a notification payload and forwards it to the hub
+func (s *Subscriber) dispatch(payload string) {
+	event, err := decodeEvent(payload)
+	if err != nil {
+		util.Warn("ignoring invalid event", "error", err.Error())
+		return
+	}
+
+	switch event.Type {
+	case EventTypeBlock:
+		s.hub.BroadcastBlock(*event.Block)
+	case EventTypeTransaction:
+		s.hub.BroadcastTransaction(*event.Tx)
+	}
+}
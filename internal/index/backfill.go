@@ -3,7 +3,12 @@ package index
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,16 +20,31 @@ type RPCBlockFetcher interface {
 	GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error)
 }
 
+// RPCBatchBlockFetcher is implemented by RPC clients that can fetch several blocks per request
+// Backfill uses it when available to fetch FetchBatchSize heights per JSON-RPC batch
+type RPCBatchBlockFetcher interface {
+	GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error)
+}
+
 // BackfillCoordinator manages parallel block backfilling with worker pool pattern
 type BackfillCoordinator struct {
 	rpcClient RPCBlockFetcher
 	store     BlockStoreExtended
+	ingester  BlockIngester      // Optional block parser (nil: header-only ParseRPCBlockHeader)
+	state     BackfillStateStore // Optional checkpoint and gap store (nil: no checkpoints or gap filling)
 	config    *Config
 
+	// Worker count, tuned from RPC latency and rate limiting
+	concurrency *adaptiveConcurrency
+
 	// Metrics
 	blocksFetched    int64
 	blocksInserted   int64
 	batchesProcessed int64
+	gapsFound        int64
+	heightsRefilled  int64
+	heightsRetried   atomic.Int64 // Updated by workers
+	heightsFailed    atomic.Int64
 	startTime        time.Time
 }
 
@@ -32,6 +52,7 @@ type BackfillCoordinator struct {
 type BlockResult struct {
 	Height   uint64
 	Block    *types.Block
+	Parsed   *Block // Domain block produced by the ingester (or header-only parser)
 	WorkerID int
 	Error    error
 }
@@ -52,12 +73,19 @@ func NewBackfillCoordinator(rpcClient RPCBlockFetcher, store BlockStoreExtended,
 	}
 
 	return &BackfillCoordinator{
-		rpcClient: rpcClient,
-		store:     store,
-		config:    config,
+		rpcClient:   rpcClient,
+		store:       store,
+		config:      config,
+		concurrency: newAdaptiveConcurrency(config),
 	}, nil
 }
 
+// SetIngester sets the block parser used to convert fetched blocks to the domain model
+// Use the same ingester as live-tail so backfilled history contains transactions (and receipts)
+func (bc *BackfillCoordinator) SetIngester(ingester BlockIngester) {
+	bc.ingester = ingester
+}
+
 // Backfill executes the backfill operation with parallel workers
 // Implements AC1 (Worker Pool Architecture), AC2 (Performance), AC3 (Error Handling)
 func (bc *BackfillCoordinator) Backfill(ctx context.Context, startHeight, endHeight uint64) error {
@@ -73,85 +101,90 @@ func (bc *BackfillCoordinator) Backfill(ctx context.Context, startHeight, endHei
 		"start_height", startHeight,
 		"end_height", endHeight,
 		"total_blocks", totalBlocks,
-		"workers", bc.config.Workers,
+		"workers", bc.concurrency.Limit(),
+		"max_workers", bc.concurrency.max,
 		"batch_size", bc.config.BatchSize,
+		"fetch_batch_size", bc.fetchBatchSize(),
 	)
 
 	// Create channels for communication
-	jobQueue := make(chan uint64, bc.config.Workers*2) // Buffered to avoid blocking
-	resultChan := make(chan *BlockResult, bc.config.Workers*2)
-	errorChan := make(chan *WorkerError, 1) // Buffered to non-blocking send
+	// One goroutine per possible worker; the adaptive limit decides how many process jobs at once
+	workers := bc.concurrency.max
+	jobQueue := make(chan backfillJob, workers*2) // Chunks of heights, buffered to avoid blocking
+	resultChan := make(chan *BlockResult, workers*2)
+	failures := &failedHeights{}
 
 	// WaitGroup for coordination
 	var workerWg sync.WaitGroup
 	var collectorWg sync.WaitGroup
-
-	// Track if backfill was halted due to error
-	var haltOnce sync.Once
-	haltBackfill := false
+	var pendingJobs sync.WaitGroup // Jobs not finished yet, including scheduled retries
 
 	// Start worker goroutines
-	workerWg.Add(bc.config.Workers)
-	for i := 0; i < bc.config.Workers; i++ {
-		go bc.worker(ctx, i, &workerWg, jobQueue, resultChan, errorChan, &haltBackfill, &haltOnce)
+	workerWg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go bc.worker(ctx, i, &workerWg, &pendingJobs, jobQueue, resultChan, failures)
 	}
 
 	// Start result collector goroutine
 	collectorWg.Add(1)
-	collectedBlocks := make([]*types.Block, 0, bc.config.BatchSize)
+	collectedBlocks := make([]*Block, 0, bc.config.BatchSize)
 	collectedHeights := make([]uint64, 0, bc.config.BatchSize)
 	go func() {
 		defer collectorWg.Done()
+
+		// flush inserts the collected blocks; heights of a failed batch are dead-lettered and
+		// reported with the other failed heights (processing continues with the next batch)
+		flush := func() {
+			if err := bc.insertBatch(ctx, collectedBlocks); err != nil {
+				util.Error("failed to insert batch",
+					"error", err.Error(),
+					"batch_size", len(collectedBlocks),
+					"batch_count", int(bc.batchesProcessed)+1,
+				)
+				for _, height := range collectedHeights {
+					if ctx.Err() != nil {
+						break // Cancelled run: reported as cancelled, not as failed heights
+					}
+					bc.deadLetter(ctx, FailedHeight{Height: height, Attempts: 1, Err: err}, failures)
+				}
+			} else {
+				util.Debug("batch inserted successfully",
+					"batch_size", len(collectedBlocks),
+					"batch_count", int(bc.batchesProcessed)+1,
+				)
+				bc.batchesProcessed++
+				bc.blocksInserted += int64(len(collectedBlocks))
+				bc.checkpoint(ctx, collectedHeights)
+			}
+			collectedBlocks = collectedBlocks[:0]
+			collectedHeights = collectedHeights[:0]
+		}
+
 		// Collect results and insert into database in batches
 		for result := range resultChan {
 			if result.Error == nil {
-				collectedBlocks = append(collectedBlocks, result.Block)
+				collectedBlocks = append(collectedBlocks, result.Parsed)
 				collectedHeights = append(collectedHeights, result.Height)
 
 				// When batch is full, insert into database
 				if len(collectedBlocks) >= bc.config.BatchSize {
-					if err := bc.insertBatch(ctx, collectedBlocks); err != nil {
-						util.Error("failed to insert batch",
-							"error", err.Error(),
-							"batch_size", len(collectedBlocks),
-							"batch_count", int(bc.batchesProcessed)+1,
-						)
-						// Continue processing despite error (log it but don't halt)
-					} else {
-						util.Debug("batch inserted successfully",
-							"batch_size", len(collectedBlocks),
-							"batch_count", int(bc.batchesProcessed)+1,
-						)
-						bc.batchesProcessed++
-						bc.blocksInserted += int64(len(collectedBlocks))
-					}
-					collectedBlocks = collectedBlocks[:0]
-					collectedHeights = collectedHeights[:0]
+					flush()
 				}
 			}
 		}
 
 		// Flush remaining blocks
 		if len(collectedBlocks) > 0 {
-			if err := bc.insertBatch(ctx, collectedBlocks); err != nil {
-				util.Error("failed to insert final batch",
-					"error", err.Error(),
-					"batch_size", len(collectedBlocks),
-				)
-			} else {
-				util.Debug("final batch inserted successfully",
-					"batch_size", len(collectedBlocks),
-				)
-				bc.batchesProcessed++
-				bc.blocksInserted += int64(len(collectedBlocks))
-			}
+			flush()
 		}
 	}()
 
 	// Send jobs to worker queue
+	distributed := make(chan struct{})
 	go func() {
-		defer close(jobQueue)
-		for height := startHeight; height <= endHeight; height++ {
+		defer close(distributed)
+		chunkSize := uint64(bc.fetchBatchSize())
+		for height := startHeight; height <= endHeight; height += chunkSize {
 			select {
 			case <-ctx.Done():
 				util.Info("context cancelled, stopping job distribution")
@@ -159,52 +192,62 @@ func (bc *BackfillCoordinator) Backfill(ctx context.Context, startHeight, endHei
 			default:
 			}
 
-			// Check if error occurred in workers
-			select {
-			case workerErr := <-errorChan:
-				// Error received from worker - halt backfill
-				util.Error("permanent error from worker, halting backfill",
-					"worker_id", workerErr.WorkerID,
-					"height", workerErr.Height,
-					"error", workerErr.Error.Error(),
-				)
-				haltOnce.Do(func() {
-					haltBackfill = true
-				})
-				return
-			default:
+			// Send chunk to queue (non-blocking; if queue full, this might block slightly)
+			chunkEnd := height + chunkSize - 1
+			if chunkEnd > endHeight || chunkEnd < height {
+				chunkEnd = endHeight
+			}
+			chunk := make([]uint64, 0, chunkEnd-height+1)
+			for h := height; h <= chunkEnd; h++ {
+				chunk = append(chunk, h)
 			}
+			pendingJobs.Add(1)
+			jobQueue <- backfillJob{heights: chunk}
+			bc.blocksFetched += int64(len(chunk))
 
-			// Send job to queue (non-blocking; if queue full, this might block slightly)
-			jobQueue <- height
-			bc.blocksFetched++
+			if chunkEnd == endHeight {
+				return // Avoid overflow when endHeight is near MaxUint64
+			}
 		}
 	}()
 
+	// Close the queue once every chunk was distributed and no job or retry is outstanding
+	go func() {
+		<-distributed
+		pendingJobs.Wait()
+		close(jobQueue)
+	}()
+
 	// Wait for all workers to finish
 	workerWg.Wait()
 	close(resultChan)
 	collectorWg.Wait()
 
-	// Check if there was an error
-	select {
-	case workerErr := <-errorChan:
-		duration := time.Since(bc.startTime)
-		util.Error("backfill failed",
+	duration := time.Since(bc.startTime)
+
+	if ctx.Err() != nil {
+		util.Warn("backfill cancelled",
+			"duration", duration.String(),
+			"blocks_fetched", bc.blocksFetched,
+			"blocks_inserted", bc.blocksInserted,
+		)
+		return fmt.Errorf("backfill cancelled: %w", ctx.Err())
+	}
+
+	// Report every height that still failed after retries
+	if failed := failures.list(); len(failed) > 0 {
+		backfillErr := &BackfillError{Failed: failed}
+		util.Error("backfill completed with failed heights",
 			"duration", duration.String(),
 			"blocks_fetched", bc.blocksFetched,
 			"blocks_inserted", bc.blocksInserted,
-			"worker_id", workerErr.WorkerID,
-			"height", workerErr.Height,
-			"error", workerErr.Error.Error(),
+			"failed_heights", len(failed),
+			"error", backfillErr.Error(),
 		)
-		return fmt.Errorf("backfill failed at height %d (worker %d): %w",
-			workerErr.Height, workerErr.WorkerID, workerErr.Error)
-	default:
+		return backfillErr
 	}
 
 	// Log completion summary
-	duration := time.Since(bc.startTime)
 	throughputPerSec := float64(bc.blocksFetched) / duration.Seconds()
 
 	util.Info("backfill completed successfully",
@@ -218,24 +261,90 @@ func (bc *BackfillCoordinator) Backfill(ctx context.Context, startHeight, endHei
 	return nil
 }
 
-// WorkerError represents an error from a worker with context
-type WorkerError struct {
-	WorkerID int
+// backfillJob is a chunk of heights for a worker, with the number of failed attempts so far
+type backfillJob struct {
+	heights  []uint64
+	attempts int
+}
+
+// FailedHeight is a height that still failed after all retries (dead-lettered)
+type FailedHeight struct {
 	Height   uint64
-	Error    error
+	Attempts int
+	Err      error
+}
+
+// BackfillError reports every height a backfill run could not index
+// All other heights of the run were processed
+type BackfillError struct {
+	Failed []FailedHeight // Ordered by height
+}
+
+// maxReportedHeights limits how many failed heights are listed in BackfillError.Error
+const maxReportedHeights = 20
+
+// Error implements the error interface
+func (e *BackfillError) Error() string {
+	heights := make([]string, 0, maxReportedHeights)
+	for i, f := range e.Failed {
+		if i == maxReportedHeights {
+			heights = append(heights, fmt.Sprintf("... %d more", len(e.Failed)-maxReportedHeights))
+			break
+		}
+		heights = append(heights, strconv.FormatUint(f.Height, 10))
+	}
+
+	first := e.Failed[0]
+	return fmt.Sprintf("backfill failed for %d heights [%s] (height %d after %d attempts: %v)",
+		len(e.Failed), strings.Join(heights, ", "), first.Height, first.Attempts, first.Err)
+}
+
+// Unwrap returns the error of the lowest failed height
+func (e *BackfillError) Unwrap() error {
+	return e.Failed[0].Err
+}
+
+// Heights returns the failed heights in ascending order
+func (e *BackfillError) Heights() []uint64 {
+	heights := make([]uint64, len(e.Failed))
+	for i, f := range e.Failed {
+		heights[i] = f.Height
+	}
+	return heights
+}
+
+// failedHeights collects dead-lettered heights from all workers
+type failedHeights struct {
+	mu     sync.Mutex
+	failed []FailedHeight
+}
+
+func (f *failedHeights) add(failed FailedHeight) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = append(f.failed, failed)
+}
+
+// list returns the failed heights ordered by height
+func (f *failedHeights) list() []FailedHeight {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sort.Slice(f.failed, func(i, j int) bool { return f.failed[i].Height < f.failed[j].Height })
+	return f.failed
 }
 
 // worker is a goroutine that fetches blocks from RPC and sends them to result channel
+// Each job is a chunk of consecutive heights, fetched with one batch request when supported.
+// Heights that fail are re-enqueued with backoff and dead-lettered after MaxRetries retries.
 // Implements AC1, AC2, AC3
 func (bc *BackfillCoordinator) worker(
 	ctx context.Context,
 	workerID int,
 	wg *sync.WaitGroup,
-	jobQueue <-chan uint64,
+	pendingJobs *sync.WaitGroup,
+	jobQueue chan backfillJob,
 	resultChan chan<- *BlockResult,
-	errorChan chan<- *WorkerError,
-	haltFlag *bool,
-	haltOnce *sync.Once,
+	failures *failedHeights,
 ) {
 	defer wg.Done()
 
@@ -243,50 +352,59 @@ func (bc *BackfillCoordinator) worker(
 		"worker_id", workerID,
 	)
 
-	for height := range jobQueue {
-		// Check if halt flag is set
-		if *haltFlag {
-			util.Debug("worker detected halt flag, stopping",
-				"worker_id", workerID,
-			)
-			continue // consume remaining items in queue
+	for {
+		// Wait for a free slot under the current concurrency limit before taking a job
+		bc.concurrency.acquire()
+		job, ok := <-jobQueue
+		if !ok {
+			bc.concurrency.release()
+			break
 		}
 
-		// Fetch block with timeout
-		workerCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		block, err := bc.rpcClient.GetBlockByNumber(workerCtx, height)
-		cancel()
+		if ctx.Err() == nil {
+			bc.processJob(ctx, workerID, job, pendingJobs, jobQueue, resultChan, failures)
+		}
+		// Otherwise consume remaining items in queue
+		pendingJobs.Done()
+		bc.concurrency.release()
+	}
 
-		if err != nil {
-			// Permanent error - halt backfill
-			util.Error("worker encountered error",
-				"worker_id", workerID,
-				"height", height,
-				"error", err.Error(),
-			)
+	util.Debug("worker finished",
+		"worker_id", workerID,
+	)
+}
 
-			workerErr := &WorkerError{
-				WorkerID: workerID,
-				Height:   height,
-				Error:    err,
-			}
+// processJob fetches and parses one chunk, sends the blocks to the collector and
+// re-enqueues (or dead-letters) the heights that failed
+func (bc *BackfillCoordinator) processJob(
+	ctx context.Context,
+	workerID int,
+	job backfillJob,
+	pendingJobs *sync.WaitGroup,
+	jobQueue chan<- backfillJob,
+	resultChan chan<- *BlockResult,
+	failures *failedHeights,
+) {
+	blocks, errs := bc.fetchChunk(ctx, job.heights)
 
-			// Send error (non-blocking; ignore if channel already has error)
-			select {
-			case errorChan <- workerErr:
-			default:
-			}
+	failed := make(map[uint64]error)
+	for i, height := range job.heights {
+		if err, ok := errs[height]; ok {
+			failed[height] = err
+			continue
+		}
 
-			haltOnce.Do(func() {
-				*haltFlag = true
-			})
+		// Parse blocks (ingester may fetch receipts, so it runs in the worker for parallelism)
+		parsed, err := bc.parseBlock(ctx, blocks[i])
+		if err != nil {
+			failed[height] = err
 			continue
 		}
 
-		// Send result to collector
 		result := &BlockResult{
 			Height:   height,
-			Block:    block,
+			Block:    blocks[i],
+			Parsed:   parsed,
 			WorkerID: workerID,
 			Error:    nil,
 		}
@@ -301,9 +419,137 @@ func (bc *BackfillCoordinator) worker(
 		}
 	}
 
-	util.Debug("worker finished",
-		"worker_id", workerID,
+	if len(failed) == 0 || ctx.Err() != nil {
+		return
+	}
+
+	heights := make([]uint64, 0, len(failed))
+	for _, height := range job.heights {
+		if _, ok := failed[height]; ok {
+			heights = append(heights, height)
+		}
+	}
+	attempts := job.attempts + 1
+
+	if job.attempts < bc.config.MaxRetries {
+		delay := retryBackoff(job.attempts, bc.config.RetryDelay)
+		util.Warn("failed to fetch heights, retrying after backoff",
+			"worker_id", workerID,
+			"heights", len(heights),
+			"first_height", heights[0],
+			"attempt", attempts,
+			"backoff_duration", delay.String(),
+			"error", failed[heights[0]].Error(),
+		)
+		bc.heightsRetried.Add(int64(len(heights)))
+
+		// Re-enqueue from a separate goroutine so the worker keeps draining the queue meanwhile
+		pendingJobs.Add(1)
+		go func() {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				jobQueue <- backfillJob{heights: heights, attempts: attempts}
+			case <-ctx.Done():
+				pendingJobs.Done()
+			}
+		}()
+		return
+	}
+
+	for _, height := range heights {
+		bc.deadLetter(ctx, FailedHeight{Height: height, Attempts: attempts, Err: failed[height]}, failures)
+	}
+}
+
+// deadLetter records a height that still failed after all retries
+// The failure is persisted when a state store is set, so it can be inspected and refilled later
+func (bc *BackfillCoordinator) deadLetter(ctx context.Context, failed FailedHeight, failures *failedHeights) {
+	util.Error("height failed after all retries",
+		"height", failed.Height,
+		"attempts", failed.Attempts,
+		"error", failed.Err.Error(),
 	)
+	failures.add(failed)
+	bc.heightsFailed.Add(1)
+
+	if bc.state == nil {
+		return
+	}
+	if err := bc.state.RecordFailedHeight(ctx, failed.Height, failed.Attempts, failed.Err.Error()); err != nil {
+		util.Warn("failed to record failed height",
+			"height", failed.Height,
+			"error", err.Error(),
+		)
+	}
+}
+
+// retryBackoff returns the delay before retry number attempt+1: baseDelay * 2^attempt
+func retryBackoff(attempt int, baseDelay time.Duration) time.Duration {
+	if attempt > 10 {
+		attempt = 10
+	}
+	return baseDelay * time.Duration(1<<uint(attempt))
+}
+
+// fetchChunk fetches a chunk of heights, with one batch request if the RPC client supports it
+// Blocks are returned in request order; heights that could not be fetched are returned in the
+// error map (their block is nil). Requests are bounded by the RPC client's request timeout, which
+// starts once the rate limiter admits them, so waiting for quota does not fail the fetch.
+func (bc *BackfillCoordinator) fetchChunk(ctx context.Context, heights []uint64) ([]*types.Block, map[uint64]error) {
+	errs := make(map[uint64]error)
+
+	batchFetcher, ok := bc.rpcClient.(RPCBatchBlockFetcher)
+	if ok && len(heights) > 1 {
+		fetchStart := time.Now()
+		blocks, err := batchFetcher.GetBlocksByNumber(ctx, heights)
+		bc.concurrency.observe(ctx, time.Since(fetchStart), err)
+		if len(blocks) != len(heights) {
+			// No partial results: the whole request failed
+			if err == nil {
+				err = fmt.Errorf("expected %d blocks, got %d", len(heights), len(blocks))
+			}
+			for _, height := range heights {
+				errs[height] = err
+			}
+			return make([]*types.Block, len(heights)), errs
+		}
+		for i, block := range blocks {
+			if block == nil {
+				if err == nil {
+					err = fmt.Errorf("block %d not returned", heights[i])
+				}
+				errs[heights[i]] = err
+			}
+		}
+		return blocks, errs
+	}
+
+	blocks := make([]*types.Block, len(heights))
+	for i, height := range heights {
+		fetchStart := time.Now()
+		block, err := bc.rpcClient.GetBlockByNumber(ctx, height)
+		bc.concurrency.observe(ctx, time.Since(fetchStart), err)
+		if err != nil {
+			errs[height] = err
+			continue
+		}
+		blocks[i] = block
+	}
+	return blocks, errs
+}
+
+// fetchBatchSize returns the number of heights per fetch job (1 when batching is disabled)
+func (bc *BackfillCoordinator) fetchBatchSize() int {
+	if bc.config.FetchBatchSize <= 1 {
+		return 1
+	}
+	if _, ok := bc.rpcClient.(RPCBatchBlockFetcher); !ok {
+		return 1
+	}
+	return bc.config.FetchBatchSize
 }
 
 // Stats returns backfill statistics
@@ -313,8 +559,13 @@ func (bc *BackfillCoordinator) Stats() map[string]interface{} {
 		"blocks_inserted":    bc.blocksInserted,
 		"batches_processed":  bc.batchesProcessed,
 		"duration":           time.Since(bc.startTime),
-		"workers":            bc.config.Workers,
+		"workers":            bc.concurrency.Limit(),
 		"batch_size":         bc.config.BatchSize,
+		"fetch_batch_size":   bc.fetchBatchSize(),
+		"gaps_found":         bc.gapsFound,
+		"heights_refilled":   bc.heightsRefilled,
+		"heights_retried":    bc.heightsRetried.Load(),
+		"heights_failed":     bc.heightsFailed.Load(),
 	}
 }
 
@@ -323,40 +574,91 @@ func (bc *BackfillCoordinator) BackfillWithConfig(ctx context.Context) error {
 	return bc.Backfill(ctx, bc.config.StartHeight, bc.config.EndHeight)
 }
 
-// insertBatch converts RPC blocks to domain model and inserts them into database
-func (bc *BackfillCoordinator) insertBatch(ctx context.Context, rpcBlocks []*types.Block) error {
-	if len(rpcBlocks) == 0 {
-		return nil
+// parseBlock converts an RPC block to the domain model using the configured ingester
+// Falls back to header-only parsing when no ingester is set
+func (bc *BackfillCoordinator) parseBlock(ctx context.Context, rpcBlock *types.Block) (*Block, error) {
+	if bc.ingester == nil {
+		return ParseRPCBlockHeader(rpcBlock), nil
 	}
 
-	// Insert each block sequentially within the batch
-	// Note: Could be optimized with bulk insert, but this is simpler and still fast enough
-	for _, rpcBlock := range rpcBlocks {
-		// Convert RPC block to domain model
-		block := parseRPCBlockToDomain(rpcBlock)
+	parseCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-		// Insert block into database
-		if err := bc.store.InsertBlock(ctx, block); err != nil {
-			return fmt.Errorf("failed to insert block %d: %w", block.Height, err)
-		}
+	block, err := bc.ingester.ParseBlock(parseCtx, rpcBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block %d: %w", rpcBlock.NumberU64(), err)
+	}
+	return block, nil
+}
+
+// insertBatch inserts parsed domain blocks into database
+// The whole batch is written with a single bulk insert, so a failure leaves none of it committed
+func (bc *BackfillCoordinator) insertBatch(ctx context.Context, blocks []*Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	if err := bc.store.InsertBlocks(ctx, blocks); err != nil {
+		return fmt.Errorf("failed to insert blocks %d-%d: %w",
+			blocks[0].Height, blocks[len(blocks)-1].Height, err)
 	}
 
 	return nil
 }
 
-// parseRPCBlockToDomain converts ethereum RPC block to index.Block domain model
-func parseRPCBlockToDomain(rpcBlock *types.Block) *Block {
+// ParseRPCBlockHeader converts ethereum RPC block to index.Block domain model
+// Fills the header and withdrawals; transactions are left to the ingester (see store.ParseRPCBlock)
+func ParseRPCBlockHeader(rpcBlock *types.Block) *Block {
 	if rpcBlock == nil {
 		return nil
 	}
 
-	return &Block{
-		Height:     rpcBlock.NumberU64(),
-		Hash:       rpcBlock.Hash().Bytes(),
-		ParentHash: rpcBlock.ParentHash().Bytes(),
-		Timestamp:  rpcBlock.Time(),
-		Miner:      rpcBlock.Coinbase().Bytes(),
-		GasUsed:    rpcBlock.GasUsed(),
-		TxCount:    len(rpcBlock.Transactions()),
+	header := rpcBlock.Header()
+	block := &Block{
+		Height:           rpcBlock.NumberU64(),
+		Hash:             rpcBlock.Hash().Bytes(),
+		ParentHash:       rpcBlock.ParentHash().Bytes(),
+		Timestamp:        rpcBlock.Time(),
+		Miner:            rpcBlock.Coinbase().Bytes(),
+		GasUsed:          rpcBlock.GasUsed(),
+		TxCount:          len(rpcBlock.Transactions()),
+		GasLimit:         header.GasLimit,
+		Difficulty:       new(big.Int),
+		Nonce:            header.Nonce[:],
+		ExtraData:        append([]byte{}, header.Extra...), // blocks.extra_data is NOT NULL
+		StateRoot:        header.Root.Bytes(),
+		TransactionsRoot: header.TxHash.Bytes(),
+		ReceiptsRoot:     header.ReceiptHash.Bytes(),
+		LogsBloom:        header.Bloom.Bytes(),
+		MixHash:          header.MixDigest.Bytes(),
+		UncleHash:        header.UncleHash.Bytes(),
+		Size:             rpcBlock.Size(),
+		BlobGasUsed:      header.BlobGasUsed,
+		ExcessBlobGas:    header.ExcessBlobGas,
+	}
+
+	// Fork-specific fields stay nil for blocks before the fork that introduced them
+	if header.Difficulty != nil {
+		block.Difficulty.Set(header.Difficulty)
 	}
+	if header.BaseFee != nil {
+		block.BaseFeePerGas = new(big.Int).Set(header.BaseFee)
+	}
+	if header.WithdrawalsHash != nil {
+		block.WithdrawalsRoot = header.WithdrawalsHash.Bytes()
+		block.Withdrawals = make([]Withdrawal, 0, len(rpcBlock.Withdrawals()))
+		for _, w := range rpcBlock.Withdrawals() {
+			block.Withdrawals = append(block.Withdrawals, Withdrawal{
+				Index:          w.Index,
+				ValidatorIndex: w.Validator,
+				Address:        w.Address.Bytes(),
+				AmountGwei:     w.Amount,
+			})
+		}
+	}
+	if header.ParentBeaconRoot != nil {
+		block.ParentBeaconRoot = header.ParentBeaconRoot.Bytes()
+	}
+
+	return block
 }
@@ -13,6 +13,23 @@ type Config struct {
 	BatchSize int
 	StartHeight uint64
 	EndHeight   uint64
+	// FetchBatchSize is the number of heights fetched per JSON-RPC batch request
+	// (0 or 1 disables batching; only used when the RPC client supports batches)
+	FetchBatchSize int
+	// GapScanInterval is how often missing heights below the head are detected and refilled (0 disables)
+	GapScanInterval time.Duration
+	// MaxRetries is how many times a failed height is re-enqueued before it is dead-lettered (0 disables retries)
+	MaxRetries int
+	// RetryDelay is the base backoff before a failed height is retried (doubled on every attempt)
+	RetryDelay time.Duration
+	// MinWorkers and MaxWorkers bound the adaptive worker count; Workers is the initial count
+	// (MaxWorkers 0 disables adaptive concurrency and keeps Workers fixed)
+	MinWorkers int
+	MaxWorkers int
+	// TargetLatency is the RPC request latency above which workers are removed (0 only reacts to rate limits)
+	TargetLatency time.Duration
+	// AdjustInterval is how often the worker count is re-evaluated
+	AdjustInterval time.Duration
 }
 
 // NewConfig creates a new backfill configuration from environment variables
@@ -28,6 +45,45 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("BACKFILL_BATCH_SIZE must be > 0, got %d", batchSize)
 	}
 
+	fetchBatchSize := getEnvInt("BACKFILL_FETCH_BATCH_SIZE", 10)
+	if fetchBatchSize <= 0 {
+		return nil, fmt.Errorf("BACKFILL_FETCH_BATCH_SIZE must be > 0, got %d", fetchBatchSize)
+	}
+
+	gapScanInterval, err := getEnvDuration("BACKFILL_GAP_SCAN_INTERVAL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetries := getEnvInt("BACKFILL_MAX_RETRIES", 3)
+	if maxRetries < 0 {
+		return nil, fmt.Errorf("BACKFILL_MAX_RETRIES must be >= 0, got %d", maxRetries)
+	}
+
+	retryDelay, err := getEnvDuration("BACKFILL_RETRY_DELAY", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	minWorkers := getEnvInt("BACKFILL_WORKERS_MIN", 1)
+	maxWorkers := getEnvInt("BACKFILL_WORKERS_MAX", 0)
+	if maxWorkers < 0 {
+		return nil, fmt.Errorf("BACKFILL_WORKERS_MAX must be >= 0, got %d", maxWorkers)
+	}
+	if maxWorkers > 0 && (minWorkers <= 0 || minWorkers > workers || workers > maxWorkers) {
+		return nil, fmt.Errorf("BACKFILL_WORKERS_MIN (%d) <= BACKFILL_WORKERS (%d) <= BACKFILL_WORKERS_MAX (%d) required",
+			minWorkers, workers, maxWorkers)
+	}
+
+	targetLatency, err := getEnvDuration("BACKFILL_TARGET_LATENCY", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	adjustInterval, err := getEnvDuration("BACKFILL_ADJUST_INTERVAL", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
 	startHeight := getEnvUint64("BACKFILL_START_HEIGHT", 0)
 	endHeight := getEnvUint64("BACKFILL_END_HEIGHT", 5000)
 
@@ -37,10 +93,18 @@ func NewConfig() (*Config, error) {
 	}
 
 	return &Config{
-		Workers:     workers,
-		BatchSize:   batchSize,
-		StartHeight: startHeight,
-		EndHeight:   endHeight,
+		Workers:         workers,
+		BatchSize:       batchSize,
+		StartHeight:     startHeight,
+		EndHeight:       endHeight,
+		FetchBatchSize:  fetchBatchSize,
+		GapScanInterval: gapScanInterval,
+		MaxRetries:      maxRetries,
+		RetryDelay:      retryDelay,
+		MinWorkers:      minWorkers,
+		MaxWorkers:      maxWorkers,
+		TargetLatency:   targetLatency,
+		AdjustInterval:  adjustInterval,
 	}, nil
 }
 
@@ -52,6 +116,29 @@ func (c *Config) Validate() error {
 	if c.BatchSize <= 0 {
 		return fmt.Errorf("batch_size must be > 0, got %d", c.BatchSize)
 	}
+	if c.FetchBatchSize < 0 {
+		return fmt.Errorf("fetch_batch_size must be >= 0, got %d", c.FetchBatchSize)
+	}
+	if c.GapScanInterval < 0 {
+		return fmt.Errorf("gap_scan_interval must be >= 0, got %v", c.GapScanInterval)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be >= 0, got %d", c.MaxRetries)
+	}
+	if c.RetryDelay < 0 {
+		return fmt.Errorf("retry_delay must be >= 0, got %v", c.RetryDelay)
+	}
+	if c.MaxWorkers < 0 {
+		return fmt.Errorf("max_workers must be >= 0, got %d", c.MaxWorkers)
+	}
+	if c.MaxWorkers > 0 && (c.MinWorkers > c.Workers || c.Workers > c.MaxWorkers) {
+		return fmt.Errorf("min_workers (%d) <= workers (%d) <= max_workers (%d) required",
+			c.MinWorkers, c.Workers, c.MaxWorkers)
+	}
+	if c.TargetLatency < 0 || c.AdjustInterval < 0 {
+		return fmt.Errorf("target_latency and adjust_interval must be >= 0, got %v and %v",
+			c.TargetLatency, c.AdjustInterval)
+	}
 	if c.StartHeight >= c.EndHeight {
 		return fmt.Errorf("start_height (%d) must be < end_height (%d)",
 			c.StartHeight, c.EndHeight)
@@ -90,6 +177,19 @@ func getEnvUint64(key string, defaultVal uint64) uint64 {
 	return val
 }
 
+// getEnvDuration gets a non-negative duration environment variable with a default value
+func getEnvDuration(key string, defaultVal time.Duration) (time.Duration, error) {
+	valStr := os.Getenv(key)
+	if valStr == "" {
+		return defaultVal, nil
+	}
+	val, err := time.ParseDuration(valStr)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration, got %q", key, valStr)
+	}
+	return val, nil
+}
+
 // TimeoutConfig holds timeout configuration
 type TimeoutConfig struct {
 	// Overall timeout for the entire backfill operation
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -43,9 +46,10 @@ func NewMockRPCClient() *MockRPCClient {
 
 // MockStore implements BlockStoreExtended for testing
 type MockStore struct {
-	blocks       map[uint64]*Block
-	insertCalls  int
-	insertErrors map[uint64]error
+	blocks           map[uint64]*Block
+	insertCalls      int
+	insertBatchCalls int
+	insertErrors     map[uint64]error
 }
 
 func NewMockStore() *MockStore {
@@ -84,6 +88,16 @@ func (m *MockStore) InsertBlock(ctx context.Context, block *Block) error {
 	return nil
 }
 
+func (m *MockStore) InsertBlocks(ctx context.Context, blocks []*Block) error {
+	m.insertBatchCalls++
+	for _, block := range blocks {
+		if err := m.InsertBlock(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *MockStore) MarkBlocksOrphaned(ctx context.Context, startHeight, endHeight uint64) error {
 	for h := startHeight; h <= endHeight; h++ {
 		if block, ok := m.blocks[h]; ok {
@@ -188,7 +202,7 @@ func TestBackfillCoordinator_HappyPath_SmallDataset(t *testing.T) {
 
 // Test AC3: Error Handling and Resilience
 // Note: Detailed error handling is tested through AC2 performance tests
-// Errors from RPC are handled via the error channel
+// Failed heights are retried and dead-lettered (see TestBackfillCoordinator_DeadLettersFailedHeights)
 func TestBackfillCoordinator_WorkerResilience(t *testing.T) {
 	// Test that multiple workers can process in parallel
 	mockRPC := NewMockRPCClient()
@@ -425,6 +439,8 @@ func TestBackfillCoordinator_BatchCollection(t *testing.T) {
 	// 25 blocks / 5 batch size = 5 batches
 	assert.Equal(t, int64(5), coordinator.batchesProcessed)
 	assert.Equal(t, int64(25), coordinator.blocksInserted)
+	// Each batch is written with a single bulk insert
+	assert.Equal(t, 5, mockStore.insertBatchCalls)
 }
 
 // Test Configuration Validation Errors
@@ -613,3 +629,374 @@ func TestBackfillCoordinator_LongRunning(t *testing.T) {
 	t.Logf("Backfilled 5000 blocks in %v", duration)
 	t.Logf("Throughput: %.2f blocks/second", float64(5000)/duration.Seconds())
 }
+
+// mockIngester attaches one synthetic transaction to every parsed block
+type mockIngester struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (m *mockIngester) ParseBlock(ctx context.Context, rpcBlock *types.Block) (*Block, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+	block := ParseRPCBlockHeader(rpcBlock)
+	block.Transactions = []Transaction{{Hash: []byte{byte(block.Height)}, TxIndex: 0}}
+	return block, nil
+}
+
+// Test that backfill routes blocks through the configured ingester
+func TestBackfillCoordinator_UsesIngester(t *testing.T) {
+	mockRPC := NewMockRPCClient()
+
+	startHeight := uint64(0)
+	endHeight := uint64(9)
+	for h := startHeight; h <= endHeight; h++ {
+		mockRPC.blockCache[h] = generateTestBlock(h)
+	}
+
+	config := &Config{
+		Workers:     3,
+		BatchSize:   4,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+
+	mockStore := NewMockStore()
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	ingester := &mockIngester{}
+	coordinator.SetIngester(ingester)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, startHeight, endHeight)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, ingester.calls)
+	for h := startHeight; h <= endHeight; h++ {
+		require.Contains(t, mockStore.blocks, h)
+		assert.Len(t, mockStore.blocks[h].Transactions, 1, "block %d should carry transactions", h)
+	}
+}
+
+// Test that an ingester failure is reported like an RPC failure
+func TestBackfillCoordinator_IngesterError(t *testing.T) {
+	mockRPC := NewMockRPCClient()
+	for h := uint64(0); h <= 4; h++ {
+		mockRPC.blockCache[h] = generateTestBlock(h)
+	}
+
+	config := &Config{Workers: 2, BatchSize: 2, StartHeight: 0, EndHeight: 4}
+	coordinator, err := NewBackfillCoordinator(mockRPC, NewMockStore(), config)
+	require.NoError(t, err)
+	coordinator.SetIngester(&mockIngester{err: fmt.Errorf("receipts unavailable")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 4)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "receipts unavailable")
+}
+
+// mockBatchRPCClient adds batch block fetching to MockRPCClient
+type mockBatchRPCClient struct {
+	*MockRPCClient
+	mu         sync.Mutex
+	batchCalls [][]uint64
+	failHeight *uint64
+}
+
+func (m *mockBatchRPCClient) GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	m.mu.Lock()
+	m.batchCalls = append(m.batchCalls, append([]uint64(nil), heights...))
+	m.mu.Unlock()
+
+	blocks := make([]*types.Block, len(heights))
+	for i, h := range heights {
+		if m.failHeight != nil && h == *m.failHeight {
+			return blocks, fmt.Errorf("batch item %d failed", i)
+		}
+		blocks[i] = m.blockCache[h]
+	}
+	return blocks, nil
+}
+
+// Test that backfill fetches chunks of heights with batch requests when supported
+func TestBackfillCoordinator_BatchFetch(t *testing.T) {
+	mockRPC := &mockBatchRPCClient{MockRPCClient: NewMockRPCClient()}
+	for h := uint64(0); h <= 24; h++ {
+		mockRPC.blockCache[h] = generateTestBlock(h)
+	}
+
+	config := &Config{Workers: 3, BatchSize: 10, StartHeight: 0, EndHeight: 24, FetchBatchSize: 10}
+	mockStore := NewMockStore()
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 24)
+	require.NoError(t, err)
+
+	assert.Len(t, mockRPC.batchCalls, 3, "25 heights should be fetched in 3 batches")
+	seen := make(map[uint64]bool)
+	for _, chunk := range mockRPC.batchCalls {
+		assert.LessOrEqual(t, len(chunk), 10)
+		for _, h := range chunk {
+			assert.False(t, seen[h], "height %d fetched twice", h)
+			seen[h] = true
+		}
+	}
+	for h := uint64(0); h <= 24; h++ {
+		assert.Contains(t, mockStore.blocks, h)
+	}
+	assert.Equal(t, int64(25), coordinator.Stats()["blocks_fetched"])
+}
+
+// Test that a failed batch item is reported by height without halting the run
+func TestBackfillCoordinator_BatchFetchError(t *testing.T) {
+	failHeight := uint64(7)
+	mockRPC := &mockBatchRPCClient{MockRPCClient: NewMockRPCClient(), failHeight: &failHeight}
+	for h := uint64(0); h <= 9; h++ {
+		mockRPC.blockCache[h] = generateTestBlock(h)
+	}
+
+	config := &Config{Workers: 2, BatchSize: 5, StartHeight: 0, EndHeight: 9, FetchBatchSize: 5}
+	coordinator, err := NewBackfillCoordinator(mockRPC, NewMockStore(), config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 9)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "height 7")
+
+	var backfillErr *BackfillError
+	require.ErrorAs(t, err, &backfillErr)
+	assert.Contains(t, backfillErr.Heights(), uint64(7))
+	assert.NotContains(t, backfillErr.Heights(), uint64(2), "heights of other chunks are not affected")
+}
+
+// Test that batching is disabled for clients without batch support
+func TestBackfillCoordinator_FetchBatchSize_NoBatchSupport(t *testing.T) {
+	config := &Config{Workers: 1, BatchSize: 1, StartHeight: 0, EndHeight: 1, FetchBatchSize: 10}
+
+	coordinator, err := NewBackfillCoordinator(NewMockRPCClient(), NewMockStore(), config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, coordinator.fetchBatchSize())
+
+	batchCoordinator, err := NewBackfillCoordinator(&mockBatchRPCClient{MockRPCClient: NewMockRPCClient()}, NewMockStore(), config)
+	require.NoError(t, err)
+	assert.Equal(t, 10, batchCoordinator.fetchBatchSize())
+}
+
+// flakyRPCClient fails each height a configured number of times before serving it
+type flakyRPCClient struct {
+	*MockRPCClient
+	mu       sync.Mutex
+	failures map[uint64]int // Remaining failures per height (-1 fails forever)
+	calls    map[uint64]int
+	err      error // Returned for failing heights (default: generic unavailable error)
+}
+
+func newFlakyRPCClient(maxHeight uint64, failures map[uint64]int) *flakyRPCClient {
+	if failures == nil {
+		failures = make(map[uint64]int)
+	}
+	client := &flakyRPCClient{MockRPCClient: NewMockRPCClient(), failures: failures, calls: make(map[uint64]int)}
+	for h := uint64(0); h <= maxHeight; h++ {
+		client.blockCache[h] = generateTestBlock(h)
+	}
+	return client
+}
+
+func (f *flakyRPCClient) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	f.mu.Lock()
+	f.calls[height]++
+	remaining := f.failures[height]
+	if remaining > 0 {
+		f.failures[height]--
+	}
+	f.mu.Unlock()
+
+	if remaining != 0 {
+		if f.err != nil {
+			return nil, f.err
+		}
+		return nil, fmt.Errorf("rpc unavailable for height %d", height)
+	}
+	return f.MockRPCClient.GetBlockByNumber(ctx, height)
+}
+
+// Test that failed heights are re-enqueued with backoff until they succeed
+func TestBackfillCoordinator_RetriesFailedHeights(t *testing.T) {
+	mockRPC := newFlakyRPCClient(9, map[uint64]int{3: 2, 8: 1})
+
+	config := &Config{Workers: 2, BatchSize: 4, StartHeight: 0, EndHeight: 9, MaxRetries: 3, RetryDelay: time.Millisecond}
+	mockStore := NewMockStore()
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 9)
+	require.NoError(t, err)
+
+	for h := uint64(0); h <= 9; h++ {
+		assert.Contains(t, mockStore.blocks, h)
+	}
+	assert.Equal(t, 3, mockRPC.calls[3])
+	assert.Equal(t, 2, mockRPC.calls[8])
+
+	stats := coordinator.Stats()
+	assert.Equal(t, int64(3), stats["heights_retried"])
+	assert.Equal(t, int64(0), stats["heights_failed"])
+}
+
+// Test that heights failing every retry are dead-lettered while the rest of the run completes
+func TestBackfillCoordinator_DeadLettersFailedHeights(t *testing.T) {
+	mockRPC := newFlakyRPCClient(19, map[uint64]int{5: -1, 12: -1})
+
+	config := &Config{Workers: 3, BatchSize: 4, StartHeight: 0, EndHeight: 19, MaxRetries: 2, RetryDelay: time.Millisecond}
+	mockStore := NewMockStore()
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+	state := &mockStateStore{store: mockStore}
+	coordinator.SetStateStore(state)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 19)
+	require.Error(t, err)
+
+	var backfillErr *BackfillError
+	require.ErrorAs(t, err, &backfillErr)
+	assert.Equal(t, []uint64{5, 12}, backfillErr.Heights())
+	assert.Equal(t, 3, backfillErr.Failed[0].Attempts)
+	assert.Contains(t, err.Error(), "[5, 12]")
+	assert.Contains(t, err.Error(), "rpc unavailable for height 5")
+
+	for h := uint64(0); h <= 19; h++ {
+		if h == 5 || h == 12 {
+			assert.NotContains(t, mockStore.blocks, h)
+			continue
+		}
+		assert.Contains(t, mockStore.blocks, h)
+	}
+	assert.Equal(t, 3, mockRPC.calls[5], "one attempt plus MaxRetries retries")
+	assert.Equal(t, map[uint64]int{5: 3, 12: 3}, state.failed)
+	assert.Equal(t, int64(2), coordinator.Stats()["heights_failed"])
+}
+
+func TestBackfillError_Error(t *testing.T) {
+	backfillErr := &BackfillError{}
+	for h := uint64(100); h < 125; h++ {
+		backfillErr.Failed = append(backfillErr.Failed, FailedHeight{Height: h, Attempts: 4, Err: fmt.Errorf("timeout")})
+	}
+
+	msg := backfillErr.Error()
+	assert.Contains(t, msg, "backfill failed for 25 heights")
+	assert.Contains(t, msg, "119, ... 5 more]")
+	assert.NotContains(t, msg, "120")
+	assert.Contains(t, msg, "(height 100 after 4 attempts: timeout)")
+}
+
+func TestNewConfig_Retries(t *testing.T) {
+	envKeys := []string{"BACKFILL_MAX_RETRIES", "BACKFILL_RETRY_DELAY"}
+	saved := make(map[string]string)
+	for _, key := range envKeys {
+		saved[key] = os.Getenv(key)
+		os.Unsetenv(key)
+	}
+	defer func() {
+		for key, val := range saved {
+			os.Setenv(key, val)
+		}
+	}()
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 3, config.MaxRetries)
+	assert.Equal(t, 2*time.Second, config.RetryDelay)
+
+	os.Setenv("BACKFILL_MAX_RETRIES", "0")
+	os.Setenv("BACKFILL_RETRY_DELAY", "500ms")
+	config, err = NewConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.MaxRetries)
+	assert.Equal(t, 500*time.Millisecond, config.RetryDelay)
+
+	os.Setenv("BACKFILL_MAX_RETRIES", "-1")
+	_, err = NewConfig()
+	assert.Error(t, err)
+
+	os.Setenv("BACKFILL_MAX_RETRIES", "1")
+	os.Setenv("BACKFILL_RETRY_DELAY", "soon")
+	_, err = NewConfig()
+	assert.Error(t, err)
+}
+
+// Test that the full header is parsed, with fork-specific fields only when present
+func TestParseRPCBlockHeader(t *testing.T) {
+	withdrawalsRoot := common.HexToHash("0x01")
+	beaconRoot := common.HexToHash("0x02")
+	blobGasUsed, excessBlobGas := uint64(131072), uint64(0)
+	header := &types.Header{
+		Number:           big.NewInt(100),
+		GasLimit:         30000000,
+		GasUsed:          21000,
+		BaseFee:          big.NewInt(7),
+		Difficulty:       big.NewInt(0),
+		Extra:            []byte("builder"),
+		Root:             common.HexToHash("0x03"),
+		MixDigest:        common.HexToHash("0x04"),
+		WithdrawalsHash:  &withdrawalsRoot,
+		BlobGasUsed:      &blobGasUsed,
+		ExcessBlobGas:    &excessBlobGas,
+		ParentBeaconRoot: &beaconRoot,
+	}
+	withdrawals := types.Withdrawals{
+		{Index: 10, Validator: 500, Address: common.HexToAddress("0x05"), Amount: 32000000000},
+	}
+	rpcBlock := types.NewBlockWithHeader(header).WithBody(types.Body{Withdrawals: withdrawals})
+
+	block := ParseRPCBlockHeader(rpcBlock)
+	assert.Equal(t, uint64(30000000), block.GasLimit)
+	assert.Equal(t, big.NewInt(7), block.BaseFeePerGas)
+	assert.Equal(t, 0, block.Difficulty.Sign())
+	assert.Equal(t, []byte("builder"), block.ExtraData)
+	assert.Equal(t, header.Root.Bytes(), block.StateRoot)
+	assert.Equal(t, header.MixDigest.Bytes(), block.MixHash)
+	assert.Len(t, block.Nonce, 8)
+	assert.Len(t, block.LogsBloom, types.BloomByteLength)
+	assert.Equal(t, rpcBlock.Size(), block.Size)
+	assert.Equal(t, withdrawalsRoot.Bytes(), block.WithdrawalsRoot)
+	assert.Equal(t, []Withdrawal{{Index: 10, ValidatorIndex: 500, Address: common.HexToAddress("0x05").Bytes(), AmountGwei: 32000000000}},
+		block.Withdrawals)
+	assert.Equal(t, &blobGasUsed, block.BlobGasUsed)
+	assert.Equal(t, &excessBlobGas, block.ExcessBlobGas)
+	assert.Equal(t, beaconRoot.Bytes(), block.ParentBeaconRoot)
+
+	// A pre-London block has none of the fork fields
+	legacy := ParseRPCBlockHeader(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(131072)}))
+	assert.Equal(t, big.NewInt(131072), legacy.Difficulty)
+	assert.Equal(t, []byte{}, legacy.ExtraData, "empty extra data is stored as empty, not NULL")
+	assert.Nil(t, legacy.BaseFeePerGas)
+	assert.Nil(t, legacy.WithdrawalsRoot)
+	assert.Nil(t, legacy.Withdrawals)
+	assert.Nil(t, legacy.BlobGasUsed)
+	assert.Nil(t, legacy.ParentBeaconRoot)
+}
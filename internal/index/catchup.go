@@ -0,0 +1,137 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// RPCHeadFetcher is implemented by RPC clients that can report the latest chain height
+// Live-tail uses it to decide whether it is far enough behind to catch up in parallel
+type RPCHeadFetcher interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// prefetchResult is a fetched and parsed block waiting for its turn to be committed
+type prefetchResult struct {
+	block *Block
+	err   error
+}
+
+// prefetchToHead catches up in rounds while the database is more than CatchUpDistance blocks
+// behind the head: each round fetches up to CatchUpWindow heights with CatchUpWorkers concurrent
+// requests and commits them strictly in height order, checking every parent hash against the
+// previous block. Returns once within CatchUpDistance of the head (single-block tailing takes
+// over), or when a block is missing or a reorg is detected.
+func (ltc *LiveTailCoordinator) prefetchToHead(ctx context.Context) error {
+	if ltc.config.CatchUpWorkers <= 1 {
+		return nil
+	}
+
+	for ctx.Err() == nil {
+		head := ltc.latestHead(ctx)
+		dbHead, err := ltc.store.GetLatestBlock(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		if head <= dbHead.Height+ltc.config.CatchUpDistance {
+			return nil
+		}
+
+		count := head - dbHead.Height - ltc.config.CatchUpDistance
+		if window := uint64(max(ltc.config.CatchUpWindow, ltc.config.CatchUpWorkers)); count > window {
+			count = window
+		}
+
+		ltc.logger.Info("catching up in parallel",
+			slog.Uint64("from_height", dbHead.Height+1),
+			slog.Uint64("to_height", dbHead.Height+count),
+			slog.Uint64("chain_head", head),
+			slog.Int("workers", ltc.config.CatchUpWorkers),
+		)
+
+		committed, err := ltc.prefetchRange(ctx, dbHead, count)
+		if err != nil {
+			return err
+		}
+		if committed < count {
+			// Missing block or reorg: leave the rest to the next head signal
+			return nil
+		}
+	}
+	return nil
+}
+
+// prefetchRange fetches the count heights above parent concurrently and commits them in order
+// Returns the number of blocks committed; stops at the first error, missing block or reorg
+func (ltc *LiveTailCoordinator) prefetchRange(ctx context.Context, parent *Block, count uint64) (uint64, error) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	startHeight := parent.Height + 1
+
+	// One buffered slot per height, so workers never block on an abandoned round
+	slots := make([]chan prefetchResult, count)
+	jobs := make(chan uint64, count)
+	for i := range slots {
+		slots[i] = make(chan prefetchResult, 1)
+		jobs <- uint64(i)
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < ltc.config.CatchUpWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if fetchCtx.Err() != nil {
+					slots[i] <- prefetchResult{err: fetchCtx.Err()}
+					continue
+				}
+				block, err := ltc.fetchBlock(fetchCtx, startHeight+i)
+				slots[i] <- prefetchResult{block: block, err: err}
+			}
+		}()
+	}
+	// Cancel the round before waiting, so workers abandon in-flight fetches and drain the remaining jobs
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	var committed uint64
+	for i := range slots {
+		result := <-slots[i]
+		if result.err != nil {
+			return committed, result.err
+		}
+		if result.block == nil {
+			return committed, nil
+		}
+
+		inserted, err := ltc.commitBlock(ctx, parent, result.block)
+		if err != nil || !inserted {
+			return committed, err
+		}
+		parent = result.block
+		committed++
+	}
+	return committed, nil
+}
+
+// latestHead returns the chain head height from the RPC client if it can report it,
+// otherwise the last height seen from the head source (0 if unknown)
+func (ltc *LiveTailCoordinator) latestHead(ctx context.Context) uint64 {
+	if headFetcher, ok := ltc.rpcClient.(RPCHeadFetcher); ok {
+		head, err := headFetcher.BlockNumber(ctx)
+		if err == nil {
+			atomic.StoreInt64(&ltc.chainHead, int64(head))
+			return head
+		}
+		ltc.logger.Warn("failed to get chain head",
+			slog.String("error", err.Error()),
+		)
+	}
+	return uint64(atomic.LoadInt64(&ltc.chainHead))
+}
@@ -0,0 +1,164 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// adaptiveConcurrency bounds how many backfill workers fetch at once and tunes the bound with AIMD
+// Every AdjustInterval the RPC requests observed since the last adjustment are evaluated:
+// any rate-limit error halves the worker count, an average latency above TargetLatency
+// reduces it by a quarter, otherwise one worker is added. The count stays within
+// [MinWorkers, MaxWorkers]; with MaxWorkers unset it is fixed at Workers.
+type adaptiveConcurrency struct {
+	min           int
+	max           int
+	targetLatency time.Duration // 0 = only react to rate limiting
+	interval      time.Duration
+	now           func() time.Time
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+
+	// Observations since the last adjustment
+	samples      int
+	totalLatency time.Duration
+	rateLimited  int
+	lastAdjust   time.Time
+}
+
+// newAdaptiveConcurrency creates the concurrency controller for a backfill config
+func newAdaptiveConcurrency(config *Config) *adaptiveConcurrency {
+	c := &adaptiveConcurrency{
+		min:           config.Workers,
+		max:           config.Workers,
+		targetLatency: config.TargetLatency,
+		interval:      config.AdjustInterval,
+		now:           time.Now,
+		limit:         config.Workers,
+	}
+	if config.MaxWorkers > 0 {
+		c.min = config.MinWorkers
+		if c.min <= 0 {
+			c.min = 1
+		}
+		c.max = config.MaxWorkers
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.lastAdjust = c.now()
+
+	backfillWorkers.Set(float64(c.limit))
+	return c
+}
+
+// adaptive reports whether the worker count is tuned at runtime
+func (c *adaptiveConcurrency) adaptive() bool {
+	return c.max > c.min
+}
+
+// Limit returns the current number of workers allowed to fetch concurrently
+func (c *adaptiveConcurrency) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// acquire blocks until the worker may process a job
+func (c *adaptiveConcurrency) acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.active >= c.limit {
+		c.cond.Wait()
+	}
+	c.active++
+}
+
+// release frees the slot taken by acquire
+func (c *adaptiveConcurrency) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+	c.cond.Signal()
+}
+
+// observe records the outcome of one RPC request and adjusts the limit once the interval has passed
+// Requests cancelled by the caller are ignored
+func (c *adaptiveConcurrency) observe(ctx context.Context, latency time.Duration, err error) {
+	if !c.adaptive() || ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples++
+	c.totalLatency += latency
+	if isThrottled(err) {
+		c.rateLimited++
+	}
+
+	if c.now().Sub(c.lastAdjust) >= c.interval {
+		c.adjust()
+	}
+}
+
+// adjust applies one AIMD step from the current observation window (caller holds mu)
+func (c *adaptiveConcurrency) adjust() {
+	avgLatency := c.totalLatency / time.Duration(c.samples)
+	rateLimited := c.rateLimited
+
+	limit := c.limit
+	reason := "healthy"
+	switch {
+	case rateLimited > 0:
+		limit = c.limit / 2
+		reason = "rate_limited"
+	case c.targetLatency > 0 && avgLatency > c.targetLatency:
+		limit = c.limit - max(1, c.limit/4)
+		reason = "high_latency"
+	default:
+		limit = c.limit + 1
+	}
+	limit = min(max(limit, c.min), c.max)
+
+	c.samples = 0
+	c.totalLatency = 0
+	c.rateLimited = 0
+	c.lastAdjust = c.now()
+
+	if limit == c.limit {
+		return
+	}
+
+	direction := "increase"
+	if limit < c.limit {
+		direction = "decrease"
+	}
+	util.Info("backfill concurrency adjusted",
+		"workers", limit,
+		"previous_workers", c.limit,
+		"reason", reason,
+		"avg_latency_ms", avgLatency.Milliseconds(),
+		"rate_limited_requests", rateLimited,
+	)
+
+	c.limit = limit
+	backfillWorkers.Set(float64(limit))
+	backfillWorkerAdjustments.WithLabelValues(direction).Inc()
+	c.cond.Broadcast()
+}
+
+// isThrottled reports whether the provider pushed back on a request (rate limit or open circuit breaker)
+func isThrottled(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return rpc.IsRateLimit(err) || rpc.IsCircuitOpen(err)
+}
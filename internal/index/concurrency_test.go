@@ -0,0 +1,173 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConcurrency creates a controller driven by a fake clock
+func newTestConcurrency(config *Config) (*adaptiveConcurrency, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newAdaptiveConcurrency(config)
+	c.now = func() time.Time { return now }
+	c.lastAdjust = now
+	return c, &now
+}
+
+var errRateLimited = &rpc.RPCError{Type: rpc.ErrRateLimit, Message: "max retries exceeded", Err: fmt.Errorf("429 Too Many Requests")}
+
+func TestAdaptiveConcurrency_FixedWithoutMaxWorkers(t *testing.T) {
+	c := newAdaptiveConcurrency(&Config{Workers: 4})
+	assert.False(t, c.adaptive())
+
+	c.observe(context.Background(), time.Minute, errRateLimited)
+	assert.Equal(t, 4, c.Limit())
+}
+
+func TestAdaptiveConcurrency_AIMD(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConcurrency(&Config{Workers: 8, MinWorkers: 2, MaxWorkers: 10, TargetLatency: time.Second})
+
+	// Additive increase while healthy, capped at MaxWorkers
+	c.observe(ctx, 100*time.Millisecond, nil)
+	assert.Equal(t, 9, c.Limit())
+	c.observe(ctx, 100*time.Millisecond, nil)
+	c.observe(ctx, 100*time.Millisecond, nil)
+	assert.Equal(t, 10, c.Limit())
+
+	// High latency removes a quarter of the workers
+	c.observe(ctx, 3*time.Second, nil)
+	assert.Equal(t, 8, c.Limit())
+
+	// Rate limiting halves, down to MinWorkers
+	c.observe(ctx, 100*time.Millisecond, errRateLimited)
+	assert.Equal(t, 4, c.Limit())
+	c.observe(ctx, 100*time.Millisecond, fmt.Errorf("wrapped: %w", errRateLimited))
+	assert.Equal(t, 2, c.Limit())
+	c.observe(ctx, 100*time.Millisecond, errRateLimited)
+	assert.Equal(t, 2, c.Limit())
+
+	// Other errors are not a throttling signal
+	c.observe(ctx, 100*time.Millisecond, fmt.Errorf("connection reset"))
+	assert.Equal(t, 3, c.Limit())
+}
+
+func TestAdaptiveConcurrency_AdjustInterval(t *testing.T) {
+	ctx := context.Background()
+	c, now := newTestConcurrency(&Config{Workers: 4, MinWorkers: 1, MaxWorkers: 8, AdjustInterval: 10 * time.Second})
+
+	// One throttled request in the window decides the step
+	c.observe(ctx, 100*time.Millisecond, nil)
+	c.observe(ctx, 100*time.Millisecond, errRateLimited)
+	assert.Equal(t, 4, c.Limit(), "no adjustment before the interval has passed")
+
+	*now = now.Add(10 * time.Second)
+	c.observe(ctx, 100*time.Millisecond, nil)
+	assert.Equal(t, 2, c.Limit())
+
+	// Cancelled requests are ignored
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	*now = now.Add(10 * time.Second)
+	c.observe(cancelled, time.Minute, errRateLimited)
+	assert.Equal(t, 2, c.Limit())
+}
+
+func TestAdaptiveConcurrency_AcquireRespectsLimit(t *testing.T) {
+	c := newAdaptiveConcurrency(&Config{Workers: 1, MinWorkers: 1, MaxWorkers: 2})
+
+	c.acquire()
+	acquired := make(chan struct{})
+	go func() {
+		c.acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second worker admitted above the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Raising the limit admits the waiting worker
+	c.observe(context.Background(), time.Millisecond, nil)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("waiting worker not admitted after increase")
+	}
+	c.release()
+	c.release()
+}
+
+// Test that backfill sheds workers when the provider rate-limits and still indexes every height
+func TestBackfillCoordinator_AdaptiveConcurrency(t *testing.T) {
+	mockRPC := newFlakyRPCClient(29, nil)
+	mockRPC.err = errRateLimited
+	for h := uint64(0); h <= 29; h += 3 {
+		mockRPC.failures[h] = 1
+	}
+
+	config := &Config{
+		Workers:     4,
+		BatchSize:   5,
+		StartHeight: 0,
+		EndHeight:   29,
+		MaxRetries:  2,
+		MinWorkers:  1,
+		MaxWorkers:  8,
+	}
+	mockStore := NewMockStore()
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	decreases := testutil.ToFloat64(backfillWorkerAdjustments.WithLabelValues("decrease"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = coordinator.Backfill(ctx, 0, 29)
+	require.NoError(t, err)
+
+	for h := uint64(0); h <= 29; h++ {
+		assert.Contains(t, mockStore.blocks, h)
+	}
+	assert.Greater(t, testutil.ToFloat64(backfillWorkerAdjustments.WithLabelValues("decrease")), decreases)
+	assert.Equal(t, float64(coordinator.Stats()["workers"].(int)), testutil.ToFloat64(backfillWorkers))
+}
+
+func TestNewConfig_AdaptiveWorkers(t *testing.T) {
+	t.Setenv("BACKFILL_WORKERS", "")
+	t.Setenv("BACKFILL_WORKERS_MIN", "")
+	t.Setenv("BACKFILL_WORKERS_MAX", "")
+	t.Setenv("BACKFILL_TARGET_LATENCY", "")
+	t.Setenv("BACKFILL_ADJUST_INTERVAL", "")
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 1, config.MinWorkers)
+	assert.Zero(t, config.MaxWorkers, "adaptive concurrency is opt-in")
+	assert.Equal(t, 2*time.Second, config.TargetLatency)
+	assert.Equal(t, 10*time.Second, config.AdjustInterval)
+
+	t.Setenv("BACKFILL_WORKERS_MAX", "32")
+	config, err = NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 32, config.MaxWorkers)
+
+	t.Setenv("BACKFILL_WORKERS_MAX", "4")
+	_, err = NewConfig()
+	assert.Error(t, err, "initial workers above the maximum")
+
+	t.Setenv("BACKFILL_WORKERS_MAX", "")
+	t.Setenv("BACKFILL_TARGET_LATENCY", "fast")
+	_, err = NewConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// Block finality statuses, from the post-merge "safe" and "finalized" block tags
+const (
+	FinalityLatest    = "latest"    // Above the safe head, may still be reorged
+	FinalitySafe      = "safe"      // At or below the safe head, reorg unlikely
+	FinalityFinalized = "finalized" // At or below the finalized checkpoint, never reorged
+)
+
+// ErrFinalizedReorg is returned when a reorg would orphan blocks at or below the finalized height
+var ErrFinalizedReorg = errors.New("reorg would orphan finalized blocks")
+
+// FinalityFetcher reports the latest safe and finalized block heights (implemented by the RPC layer)
+type FinalityFetcher interface {
+	FinalityHeights(ctx context.Context) (safe uint64, finalized uint64, err error)
+}
+
+// FinalityStore persists per-block finality (implemented by the storage layer)
+type FinalityStore interface {
+	// UpdateFinality marks canonical blocks at or below finalized as finalized and
+	// those in (finalized, safe] as safe
+	UpdateFinality(ctx context.Context, safe, finalized uint64) error
+	// GetFinalizedHeight returns the highest canonical block marked finalized (0 if none)
+	GetFinalizedHeight(ctx context.Context) (uint64, error)
+}
+
+// FinalitySource provides the finalized height the reorg handler must not reorg below
+type FinalitySource interface {
+	FinalizedHeight() uint64
+}
+
+// FinalityTracker polls the safe and finalized heights and records them per block
+// The finalized height never moves backwards, so a lagging provider cannot un-finalize blocks.
+type FinalityTracker struct {
+	rpcClient FinalityFetcher
+	store     FinalityStore
+	config    *FinalityConfig
+
+	safe      atomic.Uint64
+	finalized atomic.Uint64
+}
+
+// NewFinalityTracker creates a new finality tracker with the provided configuration
+func NewFinalityTracker(rpcClient FinalityFetcher, store FinalityStore, config *FinalityConfig) (*FinalityTracker, error) {
+	if rpcClient == nil {
+		return nil, fmt.Errorf("rpcClient cannot be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &FinalityTracker{
+		rpcClient: rpcClient,
+		store:     store,
+		config:    config,
+	}, nil
+}
+
+// Load restores the finalized height from the database so reorgs are guarded before the first poll
+func (ft *FinalityTracker) Load(ctx context.Context) error {
+	height, err := ft.store.GetFinalizedHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load finalized height: %w", err)
+	}
+	ft.advance(height, height)
+	return nil
+}
+
+// Run polls the safe and finalized heights until the context is cancelled
+// Does nothing when PollInterval is 0.
+func (ft *FinalityTracker) Run(ctx context.Context) {
+	if ft.config.PollInterval <= 0 {
+		return
+	}
+
+	util.Info("finality tracker started",
+		"interval", ft.config.PollInterval.String(),
+	)
+
+	ticker := time.NewTicker(ft.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ft.Update(ctx); err != nil && ctx.Err() == nil {
+			util.Warn("finality update failed",
+				"error", err.Error(),
+			)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			util.Info("finality tracker stopped")
+			return
+		}
+	}
+}
+
+// Update fetches the current safe and finalized heights and records them in the database
+// Blocks indexed since the last update are marked as well, so it runs on every poll.
+func (ft *FinalityTracker) Update(ctx context.Context) error {
+	safe, finalized, err := ft.rpcClient.FinalityHeights(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch finality heights: %w", err)
+	}
+
+	previous := ft.finalized.Load()
+	safe, finalized = ft.advance(safe, finalized)
+
+	if err := ft.store.UpdateFinality(ctx, safe, finalized); err != nil {
+		return fmt.Errorf("failed to update block finality: %w", err)
+	}
+
+	if finalized > previous {
+		util.Debug("finalized height advanced",
+			"finalized_height", finalized,
+			"safe_height", safe,
+			"previous_finalized_height", previous,
+		)
+	}
+	return nil
+}
+
+// advance raises the tracked heights, ignoring values below the current ones
+// Returns the resulting safe and finalized heights (safe is never below finalized)
+func (ft *FinalityTracker) advance(safe, finalized uint64) (uint64, uint64) {
+	for {
+		current := ft.finalized.Load()
+		if finalized <= current {
+			finalized = current
+			break
+		}
+		if ft.finalized.CompareAndSwap(current, finalized) {
+			break
+		}
+	}
+	safe = max(safe, finalized)
+	for {
+		current := ft.safe.Load()
+		if safe <= current {
+			safe = current
+			break
+		}
+		if ft.safe.CompareAndSwap(current, safe) {
+			break
+		}
+	}
+
+	finalitySafeHeight.Set(float64(safe))
+	finalityFinalizedHeight.Set(float64(finalized))
+	return safe, finalized
+}
+
+// FinalizedHeight returns the latest known finalized height (0 before the first update)
+func (ft *FinalityTracker) FinalizedHeight() uint64 {
+	return ft.finalized.Load()
+}
+
+// SafeHeight returns the latest known safe height (0 before the first update)
+func (ft *FinalityTracker) SafeHeight() uint64 {
+	return ft.safe.Load()
+}
+
+// Stats returns finality tracker statistics for observability
+func (ft *FinalityTracker) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"safe_height":      ft.SafeHeight(),
+		"finalized_height": ft.FinalizedHeight(),
+		"poll_interval":    ft.config.PollInterval.String(),
+	}
+}
@@ -0,0 +1,47 @@
+package index
+
+import (
+	"fmt"
+	"time"
+)
+
+// FinalityConfig holds configuration for the finality tracker
+type FinalityConfig struct {
+	// PollInterval is how often the safe and finalized heights are fetched (0 disables tracking)
+	PollInterval time.Duration
+}
+
+// NewFinalityConfig creates a new finality configuration from environment variables
+// Falls back to sensible defaults if env vars are not set
+func NewFinalityConfig() (*FinalityConfig, error) {
+	pollInterval, err := getEnvDuration("FINALITY_POLL_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &FinalityConfig{
+		PollInterval: pollInterval,
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *FinalityConfig) Validate() error {
+	if c.PollInterval < 0 {
+		return fmt.Errorf("poll_interval must be >= 0, got %v", c.PollInterval)
+	}
+	return nil
+}
+
+// DefaultFinalityConfig returns sensible defaults for finality tracking
+// Finalized checkpoints advance once per epoch (~6.4 minutes), the safe head every slot
+func DefaultFinalityConfig() *FinalityConfig {
+	return &FinalityConfig{
+		PollInterval: 30 * time.Second,
+	}
+}
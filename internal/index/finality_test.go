@@ -0,0 +1,101 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFinalityFetcher implements FinalityFetcher for testing
+type mockFinalityFetcher struct {
+	safe      uint64
+	finalized uint64
+	err       error
+}
+
+func (m *mockFinalityFetcher) FinalityHeights(ctx context.Context) (uint64, uint64, error) {
+	return m.safe, m.finalized, m.err
+}
+
+// mockFinalityStore implements FinalityStore for testing
+type mockFinalityStore struct {
+	updates   [][2]uint64 // safe, finalized
+	finalized uint64
+	err       error
+}
+
+func (m *mockFinalityStore) UpdateFinality(ctx context.Context, safe, finalized uint64) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.updates = append(m.updates, [2]uint64{safe, finalized})
+	return nil
+}
+
+func (m *mockFinalityStore) GetFinalizedHeight(ctx context.Context) (uint64, error) {
+	return m.finalized, m.err
+}
+
+func TestNewFinalityTracker(t *testing.T) {
+	_, err := NewFinalityTracker(nil, &mockFinalityStore{}, DefaultFinalityConfig())
+	assert.Error(t, err)
+	_, err = NewFinalityTracker(&mockFinalityFetcher{}, nil, DefaultFinalityConfig())
+	assert.Error(t, err)
+	_, err = NewFinalityTracker(&mockFinalityFetcher{}, &mockFinalityStore{}, nil)
+	assert.Error(t, err)
+	_, err = NewFinalityTracker(&mockFinalityFetcher{}, &mockFinalityStore{}, &FinalityConfig{PollInterval: -time.Second})
+	assert.Error(t, err)
+}
+
+func TestFinalityTracker_Update(t *testing.T) {
+	fetcher := &mockFinalityFetcher{safe: 150, finalized: 120}
+	store := &mockFinalityStore{}
+	tracker, err := NewFinalityTracker(fetcher, store, DefaultFinalityConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Update(context.Background()))
+	assert.Equal(t, uint64(150), tracker.SafeHeight())
+	assert.Equal(t, uint64(120), tracker.FinalizedHeight())
+
+	// A provider lagging behind does not move finality backwards
+	fetcher.safe, fetcher.finalized = 130, 100
+	require.NoError(t, tracker.Update(context.Background()))
+	assert.Equal(t, uint64(120), tracker.FinalizedHeight())
+	assert.Equal(t, [][2]uint64{{150, 120}, {150, 120}}, store.updates)
+
+	// Errors leave the tracked heights unchanged
+	fetcher.err = errors.New("finalized block not found")
+	assert.Error(t, tracker.Update(context.Background()))
+	assert.Equal(t, uint64(120), tracker.FinalizedHeight())
+	assert.Len(t, store.updates, 2)
+}
+
+func TestFinalityTracker_Load(t *testing.T) {
+	store := &mockFinalityStore{finalized: 500}
+	tracker, err := NewFinalityTracker(&mockFinalityFetcher{}, store, DefaultFinalityConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.Load(context.Background()))
+	assert.Equal(t, uint64(500), tracker.FinalizedHeight())
+	assert.Equal(t, uint64(500), tracker.SafeHeight())
+}
+
+func TestFinalityConfig_NewConfig(t *testing.T) {
+	t.Setenv("FINALITY_POLL_INTERVAL", "")
+	config, err := NewFinalityConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, config.PollInterval)
+
+	t.Setenv("FINALITY_POLL_INTERVAL", "0")
+	config, err = NewFinalityConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.PollInterval)
+
+	t.Setenv("FINALITY_POLL_INTERVAL", "soon")
+	_, err = NewFinalityConfig()
+	assert.Error(t, err)
+}
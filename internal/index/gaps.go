@@ -0,0 +1,238 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// gapScanHeadMargin is the number of heights below the database head left out of periodic gap
+// scans; live-tail and reorg handling own the tip of the chain
+const gapScanHeadMargin = 12
+
+// HeightRange is an inclusive range of block heights
+type HeightRange struct {
+	Start uint64
+	End   uint64
+}
+
+// Count returns the number of heights in the range
+func (r HeightRange) Count() uint64 {
+	return r.End - r.Start + 1
+}
+
+// GroupHeights groups heights into ranges of consecutive heights, in ascending order
+// The input may be unsorted and contain duplicates; it is not modified.
+func GroupHeights(heights []uint64) []HeightRange {
+	sorted := append([]uint64(nil), heights...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var ranges []HeightRange
+	for _, h := range sorted {
+		if n := len(ranges); n > 0 && h <= ranges[n-1].End+1 {
+			if h > ranges[n-1].End {
+				ranges[n-1].End = h
+			}
+			continue
+		}
+		ranges = append(ranges, HeightRange{Start: h, End: h})
+	}
+	return ranges
+}
+
+// BackfillStateStore persists completed backfill ranges and failed heights, and finds heights missing
+// from blocks (implemented by the storage layer on the indexer_state, backfill_failures and blocks tables)
+type BackfillStateStore interface {
+	// SaveCompletedRange records that every height in [startHeight, endHeight] is indexed
+	SaveCompletedRange(ctx context.Context, startHeight, endHeight uint64) error
+	// GetCompletedRanges returns the recorded ranges, merged and ordered by start height
+	GetCompletedRanges(ctx context.Context) ([]HeightRange, error)
+	// FindMissingRanges returns the ranges of [startHeight, endHeight] without a canonical block
+	FindMissingRanges(ctx context.Context, startHeight, endHeight uint64) ([]HeightRange, error)
+	// RecordFailedHeight adds a height that still failed after all retries to the dead-letter list
+	RecordFailedHeight(ctx context.Context, height uint64, attempts int, lastErr string) error
+}
+
+// SetStateStore sets the store used to checkpoint completed ranges and scan for gaps
+// Without a state store backfill neither records progress nor refills gaps
+func (bc *BackfillCoordinator) SetStateStore(state BackfillStateStore) {
+	bc.state = state
+}
+
+// ResumeHeight returns the first height at or after startHeight that is not covered by the
+// completed ranges contiguous from startHeight (startHeight itself when nothing is recorded)
+func (bc *BackfillCoordinator) ResumeHeight(ctx context.Context, startHeight uint64) (uint64, error) {
+	if bc.state == nil {
+		return startHeight, nil
+	}
+
+	ranges, err := bc.state.GetCompletedRanges(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get completed ranges: %w", err)
+	}
+
+	resume := startHeight
+	for _, r := range ranges {
+		if r.Start > resume {
+			break
+		}
+		if r.End >= resume {
+			resume = r.End + 1
+		}
+	}
+	return resume, nil
+}
+
+// FillGaps backfills every height of [startHeight, endHeight] missing from the database
+// Once all gaps are filled the whole range is checkpointed as completed.
+// Returns the number of heights that were refilled.
+func (bc *BackfillCoordinator) FillGaps(ctx context.Context, startHeight, endHeight uint64) (uint64, error) {
+	if bc.state == nil {
+		return 0, fmt.Errorf("gap filling requires a state store")
+	}
+	if startHeight > endHeight {
+		return 0, nil
+	}
+
+	gaps, err := bc.state.FindMissingRanges(ctx, startHeight, endHeight)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find missing heights %d-%d: %w", startHeight, endHeight, err)
+	}
+
+	var missing uint64
+	for _, gap := range gaps {
+		missing += gap.Count()
+	}
+	bc.gapsFound += int64(len(gaps))
+
+	if len(gaps) > 0 {
+		util.Warn("missing block heights detected, refilling",
+			"start_height", startHeight,
+			"end_height", endHeight,
+			"gaps", len(gaps),
+			"missing_heights", missing,
+			"first_gap_start", gaps[0].Start,
+			"first_gap_end", gaps[0].End,
+		)
+	}
+
+	var filled uint64
+	for _, gap := range gaps {
+		if err := bc.Backfill(ctx, gap.Start, gap.End); err != nil {
+			// Dead-lettered heights stay missing; keep refilling the other gaps
+			var backfillErr *BackfillError
+			if !errors.As(err, &backfillErr) {
+				return filled, fmt.Errorf("failed to refill heights %d-%d: %w", gap.Start, gap.End, err)
+			}
+			filled += gap.Count() - uint64(len(backfillErr.Failed))
+			continue
+		}
+		filled += gap.Count()
+	}
+	bc.heightsRefilled += int64(filled)
+
+	// Backfill logs failed inserts without halting, so only checkpoint a range that is now complete
+	remaining, err := bc.state.FindMissingRanges(ctx, startHeight, endHeight)
+	if err != nil {
+		return filled, fmt.Errorf("failed to verify heights %d-%d: %w", startHeight, endHeight, err)
+	}
+	if len(remaining) > 0 {
+		return filled, fmt.Errorf("%d gaps remain in heights %d-%d (first %d-%d)",
+			len(remaining), startHeight, endHeight, remaining[0].Start, remaining[0].End)
+	}
+
+	if err := bc.state.SaveCompletedRange(ctx, startHeight, endHeight); err != nil {
+		return filled, fmt.Errorf("failed to checkpoint heights %d-%d: %w", startHeight, endHeight, err)
+	}
+
+	if filled > 0 {
+		util.Info("missing block heights refilled",
+			"start_height", startHeight,
+			"end_height", endHeight,
+			"heights_refilled", filled,
+		)
+	}
+	return filled, nil
+}
+
+// RunGapScanner periodically refills gaps between the end of the completed ranges contiguous
+// from the configured start height and the database head (minus a small margin left to live-tail)
+// until the context is cancelled. Every successful scan is checkpointed, so each scan only covers
+// heights indexed since the previous one.
+// Does nothing when GapScanInterval is 0 or no state store is set.
+func (bc *BackfillCoordinator) RunGapScanner(ctx context.Context) {
+	if bc.config.GapScanInterval <= 0 || bc.state == nil {
+		return
+	}
+
+	util.Info("gap scanner started",
+		"interval", bc.config.GapScanInterval.String(),
+		"start_height", bc.config.StartHeight,
+	)
+
+	ticker := time.NewTicker(bc.config.GapScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			bc.scanOnce(ctx)
+		case <-ctx.Done():
+			util.Info("gap scanner stopped")
+			return
+		}
+	}
+}
+
+// scanOnce runs one periodic gap scan, logging failures (the next scan retries)
+func (bc *BackfillCoordinator) scanOnce(ctx context.Context) {
+	latest, err := bc.store.GetLatestBlock(ctx)
+	if err != nil || latest == nil || latest.Height < bc.config.StartHeight+gapScanHeadMargin {
+		return // Nothing indexed below the margin yet
+	}
+	endHeight := latest.Height - gapScanHeadMargin
+
+	// Heights covered by completed ranges were verified before; only scan past them
+	startHeight, err := bc.ResumeHeight(ctx, bc.config.StartHeight)
+	if err != nil {
+		util.Error("gap scan failed",
+			"start_height", bc.config.StartHeight,
+			"error", err.Error(),
+		)
+		return
+	}
+	if startHeight > endHeight {
+		return
+	}
+
+	if _, err := bc.FillGaps(ctx, startHeight, endHeight); err != nil && ctx.Err() == nil {
+		util.Error("gap scan failed",
+			"start_height", startHeight,
+			"end_height", endHeight,
+			"error", err.Error(),
+		)
+	}
+}
+
+// checkpoint records the heights of an inserted batch as completed ranges
+// Batches may hold non-consecutive heights (workers finish out of order), so every run of
+// consecutive heights is recorded separately; failures are logged since gap scans recover them.
+func (bc *BackfillCoordinator) checkpoint(ctx context.Context, heights []uint64) {
+	if bc.state == nil || len(heights) == 0 {
+		return
+	}
+
+	for _, r := range GroupHeights(heights) {
+		if err := bc.state.SaveCompletedRange(ctx, r.Start, r.End); err != nil {
+			util.Warn("failed to checkpoint backfill range",
+				"start_height", r.Start,
+				"end_height", r.End,
+				"error", err.Error(),
+			)
+		}
+	}
+}
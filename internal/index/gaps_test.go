@@ -0,0 +1,277 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockStateStore implements BackfillStateStore over a MockStore
+type mockStateStore struct {
+	mu        sync.Mutex
+	store     *MockStore
+	completed []HeightRange
+	failed    map[uint64]int // Attempts per dead-lettered height
+	saveErr   error
+}
+
+func (m *mockStateStore) SaveCompletedRange(ctx context.Context, startHeight, endHeight uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.completed = append(m.completed, HeightRange{Start: startHeight, End: endHeight})
+	return nil
+}
+
+func (m *mockStateStore) GetCompletedRanges(ctx context.Context) ([]HeightRange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var heights []uint64
+	for _, r := range m.completed {
+		for h := r.Start; h <= r.End; h++ {
+			heights = append(heights, h)
+		}
+	}
+	return GroupHeights(heights), nil
+}
+
+func (m *mockStateStore) FindMissingRanges(ctx context.Context, startHeight, endHeight uint64) ([]HeightRange, error) {
+	var missing []uint64
+	for h := startHeight; h <= endHeight; h++ {
+		if _, ok := m.store.blocks[h]; !ok {
+			missing = append(missing, h)
+		}
+	}
+	return GroupHeights(missing), nil
+}
+
+func (m *mockStateStore) RecordFailedHeight(ctx context.Context, height uint64, attempts int, lastErr string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failed == nil {
+		m.failed = make(map[uint64]int)
+	}
+	m.failed[height] += attempts
+	return nil
+}
+
+// newGapTestCoordinator creates a coordinator whose RPC mock serves heights [0, maxHeight]
+func newGapTestCoordinator(t *testing.T, maxHeight uint64) (*BackfillCoordinator, *MockStore, *mockStateStore) {
+	mockRPC := NewMockRPCClient()
+	for h := uint64(0); h <= maxHeight; h++ {
+		mockRPC.blockCache[h] = generateTestBlock(h)
+	}
+
+	mockStore := NewMockStore()
+	config := &Config{Workers: 2, BatchSize: 3, StartHeight: 0, EndHeight: maxHeight}
+	coordinator, err := NewBackfillCoordinator(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	state := &mockStateStore{store: mockStore}
+	coordinator.SetStateStore(state)
+	return coordinator, mockStore, state
+}
+
+func TestBackfillCoordinator_CheckpointsInsertedBatches(t *testing.T) {
+	coordinator, _, state := newGapTestCoordinator(t, 9)
+
+	err := coordinator.Backfill(context.Background(), 0, 9)
+	require.NoError(t, err)
+
+	completed, err := state.GetCompletedRanges(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []HeightRange{{Start: 0, End: 9}}, completed)
+}
+
+func TestBackfillCoordinator_FailedBatchNotCheckpointed(t *testing.T) {
+	coordinator, mockStore, state := newGapTestCoordinator(t, 9)
+	mockStore.insertErrors[4] = fmt.Errorf("insert failed")
+
+	err := coordinator.Backfill(context.Background(), 0, 9)
+
+	// Every height of the failed batch is reported and dead-lettered; the other batches are still processed
+	var backfillErr *BackfillError
+	require.ErrorAs(t, err, &backfillErr)
+	assert.Contains(t, backfillErr.Heights(), uint64(4))
+	assert.Len(t, backfillErr.Failed, 3, "one batch of BatchSize heights")
+	assert.Contains(t, err.Error(), "insert failed")
+	assert.Equal(t, 1, state.failed[4])
+
+	completed, err := state.GetCompletedRanges(context.Background())
+	require.NoError(t, err)
+	for _, r := range completed {
+		assert.False(t, r.Start <= 4 && 4 <= r.End, "height 4 must not be checkpointed: %v", completed)
+	}
+}
+
+func TestBackfillCoordinator_ResumeHeight(t *testing.T) {
+	coordinator, _, state := newGapTestCoordinator(t, 1)
+	ctx := context.Background()
+
+	resume, err := coordinator.ResumeHeight(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), resume)
+
+	state.completed = []HeightRange{{Start: 0, End: 99}, {Start: 100, End: 149}, {Start: 200, End: 300}}
+
+	resume, err = coordinator.ResumeHeight(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(150), resume)
+
+	resume, err = coordinator.ResumeHeight(ctx, 250)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(301), resume)
+
+	resume, err = coordinator.ResumeHeight(ctx, 170)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(170), resume)
+}
+
+func TestBackfillCoordinator_FillGaps(t *testing.T) {
+	coordinator, mockStore, state := newGapTestCoordinator(t, 20)
+	ctx := context.Background()
+
+	// Heights 0-20 indexed except 3-5, 11 and 20
+	for h := uint64(0); h <= 20; h++ {
+		if (h >= 3 && h <= 5) || h == 11 || h == 20 {
+			continue
+		}
+		mockStore.blocks[h] = ParseRPCBlockHeader(generateTestBlock(h))
+	}
+
+	filled, err := coordinator.FillGaps(ctx, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), filled)
+
+	for h := uint64(0); h <= 20; h++ {
+		assert.Contains(t, mockStore.blocks, h)
+	}
+
+	completed, err := state.GetCompletedRanges(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []HeightRange{{Start: 0, End: 20}}, completed)
+
+	stats := coordinator.Stats()
+	assert.Equal(t, int64(3), stats["gaps_found"])
+	assert.Equal(t, int64(5), stats["heights_refilled"])
+
+	// Nothing left to do
+	filled, err = coordinator.FillGaps(ctx, 0, 20)
+	require.NoError(t, err)
+	assert.Zero(t, filled)
+}
+
+func TestBackfillCoordinator_FillGaps_RemainingGaps(t *testing.T) {
+	coordinator, mockStore, state := newGapTestCoordinator(t, 9)
+	mockStore.insertErrors[7] = fmt.Errorf("insert failed")
+
+	_, err := coordinator.FillGaps(context.Background(), 0, 9)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gaps remain")
+
+	// The range is not checkpointed as a whole
+	for _, r := range state.completed {
+		assert.False(t, r.Start <= 7 && 7 <= r.End)
+	}
+}
+
+func TestBackfillCoordinator_FillGaps_NoStateStore(t *testing.T) {
+	coordinator, err := NewBackfillCoordinator(NewMockRPCClient(), NewMockStore(), &Config{Workers: 1, BatchSize: 1, EndHeight: 1})
+	require.NoError(t, err)
+
+	_, err = coordinator.FillGaps(context.Background(), 0, 1)
+	assert.Error(t, err)
+}
+
+func TestBackfillCoordinator_ScanOnceKeepsHeadMargin(t *testing.T) {
+	coordinator, mockStore, _ := newGapTestCoordinator(t, 40)
+
+	// Database head at 40 with a gap at 10 and one inside the head margin at 35
+	for h := uint64(0); h <= 40; h++ {
+		if h == 10 || h == 35 {
+			continue
+		}
+		mockStore.blocks[h] = ParseRPCBlockHeader(generateTestBlock(h))
+	}
+
+	coordinator.scanOnce(context.Background())
+
+	assert.Contains(t, mockStore.blocks, uint64(10))
+	assert.NotContains(t, mockStore.blocks, uint64(35), "heights near the head are left to live-tail")
+}
+
+func TestBackfillCoordinator_ScanOnceSkipsCompletedRanges(t *testing.T) {
+	coordinator, mockStore, state := newGapTestCoordinator(t, 40)
+
+	// Gaps at 5 (inside a completed range) and 25 (past it)
+	for h := uint64(0); h <= 40; h++ {
+		if h == 5 || h == 25 {
+			continue
+		}
+		mockStore.blocks[h] = ParseRPCBlockHeader(generateTestBlock(h))
+	}
+	state.completed = []HeightRange{{Start: 0, End: 19}}
+
+	coordinator.scanOnce(context.Background())
+
+	assert.NotContains(t, mockStore.blocks, uint64(5), "completed ranges are not rescanned")
+	assert.Contains(t, mockStore.blocks, uint64(25))
+
+	// The scanned range is checkpointed, so the next scan starts past it
+	resume, err := coordinator.ResumeHeight(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(40-gapScanHeadMargin+1), resume)
+}
+
+func TestBackfillCoordinator_RunGapScanner(t *testing.T) {
+	coordinator, mockStore, _ := newGapTestCoordinator(t, 30)
+	coordinator.config.GapScanInterval = 10 * time.Millisecond
+
+	for h := uint64(0); h <= 30; h++ {
+		if h != 5 {
+			mockStore.blocks[h] = ParseRPCBlockHeader(generateTestBlock(h))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	coordinator.RunGapScanner(ctx)
+
+	assert.Contains(t, mockStore.blocks, uint64(5))
+}
+
+func TestNewConfig_GapScanInterval(t *testing.T) {
+	saved := os.Getenv("BACKFILL_GAP_SCAN_INTERVAL")
+	defer os.Setenv("BACKFILL_GAP_SCAN_INTERVAL", saved)
+
+	os.Unsetenv("BACKFILL_GAP_SCAN_INTERVAL")
+	config, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, config.GapScanInterval)
+
+	os.Setenv("BACKFILL_GAP_SCAN_INTERVAL", "0")
+	config, err = NewConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.GapScanInterval)
+
+	os.Setenv("BACKFILL_GAP_SCAN_INTERVAL", "often")
+	_, err = NewConfig()
+	assert.Error(t, err)
+}
+
+func TestGroupHeights(t *testing.T) {
+	assert.Nil(t, GroupHeights(nil))
+	assert.Equal(t, []HeightRange{{1, 3}, {5, 5}, {7, 8}}, GroupHeights([]uint64{1, 2, 3, 5, 7, 8}))
+	assert.Equal(t,
+		[]HeightRange{{Start: 1, End: 3}, {Start: 5, End: 5}, {Start: 8, End: 9}},
+		GroupHeights([]uint64{9, 2, 1, 5, 3, 8, 2}),
+	)
+}
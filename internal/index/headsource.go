@@ -0,0 +1,51 @@
+package index
+
+import (
+	"context"
+	"time"
+)
+
+// HeadSource signals live-tail that the chain head may have advanced
+// Implementations: PollingHeadSource (fixed interval) and rpc.HeadSubscriber (eth_subscribe newHeads)
+type HeadSource interface {
+	// Heads returns a channel of head heights (0 when the height is unknown)
+	// The channel is closed when the context is cancelled or the source is lost
+	Heads(ctx context.Context) (<-chan uint64, error)
+}
+
+// PollingHeadSource signals on a fixed interval without knowing the head height
+type PollingHeadSource struct {
+	interval time.Duration
+}
+
+// NewPollingHeadSource creates a head source that ticks every interval
+func NewPollingHeadSource(interval time.Duration) *PollingHeadSource {
+	return &PollingHeadSource{interval: interval}
+}
+
+// Heads returns a channel that receives 0 on every tick until the context is cancelled
+func (p *PollingHeadSource) Heads(ctx context.Context) (<-chan uint64, error) {
+	heads := make(chan uint64)
+
+	go func() {
+		defer close(heads)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case heads <- 0:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return heads, nil
+}
@@ -0,0 +1,79 @@
+package index
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Receipt ingestion modes
+const (
+	// ReceiptModeNone parses blocks without receipts (gas limit as gas_used, success assumed, no logs)
+	ReceiptModeNone = "none"
+
+	// ReceiptModeBlock fetches all receipts of a block with one eth_getBlockReceipts call
+	ReceiptModeBlock = "block"
+
+	// ReceiptModeTransaction fetches receipts one by one with eth_getTransactionReceipt
+	ReceiptModeTransaction = "transaction"
+)
+
+// IngestConfig holds configuration for block ingestion (parsing RPC blocks into the domain model)
+type IngestConfig struct {
+	ReceiptMode string // One of ReceiptModeNone, ReceiptModeBlock, ReceiptModeTransaction
+	TraceCalls  bool   // Trace blocks with debug_traceBlockByHash (callTracer) to index internal transactions
+}
+
+// NewIngestConfig creates a new ingestion configuration from environment variables
+// INGEST_RECEIPTS: none (default), block, transaction
+// INGEST_TRACES: false (default) or true; requires the debug API on the RPC node
+func NewIngestConfig() (*IngestConfig, error) {
+	mode := strings.ToLower(os.Getenv("INGEST_RECEIPTS"))
+	if mode == "" {
+		mode = ReceiptModeNone
+	}
+
+	traceCalls := false
+	if value := os.Getenv("INGEST_TRACES"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INGEST_TRACES value '%s': %w", value, err)
+		}
+		traceCalls = parsed
+	}
+
+	config := &IngestConfig{
+		ReceiptMode: mode,
+		TraceCalls:  traceCalls,
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid INGEST_RECEIPTS value '%s': %w", mode, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *IngestConfig) Validate() error {
+	switch c.ReceiptMode {
+	case ReceiptModeNone, ReceiptModeBlock, ReceiptModeTransaction:
+		return nil
+	default:
+		return fmt.Errorf("receipt_mode must be one of %s, %s, %s, got %q",
+			ReceiptModeNone, ReceiptModeBlock, ReceiptModeTransaction, c.ReceiptMode)
+	}
+}
+
+// FetchReceipts reports whether the ingester should fetch receipts
+func (c *IngestConfig) FetchReceipts() bool {
+	return c.ReceiptMode != ReceiptModeNone
+}
+
+// DefaultIngestConfig returns sensible defaults (basic mode, no receipts)
+func DefaultIngestConfig() *IngestConfig {
+	return &IngestConfig{
+		ReceiptMode: ReceiptModeNone,
+	}
+}
@@ -0,0 +1,55 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIngestConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVal   string
+		wantMode string
+		wantErr  bool
+	}{
+		{name: "default", envVal: "", wantMode: ReceiptModeNone},
+		{name: "block mode", envVal: "block", wantMode: ReceiptModeBlock},
+		{name: "transaction mode uppercase", envVal: "TRANSACTION", wantMode: ReceiptModeTransaction},
+		{name: "invalid mode", envVal: "full", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INGEST_RECEIPTS", tt.envVal)
+
+			config, err := NewIngestConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMode, config.ReceiptMode)
+			assert.Equal(t, tt.wantMode != ReceiptModeNone, config.FetchReceipts())
+		})
+	}
+}
+
+func TestNewIngestConfig_Traces(t *testing.T) {
+	t.Setenv("INGEST_RECEIPTS", "")
+
+	t.Setenv("INGEST_TRACES", "")
+	config, err := NewIngestConfig()
+	require.NoError(t, err)
+	assert.False(t, config.TraceCalls, "tracing is off by default")
+
+	t.Setenv("INGEST_TRACES", "true")
+	config, err = NewIngestConfig()
+	require.NoError(t, err)
+	assert.True(t, config.TraceCalls)
+
+	t.Setenv("INGEST_TRACES", "sometimes")
+	_, err = NewIngestConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// integrityBatchSize is the number of blocks fetched per RPC request by the integrity verifier
+const integrityBatchSize = 50
+
+// integrityMaxRecheck bounds how many heights with open mismatches are re-verified per round
+const integrityMaxRecheck = 100
+
+// integrityChainFields are the fields compared against RPC; FieldParentLink is checked in the database
+var integrityChainFields = []string{FieldHash, FieldParentHash, FieldTxCount, FieldTxHash}
+
+// IntegrityStore reads stored blocks for verification and records mismatches
+// (implemented by the storage layer on the blocks, transactions and integrity_mismatches tables)
+type IntegrityStore interface {
+	GetLatestBlock(ctx context.Context) (*Block, error)
+	// GetBlocksWithTxHashes returns the canonical blocks at the given heights, ordered by height, with
+	// Transactions holding only Hash and TxIndex; heights without a canonical block are skipped
+	GetBlocksWithTxHashes(ctx context.Context, heights []uint64) ([]*Block, error)
+	// FindParentHashBreaks returns the canonical blocks of [startHeight, endHeight] whose parent hash
+	// differs from the hash of the canonical block one height below
+	FindParentHashBreaks(ctx context.Context, startHeight, endHeight uint64) ([]BlockMismatch, error)
+	// RecordIntegrityResults records the mismatches found for fields in the checked ranges and resolves
+	// the open mismatches of those fields and ranges that were not found again
+	RecordIntegrityResults(ctx context.Context, fields []string, checked []HeightRange, mismatches []BlockMismatch) error
+	// GetOpenIntegrityMismatches returns the unresolved mismatches, ordered by height and field
+	GetOpenIntegrityMismatches(ctx context.Context) ([]BlockMismatch, error)
+}
+
+// IntegrityReport summarizes one verification round
+type IntegrityReport struct {
+	BlocksChecked int             // Stored blocks compared against RPC
+	LinksChecked  uint64          // Heights whose parent-hash link was checked in the database
+	Mismatches    []BlockMismatch // Mismatches found in this round (new and still open)
+	OpenHeights   int             // Heights with unresolved mismatches after the round
+}
+
+// IntegrityVerifier periodically compares the index against the chain to detect silent corruption
+// Every round checks the most recent heights below the reorg margin, random historical heights and
+// the heights with open mismatches against RPC, and walks a window of parent-hash links in the database.
+// Mismatches are recorded (and resolved once a later round finds the height correct again), never repaired.
+type IntegrityVerifier struct {
+	rpcClient RPCBlockFetcher
+	store     IntegrityStore
+	config    *IntegrityConfig
+
+	randN  func(n uint64) uint64 // Random source for historical samples (replaced in tests)
+	cursor uint64                // Next height of the parent-hash continuity walk
+
+	rounds        int64
+	blocksChecked int64
+	mismatches    int64 // Newly detected mismatches (open ones are counted once)
+	openHeights   int
+}
+
+// NewIntegrityVerifier creates a new integrity verifier with the provided configuration
+func NewIntegrityVerifier(rpcClient RPCBlockFetcher, store IntegrityStore, config *IntegrityConfig) (*IntegrityVerifier, error) {
+	if rpcClient == nil {
+		return nil, fmt.Errorf("rpcClient cannot be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &IntegrityVerifier{
+		rpcClient: rpcClient,
+		store:     store,
+		config:    config,
+		randN:     rand.Uint64N,
+		cursor:    config.StartHeight,
+	}, nil
+}
+
+// Run verifies the index every Interval until the context is cancelled
+// Does nothing when Interval is 0.
+func (iv *IntegrityVerifier) Run(ctx context.Context) {
+	if iv.config.Interval <= 0 {
+		return
+	}
+
+	util.Info("integrity verifier started",
+		"interval", iv.config.Interval.String(),
+		"recent_blocks", iv.config.RecentBlocks,
+		"random_samples", iv.config.RandomSamples,
+		"continuity_window", iv.config.ContinuityWindow,
+	)
+
+	ticker := time.NewTicker(iv.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			report, err := iv.Verify(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					util.Warn("integrity verification failed",
+						"error", err.Error(),
+					)
+				}
+				continue
+			}
+			util.Debug("integrity verification completed",
+				"blocks_checked", report.BlocksChecked,
+				"links_checked", report.LinksChecked,
+				"mismatches", len(report.Mismatches),
+				"open_heights", report.OpenHeights,
+			)
+		case <-ctx.Done():
+			util.Info("integrity verifier stopped")
+			return
+		}
+	}
+}
+
+// Verify runs one verification round and records its results
+func (iv *IntegrityVerifier) Verify(ctx context.Context) (*IntegrityReport, error) {
+	report := &IntegrityReport{}
+
+	latest, err := iv.store.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if latest == nil || latest.Height < iv.config.StartHeight+gapScanHeadMargin {
+		return report, nil // Nothing indexed below the reorg margin yet
+	}
+	top := latest.Height - gapScanHeadMargin
+
+	open, err := iv.store.GetOpenIntegrityMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open integrity mismatches: %w", err)
+	}
+	wasOpen := make(map[mismatchKey]bool, len(open))
+	for _, m := range open {
+		wasOpen[mismatchKey{m.Height, m.Field}] = true
+	}
+
+	// Compare sampled blocks against RPC
+	heights := iv.sampleHeights(top, openHeights(open))
+	checked, chainMismatches, err := iv.compareWithChain(ctx, heights)
+	if err != nil {
+		return nil, err
+	}
+	report.BlocksChecked = len(checked)
+	if err := iv.store.RecordIntegrityResults(ctx, integrityChainFields, GroupHeights(checked), chainMismatches); err != nil {
+		return nil, fmt.Errorf("failed to record integrity results: %w", err)
+	}
+
+	// Check parent-hash links around the sampled heights and the next continuity window
+	links := GroupHeights(heights)
+	if window, ok := iv.nextWindow(top); ok {
+		links = append(links, window)
+	}
+	var linkMismatches []BlockMismatch
+	for _, r := range links {
+		breaks, err := iv.store.FindParentHashBreaks(ctx, r.Start, r.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check parent hashes %d-%d: %w", r.Start, r.End, err)
+		}
+		linkMismatches = append(linkMismatches, breaks...)
+		report.LinksChecked += r.Count()
+	}
+	if err := iv.store.RecordIntegrityResults(ctx, []string{FieldParentLink}, links, linkMismatches); err != nil {
+		return nil, fmt.Errorf("failed to record integrity results: %w", err)
+	}
+
+	report.Mismatches = append(chainMismatches, linkMismatches...)
+	var detected int
+	for _, m := range report.Mismatches {
+		if wasOpen[mismatchKey{m.Height, m.Field}] {
+			continue // Already reported
+		}
+		detected++
+		integrityMismatchesDetected.WithLabelValues(m.Field).Inc()
+		util.Error("integrity mismatch detected - re-index with explorerctl verify --repair",
+			"alert", "integrity_mismatch",
+			"height", m.Height,
+			"field", m.Field,
+			"stored", m.Stored,
+			"chain", m.Chain,
+		)
+	}
+
+	open, err = iv.store.GetOpenIntegrityMismatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open integrity mismatches: %w", err)
+	}
+	report.OpenHeights = len(openHeights(open))
+
+	iv.rounds++
+	iv.blocksChecked += int64(report.BlocksChecked)
+	iv.mismatches += int64(detected)
+	iv.openHeights = report.OpenHeights
+
+	integrityBlocksChecked.Add(float64(report.BlocksChecked))
+	integrityOpenHeights.Set(float64(report.OpenHeights))
+	integrityLastRun.SetToCurrentTime()
+	return report, nil
+}
+
+// mismatchKey identifies an open mismatch (one per height and field)
+type mismatchKey struct {
+	height uint64
+	field  string
+}
+
+// openHeights returns the distinct heights of open mismatches ordered by height
+func openHeights(open []BlockMismatch) []uint64 {
+	var heights []uint64
+	for _, m := range open {
+		if n := len(heights); n == 0 || heights[n-1] != m.Height {
+			heights = append(heights, m.Height)
+		}
+	}
+	return heights
+}
+
+// sampleHeights returns the sorted, distinct heights compared against RPC this round: the recent
+// heights up to top, random heights below them and the lowest heights with open mismatches
+func (iv *IntegrityVerifier) sampleHeights(top uint64, open []uint64) []uint64 {
+	start := iv.config.StartHeight
+	heights := make([]uint64, 0, iv.config.RecentBlocks+iv.config.RandomSamples+len(open))
+
+	recentStart := start
+	if recent := uint64(iv.config.RecentBlocks); recent > 0 && top-start+1 > recent {
+		recentStart = top - recent + 1
+	}
+	if iv.config.RecentBlocks > 0 {
+		for h := recentStart; h <= top; h++ {
+			heights = append(heights, h)
+		}
+	}
+
+	if recentStart > start {
+		for range iv.config.RandomSamples {
+			heights = append(heights, start+iv.randN(recentStart-start))
+		}
+	}
+
+	for _, h := range open[:min(len(open), integrityMaxRecheck)] {
+		if h >= start && h <= top {
+			heights = append(heights, h)
+		}
+	}
+
+	slices.Sort(heights)
+	return slices.Compact(heights)
+}
+
+// nextWindow returns the next range of the continuity walk and advances the cursor,
+// wrapping around to StartHeight once the walk reaches top
+func (iv *IntegrityVerifier) nextWindow(top uint64) (HeightRange, bool) {
+	if iv.config.ContinuityWindow <= 0 {
+		return HeightRange{}, false
+	}
+	if iv.cursor < iv.config.StartHeight || iv.cursor > top {
+		iv.cursor = iv.config.StartHeight
+	}
+
+	window := HeightRange{Start: iv.cursor, End: min(iv.cursor+uint64(iv.config.ContinuityWindow)-1, top)}
+	iv.cursor = window.End + 1
+	return window, true
+}
+
+// compareWithChain fetches the stored blocks and the chain's blocks at heights and compares them
+// Returns the heights that were compared (heights missing from the database are left to the gap scanner)
+func (iv *IntegrityVerifier) compareWithChain(ctx context.Context, heights []uint64) ([]uint64, []BlockMismatch, error) {
+	var checked []uint64
+	var mismatches []BlockMismatch
+
+	for batch := range slices.Chunk(heights, integrityBatchSize) {
+		stored, err := iv.store.GetBlocksWithTxHashes(ctx, batch)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get stored blocks %d-%d: %w", batch[0], batch[len(batch)-1], err)
+		}
+		if len(stored) == 0 {
+			continue
+		}
+
+		storedHeights := make([]uint64, len(stored))
+		for i, block := range stored {
+			storedHeights[i] = block.Height
+		}
+		chainBlocks, err := iv.fetchBlocks(ctx, storedHeights)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, block := range stored {
+			mismatches = append(mismatches, CompareBlock(block, chainBlocks[i])...)
+			mismatches = append(mismatches, CompareTransactions(block, chainBlocks[i])...)
+		}
+		checked = append(checked, storedHeights...)
+	}
+
+	return checked, mismatches, nil
+}
+
+// fetchBlocks fetches the chain's blocks at heights, in one batch request when the client supports it
+// Requests are bounded by the RPC client's request timeout (see BackfillCoordinator.fetchChunk)
+func (iv *IntegrityVerifier) fetchBlocks(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	if batchFetcher, ok := iv.rpcClient.(RPCBatchBlockFetcher); ok && len(heights) > 1 {
+		blocks, err := batchFetcher.GetBlocksByNumber(ctx, heights)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch blocks %d-%d: %w", heights[0], heights[len(heights)-1], err)
+		}
+		if len(blocks) != len(heights) {
+			return nil, fmt.Errorf("expected %d blocks, got %d", len(heights), len(blocks))
+		}
+		for i, block := range blocks {
+			if block == nil {
+				return nil, fmt.Errorf("block %d not returned", heights[i])
+			}
+		}
+		return blocks, nil
+	}
+
+	blocks := make([]*types.Block, len(heights))
+	for i, height := range heights {
+		block, err := iv.rpcClient.GetBlockByNumber(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch block %d: %w", height, err)
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
+
+// Stats returns integrity verifier statistics for observability
+func (iv *IntegrityVerifier) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"rounds":              iv.rounds,
+		"blocks_checked":      iv.blocksChecked,
+		"mismatches_detected": iv.mismatches,
+		"open_heights":        iv.openHeights,
+		"interval":            iv.config.Interval.String(),
+	}
+}
@@ -0,0 +1,71 @@
+package index
+
+import (
+	"fmt"
+	"time"
+)
+
+// IntegrityConfig holds configuration for the background integrity verifier
+type IntegrityConfig struct {
+	// Interval is how often a verification round runs (0 disables the verifier)
+	Interval time.Duration
+	// RecentBlocks is the number of heights below the reorg margin checked every round
+	RecentBlocks int
+	// RandomSamples is the number of random historical heights checked every round
+	RandomSamples int
+	// ContinuityWindow is the number of heights whose parent-hash links are checked per round;
+	// the window advances from StartHeight to the head and wraps around
+	ContinuityWindow int
+	// StartHeight is the lowest height sampled, the backfill start height (BACKFILL_START_HEIGHT)
+	StartHeight uint64
+}
+
+// NewIntegrityConfig creates a new integrity verifier configuration from environment variables
+// Falls back to sensible defaults if env vars are not set
+func NewIntegrityConfig() (*IntegrityConfig, error) {
+	interval, err := getEnvDuration("INTEGRITY_CHECK_INTERVAL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &IntegrityConfig{
+		Interval:         interval,
+		RecentBlocks:     getEnvInt("INTEGRITY_RECENT_BLOCKS", 64),
+		RandomSamples:    getEnvInt("INTEGRITY_RANDOM_SAMPLES", 32),
+		ContinuityWindow: getEnvInt("INTEGRITY_CONTINUITY_WINDOW", 10000),
+		StartHeight:      getEnvUint64("BACKFILL_START_HEIGHT", 0),
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *IntegrityConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("interval must be >= 0, got %v", c.Interval)
+	}
+	if c.RecentBlocks < 0 {
+		return fmt.Errorf("recent_blocks must be >= 0, got %d", c.RecentBlocks)
+	}
+	if c.RandomSamples < 0 {
+		return fmt.Errorf("random_samples must be >= 0, got %d", c.RandomSamples)
+	}
+	if c.ContinuityWindow < 0 {
+		return fmt.Errorf("continuity_window must be >= 0, got %d", c.ContinuityWindow)
+	}
+	return nil
+}
+
+// DefaultIntegrityConfig returns sensible defaults for integrity verification
+func DefaultIntegrityConfig() *IntegrityConfig {
+	return &IntegrityConfig{
+		Interval:         5 * time.Minute,
+		RecentBlocks:     64,
+		RandomSamples:    32,
+		ContinuityWindow: 10000,
+	}
+}
@@ -0,0 +1,270 @@
+package index
+
+import (
+	"cmp"
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIntegrityStore implements IntegrityStore over an in-memory chain
+type mockIntegrityStore struct {
+	blocks map[uint64]*Block
+	open   map[uint64]map[string]BlockMismatch // height -> field -> open mismatch
+}
+
+func (m *mockIntegrityStore) GetLatestBlock(ctx context.Context) (*Block, error) {
+	var latest *Block
+	for _, b := range m.blocks {
+		if latest == nil || b.Height > latest.Height {
+			latest = b
+		}
+	}
+	return latest, nil
+}
+
+func (m *mockIntegrityStore) GetBlocksWithTxHashes(ctx context.Context, heights []uint64) ([]*Block, error) {
+	var blocks []*Block
+	for _, h := range heights {
+		if b, ok := m.blocks[h]; ok {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks, nil
+}
+
+func (m *mockIntegrityStore) FindParentHashBreaks(ctx context.Context, startHeight, endHeight uint64) ([]BlockMismatch, error) {
+	var breaks []BlockMismatch
+	for h := startHeight; h <= endHeight; h++ {
+		b, ok := m.blocks[h]
+		parent, hasParent := m.blocks[h-1]
+		if !ok || !hasParent || h == 0 {
+			continue
+		}
+		if string(b.ParentHash) != string(parent.Hash) {
+			breaks = append(breaks, BlockMismatch{Height: h, Field: FieldParentLink})
+		}
+	}
+	return breaks, nil
+}
+
+func (m *mockIntegrityStore) RecordIntegrityResults(ctx context.Context, fields []string, checked []HeightRange, mismatches []BlockMismatch) error {
+	for height, byField := range m.open {
+		for _, r := range checked {
+			if height < r.Start || height > r.End {
+				continue
+			}
+			for _, field := range fields {
+				delete(byField, field)
+			}
+		}
+		if len(byField) == 0 {
+			delete(m.open, height)
+		}
+	}
+	for _, mismatch := range mismatches {
+		if m.open[mismatch.Height] == nil {
+			m.open[mismatch.Height] = make(map[string]BlockMismatch)
+		}
+		m.open[mismatch.Height][mismatch.Field] = mismatch
+	}
+	return nil
+}
+
+func (m *mockIntegrityStore) GetOpenIntegrityMismatches(ctx context.Context) ([]BlockMismatch, error) {
+	var open []BlockMismatch
+	for _, byField := range m.open {
+		for _, mismatch := range byField {
+			open = append(open, mismatch)
+		}
+	}
+	slices.SortFunc(open, func(a, b BlockMismatch) int {
+		if c := cmp.Compare(a.Height, b.Height); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Field, b.Field)
+	})
+	return open, nil
+}
+
+// setupIntegrityChain builds a linked chain of heights [0, head] with one transaction per block,
+// served by the RPC mock and indexed unchanged in the store mock
+func setupIntegrityChain(head uint64) (*MockRPCBlockFetcher, *mockIntegrityStore) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	mockStore := &mockIntegrityStore{
+		blocks: make(map[uint64]*Block),
+		open:   make(map[uint64]map[string]BlockMismatch),
+	}
+
+	parentHash := common.Hash{}
+	for h := uint64(0); h <= head; h++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: h, GasPrice: big.NewInt(1), Gas: 21000})
+		chainBlock := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(h),
+			ParentHash: parentHash,
+		}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+		mockRPC.blockCache[h] = chainBlock
+		parentHash = chainBlock.Hash()
+
+		stored := ParseRPCBlockHeader(chainBlock)
+		stored.Transactions = []Transaction{{Hash: tx.Hash().Bytes(), TxIndex: 0}}
+		mockStore.blocks[h] = stored
+	}
+	return mockRPC, mockStore
+}
+
+func TestNewIntegrityVerifier(t *testing.T) {
+	mockRPC, mockStore := setupIntegrityChain(0)
+
+	_, err := NewIntegrityVerifier(nil, mockStore, DefaultIntegrityConfig())
+	assert.Error(t, err)
+	_, err = NewIntegrityVerifier(mockRPC, nil, DefaultIntegrityConfig())
+	assert.Error(t, err)
+	_, err = NewIntegrityVerifier(mockRPC, mockStore, nil)
+	assert.Error(t, err)
+	_, err = NewIntegrityVerifier(mockRPC, mockStore, &IntegrityConfig{RandomSamples: -1})
+	assert.Error(t, err)
+}
+
+func TestIntegrityVerifier_CleanIndex(t *testing.T) {
+	mockRPC, mockStore := setupIntegrityChain(200)
+	config := &IntegrityConfig{RecentBlocks: 20, RandomSamples: 10, ContinuityWindow: 50}
+	verifier, err := NewIntegrityVerifier(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	report, err := verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+	assert.Zero(t, report.OpenHeights)
+	assert.GreaterOrEqual(t, report.BlocksChecked, 21)
+	assert.LessOrEqual(t, report.BlocksChecked, 30)
+	assert.Equal(t, int64(1), verifier.Stats()["rounds"])
+}
+
+func TestIntegrityVerifier_DetectsAndResolvesMismatches(t *testing.T) {
+	mockRPC, mockStore := setupIntegrityChain(200)
+	config := &IntegrityConfig{RecentBlocks: 20, RandomSamples: 0, ContinuityWindow: 100}
+	verifier, err := NewIntegrityVerifier(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	// Recent heights are 169-188 (head 200 minus the reorg margin); the continuity window covers 0-99
+	original := *mockStore.blocks[180]
+	corrupted := original
+	corrupted.Transactions = []Transaction{{Hash: generateHash(1), TxIndex: 0}}
+	mockStore.blocks[180] = &corrupted
+
+	originalParent := mockStore.blocks[50].ParentHash
+	mockStore.blocks[50].ParentHash = generateHash(49)
+
+	detected := testutil.ToFloat64(integrityMismatchesDetected.WithLabelValues(FieldTxHash))
+	report, err := verifier.Verify(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 2)
+	assert.Equal(t, uint64(180), report.Mismatches[0].Height)
+	assert.Equal(t, FieldTxHash, report.Mismatches[0].Field)
+	assert.Equal(t, uint64(50), report.Mismatches[1].Height)
+	assert.Equal(t, FieldParentLink, report.Mismatches[1].Field)
+	assert.Equal(t, 2, report.OpenHeights)
+	assert.Equal(t, detected+1, testutil.ToFloat64(integrityMismatchesDetected.WithLabelValues(FieldTxHash)))
+	assert.Equal(t, int64(2), verifier.Stats()["mismatches_detected"])
+
+	// Unrepaired mismatches stay open and are not counted again; re-checking open height 50 against
+	// RPC finds a new parent_hash mismatch there, which is counted although the height is already open
+	parentHashDetected := testutil.ToFloat64(integrityMismatchesDetected.WithLabelValues(FieldParentHash))
+	report, err = verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.Mismatches, 3)
+	assert.Equal(t, detected+1, testutil.ToFloat64(integrityMismatchesDetected.WithLabelValues(FieldTxHash)))
+	assert.Equal(t, parentHashDetected+1, testutil.ToFloat64(integrityMismatchesDetected.WithLabelValues(FieldParentHash)))
+	assert.Equal(t, int64(3), verifier.Stats()["mismatches_detected"])
+	assert.Equal(t, 2, report.OpenHeights)
+
+	// Nothing new in a further round
+	_, err = verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), verifier.Stats()["mismatches_detected"])
+
+	// Height 50 is outside the recent window but re-checked while open; after repair both resolve
+	mockStore.blocks[180] = &original
+	mockStore.blocks[50].ParentHash = originalParent
+
+	report, err = verifier.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+	assert.Zero(t, report.OpenHeights)
+	assert.Empty(t, mockStore.open)
+}
+
+func TestIntegrityVerifier_SampleHeights(t *testing.T) {
+	mockRPC, mockStore := setupIntegrityChain(0)
+	config := &IntegrityConfig{RecentBlocks: 5, RandomSamples: 3, StartHeight: 1000}
+	verifier, err := NewIntegrityVerifier(mockRPC, mockStore, config)
+	require.NoError(t, err)
+
+	samples := []uint64{7, 7, 0}
+	verifier.randN = func(n uint64) uint64 {
+		assert.Equal(t, uint64(95), n, "random heights are drawn below the recent window")
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+
+	// Duplicates are removed; open heights outside [StartHeight, top] are not re-checked
+	heights := verifier.sampleHeights(1099, []uint64{500, 1007, 1050, 2000})
+	assert.Equal(t, []uint64{1000, 1007, 1050, 1095, 1096, 1097, 1098, 1099}, heights)
+
+	// Fewer indexed heights than RecentBlocks: everything is recent, nothing random
+	heights = verifier.sampleHeights(1002, nil)
+	assert.Equal(t, []uint64{1000, 1001, 1002}, heights)
+}
+
+func TestIntegrityVerifier_ContinuityWindowWraps(t *testing.T) {
+	mockRPC, mockStore := setupIntegrityChain(0)
+	verifier, err := NewIntegrityVerifier(mockRPC, mockStore, &IntegrityConfig{ContinuityWindow: 40, StartHeight: 10})
+	require.NoError(t, err)
+
+	var windows []HeightRange
+	for range 4 {
+		window, ok := verifier.nextWindow(100)
+		require.True(t, ok)
+		windows = append(windows, window)
+	}
+	assert.Equal(t, []HeightRange{{10, 49}, {50, 89}, {90, 100}, {10, 49}}, windows)
+
+	verifier.config.ContinuityWindow = 0
+	_, ok := verifier.nextWindow(100)
+	assert.False(t, ok)
+}
+
+func TestNewIntegrityConfig(t *testing.T) {
+	t.Setenv("INTEGRITY_CHECK_INTERVAL", "")
+	t.Setenv("INTEGRITY_RECENT_BLOCKS", "")
+	t.Setenv("INTEGRITY_RANDOM_SAMPLES", "")
+	t.Setenv("INTEGRITY_CONTINUITY_WINDOW", "")
+	t.Setenv("BACKFILL_START_HEIGHT", "1000")
+
+	config, err := NewIntegrityConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, config.Interval)
+	assert.Equal(t, 64, config.RecentBlocks)
+	assert.Equal(t, 32, config.RandomSamples)
+	assert.Equal(t, 10000, config.ContinuityWindow)
+	assert.Equal(t, uint64(1000), config.StartHeight)
+
+	t.Setenv("INTEGRITY_CHECK_INTERVAL", "0")
+	config, err = NewIntegrityConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.Interval)
+
+	t.Setenv("INTEGRITY_RECENT_BLOCKS", "-1")
+	_, err = NewIntegrityConfig()
+	assert.Error(t, err)
+}
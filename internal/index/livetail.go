@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"os"
 	"sync/atomic"
 	"time"
@@ -30,6 +31,9 @@ type BlockData struct {
 // TransactionData represents minimal transaction data for broadcasting
 type TransactionData struct {
 	Hash        string
+	FromAddr    string
+	ToAddr      string // Empty for contract creation
+	ValueWei    string
 	BlockHeight uint64
 }
 
@@ -48,11 +52,40 @@ type Transaction struct {
 	FromAddr []byte   // Sender address
 	ToAddr   *[]byte  // Recipient address (nil for contract creation)
 	ValueWei string   // Value transferred in wei (as string to preserve precision)
-	GasUsed  uint64
-	GasPrice uint64
+	GasUsed  uint64   // Gas limit in basic mode, actual gas used when receipts are ingested
+	GasPrice *big.Int // Declared gas price (fee cap for EIP-1559 types) in basic mode, effective gas price when receipts are ingested
 	Nonce    uint64
 	Success  bool     // Whether transaction succeeded
 	Logs     []Log    // Transaction logs (events)
+
+	ContractAddress *[]byte // Created contract address (receipt mode only, nil otherwise)
+
+	Type                 uint8            // EIP-2718 type: 0 legacy, 1 access list, 2 dynamic fee, 3 blob, 4 set code
+	MaxFeePerGas         *big.Int         // EIP-1559 fee cap (nil for legacy and access list transactions)
+	MaxPriorityFeePerGas *big.Int         // EIP-1559 tip cap (nil for legacy and access list transactions)
+	EffectiveGasPrice    *big.Int         // Price paid per gas from the receipt (receipt mode only, nil otherwise)
+	Input                []byte           // Call data (empty for plain transfers)
+	AccessList           types.AccessList // EIP-2930 access list (nil for legacy transactions)
+	MaxFeePerBlobGas     *big.Int         // EIP-4844 blob fee cap (blob transactions only)
+	BlobHashes           [][]byte         // EIP-4844 versioned blob hashes (blob transactions only)
+	BlobGasUsed          uint64           // Blob gas used from the receipt (receipt mode blob transactions only)
+	BlobGasPrice         *big.Int         // Blob gas price from the receipt (receipt mode blob transactions only)
+
+	InternalTxs []InternalTransaction // Calls made by the transaction, from its call trace (trace mode only)
+}
+
+// InternalTransaction is a call made by a transaction's execution (a contract calling another
+// contract or sending ETH), flattened from the transaction's call tree
+type InternalTransaction struct {
+	TraceAddress []int  // Path in the call tree: [0] is the first call made by the transaction, [0 1] the second call made by [0]
+	Depth        int    // Call depth, len(TraceAddress)
+	Type         string // CALL, STATICCALL, DELEGATECALL, CALLCODE, CREATE, CREATE2 or SELFDESTRUCT
+	FromAddr     []byte
+	ToAddr       []byte   // nil for a failed contract creation
+	ValueWei     *big.Int // 0 when the tracer reports no value (e.g. STATICCALL)
+	Gas          uint64
+	GasUsed      uint64
+	Error        string // Empty if the call succeeded (e.g. "execution reverted" otherwise)
 }
 
 // LiveTailCoordinator manages sequential live-tail processing of new blocks
@@ -62,6 +95,7 @@ type LiveTailCoordinator struct {
 	ingester     BlockIngester
 	reorgHandler ReorgHandler
 	hub          WebSocketBroadcaster // Optional WebSocket hub for real-time broadcasts
+	headSource   HeadSource           // Optional push-based head source (nil: polling only)
 	config       *LiveTailConfig
 	logger       *slog.Logger
 
@@ -71,9 +105,13 @@ type LiveTailCoordinator struct {
 	// Metrics
 	blocksProcessed int64
 	currentHeight   int64
+	chainHead       int64 // Latest head height reported by the head source (0 if unknown)
 	startTime       time.Time
 }
 
+// headResubscribeDelay is how long live-tail polls before retrying a failed head subscription
+const headResubscribeDelay = 30 * time.Second
+
 // BlockStore interface for database operations (stub for now)
 type BlockStore interface {
 	GetLatestBlock(ctx context.Context) (*Block, error)
@@ -81,9 +119,10 @@ type BlockStore interface {
 	GetBlockByHeight(ctx context.Context, height uint64) (*Block, error)
 }
 
-// BlockIngester interface for block parsing (stub for now)
+// BlockIngester interface for block parsing
+// Implementations may call RPC (e.g. to fetch receipts), so parsing takes a context
 type BlockIngester interface {
-	ParseBlock(rpcBlock *types.Block) (*Block, error)
+	ParseBlock(ctx context.Context, rpcBlock *types.Block) (*Block, error)
 }
 
 // ReorgHandler interface for reorg handling (stub for Story 1.5)
@@ -91,7 +130,7 @@ type ReorgHandler interface {
 	HandleReorg(ctx context.Context, block *Block) error
 }
 
-// Block represents a blockchain block (domain model, stub)
+// Block represents a blockchain block (domain model)
 type Block struct {
 	Height       uint64
 	Hash         []byte
@@ -101,6 +140,33 @@ type Block struct {
 	GasUsed      uint64
 	TxCount      int
 	Transactions []Transaction // Extracted transactions from block
+
+	// Header fields
+	GasLimit         uint64
+	BaseFeePerGas    *big.Int // EIP-1559 (London), nil before
+	Difficulty       *big.Int // 0 after the merge
+	Nonce            []byte   // 8 bytes, zero after the merge
+	ExtraData        []byte
+	StateRoot        []byte
+	TransactionsRoot []byte
+	ReceiptsRoot     []byte
+	LogsBloom        []byte // 256 bytes
+	MixHash          []byte // PREVRANDAO after the merge
+	UncleHash        []byte
+	Size             uint64 // RLP-encoded block size in bytes
+	WithdrawalsRoot  []byte // EIP-4895 (Shanghai), nil before
+	Withdrawals      []Withdrawal
+	BlobGasUsed      *uint64 // EIP-4844 (Cancun), nil before
+	ExcessBlobGas    *uint64 // EIP-4844 (Cancun), nil before
+	ParentBeaconRoot []byte  // EIP-4788 (Cancun), nil before
+}
+
+// Withdrawal represents a validator withdrawal processed by a block (EIP-4895)
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        []byte
+	AmountGwei     uint64
 }
 
 // NewLiveTailCoordinator creates a new live-tail coordinator
@@ -143,82 +209,215 @@ func NewLiveTailCoordinator(
 	return ltc, nil
 }
 
-// Start begins the live-tail polling loop
+// SetHeadSource sets a push-based head source (e.g. eth_subscribe newHeads)
+// While the source is unavailable, live-tail falls back to polling at PollInterval; while it is
+// subscribed, polling keeps running as a watchdog so a silent subscription cannot stall live-tail
+func (ltc *LiveTailCoordinator) SetHeadSource(source HeadSource) {
+	ltc.headSource = source
+}
+
+// Start begins the live-tail loop
+// Every head signal drains all pending heights, so live-tail catches up even when
+// blocks are produced faster than the poll interval
 // Implements AC1 (Sequential), AC2 (Polling), AC3 (Error Handling), AC4 (Reorg), AC5 (Observability)
 func (ltc *LiveTailCoordinator) Start(ctx context.Context) error {
 	ltc.startTime = time.Now()
 
 	ltc.logger.Info("starting live-tail coordinator",
 		slog.Duration("poll_interval", ltc.config.PollInterval),
+		slog.Bool("head_subscription", ltc.headSource != nil),
 	)
 
-	ticker := time.NewTicker(ltc.config.PollInterval)
-	defer ticker.Stop()
+	poller := NewPollingHeadSource(ltc.config.PollInterval)
 
 	for {
-		select {
-		case <-ticker.C:
-			// Process next block
-			if err := ltc.processNextBlock(ctx); err != nil {
-				// AC3: Log and continue (don't halt)
-				ltc.logger.Error("error processing block",
-					slog.String("error", err.Error()),
-					slog.Int64("current_height", ltc.currentHeight),
-				)
-				// Continue to next tick
-			}
+		heads, watchdog, cancel := ltc.subscribeHeads(ctx, poller)
 
-		case <-ctx.Done():
+		// Catch up on anything produced while (re)subscribing
+		ltc.catchUp(ctx)
+
+		ltc.consumeHeads(ctx, heads, watchdog)
+		cancel()
+
+		if ctx.Err() != nil {
 			// AC3: Graceful shutdown
 			ltc.logger.Info("live-tail coordinator shutting down",
 				slog.Duration("duration", time.Since(ltc.startTime)),
-				slog.Int64("blocks_processed", ltc.blocksProcessed),
+				slog.Int64("blocks_processed", atomic.LoadInt64(&ltc.blocksProcessed)),
 			)
 			return ctx.Err()
 		}
+
+		if ltc.headSource != nil {
+			ltc.logger.Info("retrying head subscription")
+		}
+	}
+}
+
+// subscribeHeads opens the configured head source, falling back to polling if it is unset or fails
+// When falling back from a failed subscription, the poller stops after headResubscribeDelay so the
+// subscription is retried. While subscribed, the poller is also returned as a watchdog channel
+// (nil otherwise), which catches up if the subscription goes silent without closing
+func (ltc *LiveTailCoordinator) subscribeHeads(ctx context.Context, poller *PollingHeadSource) (<-chan uint64, <-chan uint64, context.CancelFunc) {
+	if ltc.headSource == nil {
+		heads, _ := poller.Heads(ctx)
+		return heads, nil, func() {}
+	}
+
+	heads, err := ltc.headSource.Heads(ctx)
+	if err == nil {
+		watchdogCtx, cancel := context.WithCancel(ctx)
+		watchdog, _ := poller.Heads(watchdogCtx)
+		return heads, watchdog, cancel
+	}
+
+	ltc.logger.Warn("head subscription failed, falling back to polling",
+		slog.String("error", err.Error()),
+		slog.Duration("retry_in", headResubscribeDelay),
+	)
+	fallbackCtx, cancel := context.WithTimeout(ctx, headResubscribeDelay)
+	heads, _ = poller.Heads(fallbackCtx)
+	return heads, nil, cancel
+}
+
+// consumeHeads catches up on every head signal until the source closes or the context is cancelled
+// Watchdog ticks catch up only if no head signal arrived within the last poll interval, so a
+// healthy subscription does not double the RPC load
+func (ltc *LiveTailCoordinator) consumeHeads(ctx context.Context, heads, watchdog <-chan uint64) {
+	lastHead := time.Now()
+	for {
+		select {
+		case height, ok := <-heads:
+			if !ok {
+				return
+			}
+			if height > 0 {
+				atomic.StoreInt64(&ltc.chainHead, int64(height))
+			}
+			lastHead = time.Now()
+			ltc.catchUp(ctx)
+
+		case _, ok := <-watchdog:
+			if !ok {
+				watchdog = nil
+				continue
+			}
+			if time.Since(lastHead) < ltc.config.PollInterval {
+				continue
+			}
+			ltc.logger.Debug("no head signal within poll interval, polling")
+			ltc.catchUp(ctx)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// catchUp processes blocks until the next height is not yet available, an error occurs,
+// or a reorg is detected (the next head signal resumes after reorg resolution)
+// While far behind the head, blocks are prefetched in parallel (see prefetchToHead)
+func (ltc *LiveTailCoordinator) catchUp(ctx context.Context) {
+	if err := ltc.prefetchToHead(ctx); err != nil {
+		ltc.logger.Error("error catching up",
+			slog.String("error", err.Error()),
+			slog.Int64("current_height", atomic.LoadInt64(&ltc.currentHeight)),
+		)
+		return
+	}
+
+	for ctx.Err() == nil {
+		advanced, err := ltc.processNext(ctx)
+		if err != nil {
+			// AC3: Log and continue (don't halt)
+			ltc.logger.Error("error processing block",
+				slog.String("error", err.Error()),
+				slog.Int64("current_height", atomic.LoadInt64(&ltc.currentHeight)),
+			)
+			return
+		}
+		if !advanced {
+			return
+		}
 	}
 }
 
 // processNextBlock fetches and processes the next sequential block
-// Implements AC1 (Sequential), AC2 (Next height), AC3 (Error handling), AC4 (Reorg check)
 func (ltc *LiveTailCoordinator) processNextBlock(ctx context.Context) error {
+	_, err := ltc.processNext(ctx)
+	return err
+}
+
+// processNext fetches and processes the next sequential block
+// Returns whether a block was inserted
+// Implements AC1 (Sequential), AC2 (Next height), AC3 (Error handling), AC4 (Reorg check)
+func (ltc *LiveTailCoordinator) processNext(ctx context.Context) (bool, error) {
 	// AC1: Query database head before each fetch
 	dbHead, err := ltc.store.GetLatestBlock(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get latest block: %w", err)
+		return false, fmt.Errorf("failed to get latest block: %w", err)
 	}
 
 	nextHeight := dbHead.Height + 1
 
-	// AC1/AC2: Fetch next block from RPC
-	rpcBlock, err := ltc.rpcClient.GetBlockByNumber(ctx, nextHeight)
+	// AC1/AC2: Fetch and parse next block
+	domainBlock, err := ltc.fetchBlock(ctx, nextHeight)
+	if err != nil {
+		// AC3: RPC error (transient or permanent), will retry on next tick
+		return false, err
+	}
+	if domainBlock == nil {
+		// AC2: Next block not yet produced, wait for next head
+		return false, nil
+	}
+
+	return ltc.commitBlock(ctx, dbHead, domainBlock)
+}
+
+// fetchBlock fetches a block and parses it to the domain model
+// Returns nil without error if the block has not been produced yet
+func (ltc *LiveTailCoordinator) fetchBlock(ctx context.Context, height uint64) (*Block, error) {
+	rpcBlock, err := ltc.rpcClient.GetBlockByNumber(ctx, height)
 	if err != nil {
 		// AC2: Handle "block not found" gracefully (next block not yet produced)
 		if isBlockNotFound(err) {
 			ltc.logger.Debug("next block not yet produced",
-				slog.Uint64("next_height", nextHeight),
+				slog.Uint64("next_height", height),
 			)
-			return nil // Not an error, just wait for next tick
+			return nil, nil
 		}
-		// AC3: RPC error (transient or permanent), will retry on next tick
-		return fmt.Errorf("failed to fetch block %d: %w", nextHeight, err)
+		return nil, fmt.Errorf("failed to fetch block %d: %w", height, err)
 	}
 
 	if rpcBlock == nil {
 		ltc.logger.Debug("block not found from rpc",
-			slog.Uint64("height", nextHeight),
+			slog.Uint64("height", height),
 		)
-		return nil
+		return nil, nil
 	}
 
-	// AC1/AC2: Parse RPC block to domain model (stub: just extract fields)
-	domainBlock := ltc.parseRPCBlock(rpcBlock, nextHeight)
+	// AC1/AC2: Parse RPC block to domain model (ingester if configured, header-only parser otherwise)
+	if ltc.ingester != nil {
+		domainBlock, err := ltc.ingester.ParseBlock(ctx, rpcBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse block %d: %w", height, err)
+		}
+		return domainBlock, nil
+	}
+	return ltc.parseRPCBlock(rpcBlock, height), nil
+}
+
+// commitBlock inserts a block on top of parent after checking their hashes link up
+// On a parent hash mismatch the reorg handler is triggered and the block is skipped
+// Returns whether the block was inserted
+func (ltc *LiveTailCoordinator) commitBlock(ctx context.Context, parent *Block, domainBlock *Block) (bool, error) {
+	nextHeight := parent.Height + 1
 
 	// AC4: Check for parent hash mismatch (reorg detection)
-	if !bytesEqual(domainBlock.ParentHash, dbHead.Hash) {
+	if !bytesEqual(domainBlock.ParentHash, parent.Hash) {
 		ltc.logger.Warn("parent hash mismatch detected (potential reorg)",
 			slog.Uint64("height", nextHeight),
-			slog.Uint64("db_head_height", dbHead.Height),
+			slog.Uint64("db_head_height", parent.Height),
 		)
 
 		// AC4: Trigger reorg handler if available
@@ -231,15 +430,15 @@ func (ltc *LiveTailCoordinator) processNextBlock(ctx context.Context) error {
 				// Continue anyway (reorg handling is asynchronous)
 			}
 		}
-		return nil // Skip this block, will retry on next tick after reorg resolution
+		return false, nil // Skip this block, will retry on next head after reorg resolution
 	}
 
 	// AC1/AC2: Insert block into database
 	if err := ltc.store.InsertBlock(ctx, domainBlock); err != nil {
-		return fmt.Errorf("failed to insert block %d: %w", nextHeight, err)
+		return false, fmt.Errorf("failed to insert block %d: %w", nextHeight, err)
 	}
 
-	// Broadcast block to WebSocket clients (Story 2.2)
+	// Broadcast block and its transactions to WebSocket clients (Story 2.2)
 	if ltc.hub != nil {
 		ltc.hub.BroadcastBlock(BlockData{
 			Height:    domainBlock.Height,
@@ -249,6 +448,20 @@ func (ltc *LiveTailCoordinator) processNextBlock(ctx context.Context) error {
 			Miner:     fmt.Sprintf("0x%x", domainBlock.Miner),
 			GasUsed:   domainBlock.GasUsed,
 		})
+
+		for _, txn := range domainBlock.Transactions {
+			toAddr := ""
+			if txn.ToAddr != nil {
+				toAddr = fmt.Sprintf("0x%x", *txn.ToAddr)
+			}
+			ltc.hub.BroadcastTransaction(TransactionData{
+				Hash:        fmt.Sprintf("0x%x", txn.Hash),
+				FromAddr:    fmt.Sprintf("0x%x", txn.FromAddr),
+				ToAddr:      toAddr,
+				ValueWei:    txn.ValueWei,
+				BlockHeight: domainBlock.Height,
+			})
+		}
 	}
 
 	// AC5: Update metrics
@@ -261,32 +474,29 @@ func (ltc *LiveTailCoordinator) processNextBlock(ctx context.Context) error {
 		slog.Uint64("height", nextHeight),
 		slog.Duration("lag_estimate", lag),
 		slog.Int64("blocks_processed", ltc.blocksProcessed),
+		slog.Int64("chain_head", atomic.LoadInt64(&ltc.chainHead)),
 	)
 
-	return nil
+	return true, nil
 }
 
 // defaultParseRPCBlock converts go-ethereum Block to domain model
-// Stub implementation - full parsing happens in Story 1.5+ (Ingester)
+// Header-only parsing - transactions are extracted by the ingester
 func (ltc *LiveTailCoordinator) defaultParseRPCBlock(rpcBlock *types.Block, height uint64) *Block {
-	return &Block{
-		Height:     height,
-		Hash:       rpcBlock.Hash().Bytes(),
-		ParentHash: rpcBlock.ParentHash().Bytes(),
-		Timestamp:  rpcBlock.Time(),
-		Miner:      rpcBlock.Coinbase().Bytes(),
-		GasUsed:    rpcBlock.GasUsed(),
-		TxCount:    len(rpcBlock.Transactions()),
-	}
+	block := ParseRPCBlockHeader(rpcBlock)
+	block.Height = height
+	return block
 }
 
 // Stats returns live-tail statistics
 func (ltc *LiveTailCoordinator) Stats() map[string]interface{} {
 	return map[string]interface{}{
-		"blocks_processed": atomic.LoadInt64(&ltc.blocksProcessed),
-		"current_height":   atomic.LoadInt64(&ltc.currentHeight),
-		"duration":         time.Since(ltc.startTime),
-		"poll_interval":    ltc.config.PollInterval,
+		"blocks_processed":  atomic.LoadInt64(&ltc.blocksProcessed),
+		"current_height":    atomic.LoadInt64(&ltc.currentHeight),
+		"chain_head":        atomic.LoadInt64(&ltc.chainHead),
+		"duration":          time.Since(ltc.startTime),
+		"poll_interval":     ltc.config.PollInterval,
+		"head_subscription": ltc.headSource != nil,
 	}
 }
 
@@ -9,6 +9,13 @@ import (
 // LiveTailConfig holds configuration for the live-tail coordinator
 type LiveTailConfig struct {
 	PollInterval time.Duration
+	// CatchUpWorkers is the number of heights fetched concurrently while far behind the head
+	// (0 or 1 disables parallel catch-up)
+	CatchUpWorkers int
+	// CatchUpWindow is the number of heights prefetched per catch-up round
+	CatchUpWindow int
+	// CatchUpDistance is the distance to the head below which live-tail fetches one block at a time
+	CatchUpDistance uint64
 }
 
 // NewLiveTailConfig creates a new live-tail configuration from environment variables
@@ -25,8 +32,21 @@ func NewLiveTailConfig() (*LiveTailConfig, error) {
 		// If parsing fails, use default (no error)
 	}
 
+	catchUpWorkers := getEnvInt("LIVETAIL_CATCHUP_WORKERS", 8)
+	if catchUpWorkers < 0 {
+		return nil, fmt.Errorf("LIVETAIL_CATCHUP_WORKERS must be >= 0, got %d", catchUpWorkers)
+	}
+
+	catchUpWindow := getEnvInt("LIVETAIL_CATCHUP_WINDOW", 64)
+	if catchUpWindow <= 0 {
+		return nil, fmt.Errorf("LIVETAIL_CATCHUP_WINDOW must be > 0, got %d", catchUpWindow)
+	}
+
 	return &LiveTailConfig{
-		PollInterval: pollInterval,
+		PollInterval:    pollInterval,
+		CatchUpWorkers:  catchUpWorkers,
+		CatchUpWindow:   catchUpWindow,
+		CatchUpDistance: getEnvUint64("LIVETAIL_CATCHUP_DISTANCE", 16),
 	}, nil
 }
 
@@ -35,6 +55,10 @@ func (c *LiveTailConfig) Validate() error {
 	if c.PollInterval <= 0 {
 		return fmt.Errorf("poll_interval must be > 0, got %v", c.PollInterval)
 	}
+	if c.CatchUpWorkers < 0 || c.CatchUpWindow < 0 {
+		return fmt.Errorf("catchup_workers and catchup_window must be >= 0, got %d and %d",
+			c.CatchUpWorkers, c.CatchUpWindow)
+	}
 	if c.PollInterval > 60*time.Second {
 		// Warn but don't error - user might want a longer interval
 		fmt.Fprintf(os.Stderr, "warning: poll_interval %v is unusually long\n", c.PollInterval)
@@ -45,6 +69,9 @@ func (c *LiveTailConfig) Validate() error {
 // DefaultConfig returns sensible defaults
 func DefaultConfig() *LiveTailConfig {
 	return &LiveTailConfig{
-		PollInterval: 2 * time.Second,
+		PollInterval:    2 * time.Second,
+		CatchUpWorkers:  8,
+		CatchUpWindow:   64,
+		CatchUpDistance: 16,
 	}
 }
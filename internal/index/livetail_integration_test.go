@@ -85,7 +85,7 @@ func (m *IntegrationMockBlockStore) SeedBlocks(blocks []*Block) {
 // IntegrationMockBlockIngester stub for integration tests
 type IntegrationMockBlockIngester struct{}
 
-func (m *IntegrationMockBlockIngester) ParseBlock(rpcBlock *types.Block) (*Block, error) {
+func (m *IntegrationMockBlockIngester) ParseBlock(ctx context.Context, rpcBlock *types.Block) (*Block, error) {
 	return &Block{
 		Height:     rpcBlock.Number().Uint64(),
 		Hash:       rpcBlock.Hash().Bytes(),
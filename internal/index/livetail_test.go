@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -402,6 +403,329 @@ func TestLiveTailCoordinator_Stats(t *testing.T) {
 	assert.Equal(t, coordinator.config.PollInterval, stats["poll_interval"])
 }
 
+// mockHeadSource implements HeadSource for testing
+type mockHeadSource struct {
+	heads     chan uint64
+	err       error
+	callCount int32
+}
+
+func (m *mockHeadSource) Heads(ctx context.Context) (<-chan uint64, error) {
+	atomic.AddInt32(&m.callCount, 1)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.heads, nil
+}
+
+// gatedRPCFetcher reports every block as not found until ready is set
+type gatedRPCFetcher struct {
+	*MockRPCBlockFetcher
+	ready atomic.Bool
+}
+
+func (g *gatedRPCFetcher) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	if !g.ready.Load() {
+		return nil, errors.New("not found")
+	}
+	return g.MockRPCBlockFetcher.GetBlockByNumber(ctx, height)
+}
+
+// Test: a single head signal drains all pending heights
+func TestLiveTailCoordinator_CatchUpDrainsPendingHeights(t *testing.T) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, DefaultConfig())
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC, mockStore, coordinator, 101, 110)
+
+	coordinator.catchUp(context.Background())
+
+	require.Len(t, mockStore.insertedBlocks, 10)
+	assert.Equal(t, uint64(110), mockStore.latestBlock.Height)
+}
+
+// Test: catch-up stops at the first error and resumes on the next signal
+func TestLiveTailCoordinator_CatchUpStopsOnError(t *testing.T) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, DefaultConfig())
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC, mockStore, coordinator, 101, 105)
+	mockRPC.fetchError = func() error { return errors.New("temporary network error") }
+
+	coordinator.catchUp(context.Background())
+	assert.Len(t, mockStore.insertedBlocks, 0)
+
+	mockRPC.fetchError = nil
+	coordinator.catchUp(context.Background())
+	assert.Len(t, mockStore.insertedBlocks, 5)
+}
+
+// Test: head signals from the head source trigger processing without waiting for the poll interval
+func TestLiveTailCoordinator_HeadSource(t *testing.T) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	// Poll interval long enough that only head signals can drive processing
+	config := &LiveTailConfig{PollInterval: time.Hour}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC, mockStore, coordinator, 101, 103)
+
+	source := &mockHeadSource{heads: make(chan uint64, 1)}
+	coordinator.SetHeadSource(source)
+	source.heads <- 103
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- coordinator.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return coordinator.Stats()["chain_head"] == int64(103)
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	assert.Len(t, mockStore.insertedBlocks, 3)
+	assert.Equal(t, true, coordinator.Stats()["head_subscription"])
+}
+
+// Test: a failing head source falls back to polling
+func TestLiveTailCoordinator_HeadSourceFallback(t *testing.T) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: make([]byte, 32), ParentHash: make([]byte, 32)},
+	}
+
+	config := &LiveTailConfig{PollInterval: 5 * time.Millisecond}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	source := &mockHeadSource{err: errors.New("dial failed")}
+	coordinator.SetHeadSource(source)
+
+	// Count polls through the fetcher
+	var polls int32
+	mockRPC.fetchError = func() error {
+		atomic.AddInt32(&polls, 1)
+		return errors.New("not found")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- coordinator.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&polls) > 3
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.callCount), "subscription is retried only after the fallback delay")
+}
+
+// Test: a subscription that stays open but never signals does not stall live-tail
+func TestLiveTailCoordinator_SilentHeadSourceWatchdog(t *testing.T) {
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	// Blocks only become available after the initial catch-up, so the watchdog must pick them up
+	fetcher := &gatedRPCFetcher{MockRPCBlockFetcher: mockRPC}
+	config := &LiveTailConfig{PollInterval: 5 * time.Millisecond}
+	coordinator, err := NewLiveTailCoordinator(fetcher, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC, mockStore, coordinator, 101, 103)
+
+	source := &mockHeadSource{heads: make(chan uint64)}
+	coordinator.SetHeadSource(source)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- coordinator.Start(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	fetcher.ready.Store(true)
+
+	require.Eventually(t, func() bool {
+		return coordinator.Stats()["current_height"] == int64(103)
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.callCount))
+}
+
+// mockHeadRPCFetcher adds chain head reporting and in-flight tracking to MockRPCBlockFetcher
+type mockHeadRPCFetcher struct {
+	*MockRPCBlockFetcher
+	head        uint64
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (m *mockHeadRPCFetcher) BlockNumber(ctx context.Context) (uint64, error) {
+	return m.head, nil
+}
+
+func (m *mockHeadRPCFetcher) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	n := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		seen := atomic.LoadInt32(&m.maxInFlight)
+		if n <= seen || atomic.CompareAndSwapInt32(&m.maxInFlight, seen, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return m.MockRPCBlockFetcher.GetBlockByNumber(ctx, height)
+}
+
+// Test: far behind the head, blocks are prefetched concurrently and committed in height order
+func TestLiveTailCoordinator_ParallelCatchUp(t *testing.T) {
+	mockRPC := &mockHeadRPCFetcher{MockRPCBlockFetcher: &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}, head: 200}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	config := &LiveTailConfig{PollInterval: time.Second, CatchUpWorkers: 4, CatchUpWindow: 16, CatchUpDistance: 5}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC.MockRPCBlockFetcher, mockStore, coordinator, 101, 200)
+
+	coordinator.catchUp(context.Background())
+
+	require.Len(t, mockStore.insertedBlocks, 100)
+	for i, block := range mockStore.insertedBlocks {
+		assert.Equal(t, uint64(101+i), block.Height)
+	}
+	assert.Greater(t, atomic.LoadInt32(&mockRPC.maxInFlight), int32(1), "catch-up should fetch concurrently")
+	assert.Equal(t, int64(200), coordinator.Stats()["chain_head"])
+}
+
+// Test: parallel catch-up stops at a parent hash mismatch and hands it to the reorg handler
+func TestLiveTailCoordinator_ParallelCatchUpReorg(t *testing.T) {
+	mockRPC := &mockHeadRPCFetcher{MockRPCBlockFetcher: &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}, head: 150}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+	reorgHandler := &MockReorgHandler{}
+
+	config := &LiveTailConfig{PollInterval: time.Second, CatchUpWorkers: 4, CatchUpWindow: 32, CatchUpDistance: 5}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, reorgHandler, nil, config)
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC.MockRPCBlockFetcher, mockStore, coordinator, 101, 150)
+	parse := coordinator.parseRPCBlock
+	coordinator.parseRPCBlock = func(rpcBlock *types.Block, height uint64) *Block {
+		block := parse(rpcBlock, height)
+		if height == 110 {
+			block.ParentHash = []byte("fork")
+		}
+		return block
+	}
+
+	coordinator.catchUp(context.Background())
+
+	require.Len(t, mockStore.insertedBlocks, 9)
+	assert.Equal(t, uint64(109), mockStore.latestBlock.Height)
+	require.True(t, reorgHandler.handleReorgCalled)
+	assert.Equal(t, uint64(110), reorgHandler.lastBlock.Height)
+}
+
+// Test: within CatchUpDistance of the head, blocks are fetched one at a time
+func TestLiveTailCoordinator_CatchUpNearHeadIsSequential(t *testing.T) {
+	mockRPC := &mockHeadRPCFetcher{MockRPCBlockFetcher: &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}, head: 110}
+	initialHash := make([]byte, 32)
+	initialHash[0] = 100
+	mockStore := &MockBlockStore{
+		latestBlock: &Block{Height: 100, Hash: initialHash, ParentHash: make([]byte, 32)},
+	}
+
+	config := &LiveTailConfig{PollInterval: time.Second, CatchUpWorkers: 4, CatchUpWindow: 16, CatchUpDistance: 16}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	setupBlockChainForTest(mockRPC.MockRPCBlockFetcher, mockStore, coordinator, 101, 110)
+
+	coordinator.catchUp(context.Background())
+
+	assert.Len(t, mockStore.insertedBlocks, 10)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mockRPC.maxInFlight))
+}
+
+func TestLiveTailConfig_CatchUp(t *testing.T) {
+	t.Setenv("LIVETAIL_CATCHUP_WORKERS", "")
+	t.Setenv("LIVETAIL_CATCHUP_WINDOW", "")
+	t.Setenv("LIVETAIL_CATCHUP_DISTANCE", "")
+
+	config, err := NewLiveTailConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 8, config.CatchUpWorkers)
+	assert.Equal(t, 64, config.CatchUpWindow)
+	assert.Equal(t, uint64(16), config.CatchUpDistance)
+
+	t.Setenv("LIVETAIL_CATCHUP_WORKERS", "0")
+	t.Setenv("LIVETAIL_CATCHUP_DISTANCE", "4")
+	config, err = NewLiveTailConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.CatchUpWorkers)
+	assert.Equal(t, uint64(4), config.CatchUpDistance)
+
+	t.Setenv("LIVETAIL_CATCHUP_WINDOW", "0")
+	_, err = NewLiveTailConfig()
+	assert.Error(t, err)
+}
+
+// Test: polling head source ticks until cancelled
+func TestPollingHeadSource(t *testing.T) {
+	source := NewPollingHeadSource(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	heads, err := source.Heads(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(0), <-heads)
+	cancel()
+
+	// Channel is closed after cancellation
+	for range heads {
+	}
+}
+
 // Helper: generate test block
 func generateTestRPCBlock(height uint64) *types.Block {
 	header := &types.Header{
@@ -469,3 +793,37 @@ func BenchmarkLiveTail_ProcessBlock(b *testing.B) {
 		coordinator.processNextBlock(context.Background())
 	}
 }
+
+// blockingRPCFetcher fails one height and blocks every other fetch until its context is cancelled
+type blockingRPCFetcher struct {
+	failHeight uint64
+}
+
+func (m *blockingRPCFetcher) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	if height == m.failHeight {
+		return nil, errors.New("connection refused")
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("fetch was not cancelled")
+	}
+}
+
+// Test: a failed slot cancels the round instead of waiting for the other fetches to finish
+func TestLiveTailCoordinator_PrefetchRangeCancelsOnError(t *testing.T) {
+	mockRPC := &blockingRPCFetcher{failHeight: 101}
+	mockStore := &MockBlockStore{}
+
+	config := &LiveTailConfig{PollInterval: time.Second, CatchUpWorkers: 4, CatchUpWindow: 16, CatchUpDistance: 5}
+	coordinator, err := NewLiveTailCoordinator(mockRPC, mockStore, nil, nil, nil, config)
+	require.NoError(t, err)
+
+	start := time.Now()
+	committed, err := coordinator.prefetchRange(context.Background(), &Block{Height: 100}, 8)
+	require.Error(t, err)
+	assert.Zero(t, committed)
+	assert.Less(t, time.Since(start), time.Second, "in-flight fetches should be cancelled")
+	assert.Empty(t, mockStore.insertedBlocks)
+}
@@ -0,0 +1,78 @@
+package index
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Current number of backfill workers allowed to fetch concurrently
+	backfillWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_backfill_workers",
+		Help: "Current backfill worker concurrency (adjusted from RPC latency and rate limiting)",
+	})
+
+	// Concurrency adjustments by direction (increase, decrease)
+	backfillWorkerAdjustments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_backfill_worker_adjustments_total",
+		Help: "Total number of backfill concurrency adjustments by direction",
+	}, []string{"direction"})
+
+	// Latest safe and finalized heights reported by RPC
+	finalitySafeHeight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_safe_height",
+		Help: "Height of the latest safe block",
+	})
+	finalityFinalizedHeight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_finalized_height",
+		Help: "Height of the latest finalized block",
+	})
+
+	// Reorgs deeper than REORG_MAX_DEPTH by recovery result (recovered, failed)
+	reorgDeepRecoveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_reorg_deep_recoveries_total",
+		Help: "Total number of automatic deep reorg recoveries by result",
+	}, []string{"result"})
+
+	// Depth of the last deep reorg
+	reorgDeepRecoveryDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_reorg_deep_recovery_depth",
+		Help: "Depth of the last reorg deeper than the maximum depth",
+	})
+
+	// Integrity verifier: stored blocks compared against RPC
+	integrityBlocksChecked = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "explorer_integrity_blocks_checked_total",
+		Help: "Total number of stored blocks compared against RPC by the integrity verifier",
+	})
+
+	// Newly detected mismatches by field (hash, parent_hash, tx_count, tx_hash, parent_link)
+	integrityMismatchesDetected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_integrity_mismatches_detected_total",
+		Help: "Total number of newly detected integrity mismatches by field (open mismatches are counted once)",
+	}, []string{"field"})
+
+	// Heights with unresolved mismatches after the last round
+	integrityOpenHeights = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_integrity_open_mismatch_heights",
+		Help: "Number of heights with unresolved integrity mismatches",
+	})
+
+	// Completion time of the last verification round
+	integrityLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "explorer_integrity_last_run_timestamp_seconds",
+		Help: "Unix time of the last completed integrity verification round",
+	})
+
+	// Token metadata resolver: contracts resolved by standard (ERC-20, ERC-721, ERC-1155, unknown)
+	tokenMetadataResolved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_token_metadata_resolved_total",
+		Help: "Total number of token contracts whose metadata was resolved, by standard",
+	}, []string{"standard"})
+
+	// Resolutions deferred after an RPC failure
+	tokenMetadataFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "explorer_token_metadata_failures_total",
+		Help: "Total number of token metadata resolutions deferred after an RPC failure",
+	})
+)
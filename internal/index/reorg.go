@@ -2,9 +2,12 @@ package index
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
+	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/hieutt50/go-blockchain-explorer/internal/util"
 )
 
@@ -14,6 +17,9 @@ type ReorgHandlerImpl struct {
 	rpcClient RPCBlockFetcher // For fetching blockchain block hashes during fork point discovery
 	store     BlockStoreExtended // Extended interface with MarkBlocksOrphaned method
 	config    *ReorgConfig
+	recorder  ReorgRecorder // Optional: persists reorg events for auditing
+	finality  FinalitySource // Optional: blocks at or below the finalized height are never orphaned
+	ingester  BlockIngester  // Optional: parses blocks re-indexed by deep reorg recovery
 
 	// Metrics (AC5: Observability)
 	reorgDetectedTotal  uint64 // Counter: total reorgs detected
@@ -28,6 +34,37 @@ type BlockStoreExtended interface {
 	// MarkBlocksOrphaned marks all blocks in the height range [startHeight, endHeight] as orphaned
 	// Uses database transaction for atomicity (AC3)
 	MarkBlocksOrphaned(ctx context.Context, startHeight, endHeight uint64) error
+	// InsertBlocks inserts a batch of blocks with their transactions and logs in one database transaction
+	// Used by backfill for bulk writes
+	InsertBlocks(ctx context.Context, blocks []*Block) error
+}
+
+// ReorgEvent describes a handled chain reorganization
+type ReorgEvent struct {
+	DetectedAt      time.Time
+	ForkPointHeight uint64
+	ForkPointHash   []byte // nil when the fork point is the genesis block
+	Depth           uint64
+	OldHeadHeight   uint64 // Database head before the reorg
+	OldHeadHash     []byte
+	NewHeadHeight   uint64 // Block that triggered the reorg
+	NewHeadHash     []byte
+	Blocks          []ReorgBlock // Replaced heights, from fork point + 1 to the old head
+}
+
+// ReorgBlock records the replaced (orphaned) and replacing (canonical) block hash at one height
+type ReorgBlock struct {
+	Height  uint64
+	OldHash []byte
+	NewHash []byte
+}
+
+// errForkPointNotFound is returned when no common ancestor is found within the search depth
+var errForkPointNotFound = errors.New("fork point not found")
+
+// ReorgRecorder persists reorg events (implemented by the storage layer)
+type ReorgRecorder interface {
+	RecordReorg(ctx context.Context, event *ReorgEvent) error
 }
 
 // NewReorgHandler creates a new reorg handler with the provided configuration
@@ -52,6 +89,7 @@ func NewReorgHandler(
 
 	util.Info("reorg handler initialized",
 		"max_depth", config.MaxDepth,
+		"recovery_max_depth", config.RecoveryMaxDepth,
 	)
 
 	return &ReorgHandlerImpl{
@@ -61,10 +99,38 @@ func NewReorgHandler(
 	}, nil
 }
 
+// SetRecorder sets the recorder used to persist reorg events
+// When no recorder is set, reorgs are only logged and counted
+func (rh *ReorgHandlerImpl) SetRecorder(recorder ReorgRecorder) {
+	rh.recorder = recorder
+}
+
+// SetFinality sets the source of the finalized height
+// Reorgs that would orphan blocks at or below it are refused with ErrFinalizedReorg
+func (rh *ReorgHandlerImpl) SetFinality(finality FinalitySource) {
+	rh.finality = finality
+}
+
+// SetIngester sets the block parser used when deep reorg recovery re-indexes the new canonical blocks
+// Use the same ingester as live-tail; without one, blocks are re-indexed header-only
+func (rh *ReorgHandlerImpl) SetIngester(ingester BlockIngester) {
+	rh.ingester = ingester
+}
+
+// finalizedHeight returns the finalized height, 0 when finality is not tracked
+func (rh *ReorgHandlerImpl) finalizedHeight() uint64 {
+	if rh.finality == nil {
+		return 0
+	}
+	return rh.finality.FinalizedHeight()
+}
+
 // HandleReorg is the main entry point for reorg handling triggered by live-tail coordinator
 // Implements AC1: Reorg Detection, AC2: Fork Point Discovery, AC3: Orphaned Block Marking
 // Addresses Task 2: Implement reorg detection
 func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) error {
+	detectedAt := time.Now()
+
 	// AC1: Get current database head for comparison (Task 2.4)
 	dbHead, err := rh.store.GetLatestBlock(ctx)
 	if err != nil {
@@ -91,7 +157,7 @@ func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) er
 	// the initial estimate is incorrect. This check provides early rejection for obviously
 	// deep reorgs without expensive backwards walk.
 	initialDepth := newBlock.Height - dbHead.Height
-	if initialDepth > uint64(rh.config.MaxDepth) {
+	if initialDepth > uint64(rh.config.searchDepth()) {
 		// AC1: Return error if depth exceeds maximum immediately (Task 2.6)
 		util.Error("reorg depth exceeds maximum - manual intervention required",
 			"initial_depth", initialDepth,
@@ -100,21 +166,26 @@ func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) er
 			"db_head_height", dbHead.Height,
 		)
 		return fmt.Errorf("reorg depth (%d) exceeds maximum (%d) - manual intervention required",
-			initialDepth, rh.config.MaxDepth)
+			initialDepth, rh.config.searchDepth())
 	}
 
 	// AC2: Find fork point by walking backwards (Task 3)
-	forkPointHeight, err := rh.findForkPoint(ctx, dbHead)
+	// With recovery enabled the walk continues past MaxDepth, up to RecoveryMaxDepth or the finalized height
+	forkPointHeight, forkPointHash, replaced, err := rh.findForkPoint(ctx, dbHead)
 	if err != nil {
 		util.Error("failed to find fork point",
 			"error", err.Error(),
 			"db_head_height", dbHead.Height,
 		)
+		if len(replaced) > rh.config.MaxDepth {
+			rh.deepRecoveryFailed(dbHead.Height, uint64(len(replaced)), err)
+		}
 		return fmt.Errorf("failed to find fork point: %w", err)
 	}
 
 	// Calculate actual reorg depth
 	actualDepth := dbHead.Height - forkPointHeight
+	deep := actualDepth > uint64(rh.config.MaxDepth)
 
 	util.Info("fork point found",
 		"fork_point_height", forkPointHeight,
@@ -127,8 +198,35 @@ func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) er
 	atomic.AddUint64(&rh.reorgDetectedTotal, 1)
 	atomic.StoreUint64(&rh.reorgDepth, actualDepth)
 
-	// AC3: Mark orphaned blocks from fork point + 1 to current head (Task 4)
-	if forkPointHeight < dbHead.Height {
+	if deep {
+		// Replace the whole divergent range in one step instead of leaving it to live-tail
+		util.Warn("deep reorg detected - starting automatic recovery",
+			"reorg_depth", actualDepth,
+			"max_depth", rh.config.MaxDepth,
+			"fork_point", forkPointHeight,
+			"db_head", dbHead.Height,
+		)
+		reorgDeepRecoveryDepth.Set(float64(actualDepth))
+
+		if err := rh.recoverDeepReorg(ctx, forkPointHeight, forkPointHash, dbHead.Height); err != nil {
+			rh.deepRecoveryFailed(dbHead.Height, actualDepth, err)
+			return fmt.Errorf("failed to recover deep reorg: %w", err)
+		}
+
+		orphanedCount := dbHead.Height - forkPointHeight
+		atomic.AddUint64(&rh.orphanedBlocksTotal, orphanedCount)
+		reorgDeepRecoveries.WithLabelValues("recovered").Inc()
+
+		util.Error("deep reorg recovered - blocks replaced automatically",
+			"alert", "deep_reorg",
+			"reorg_depth", actualDepth,
+			"max_depth", rh.config.MaxDepth,
+			"fork_point", forkPointHeight,
+			"start_height", forkPointHeight+1,
+			"end_height", dbHead.Height,
+		)
+	} else if forkPointHeight < dbHead.Height {
+		// AC3: Mark orphaned blocks from fork point + 1 to current head (Task 4)
 		// There are blocks to mark as orphaned
 		if err := rh.markOrphanedBlocks(ctx, forkPointHeight+1, dbHead.Height); err != nil {
 			util.Error("failed to mark orphaned blocks",
@@ -151,6 +249,28 @@ func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) er
 		)
 	}
 
+	// Persist the reorg event for auditing (best effort, the reorg itself is already handled)
+	if rh.recorder != nil {
+		event := &ReorgEvent{
+			DetectedAt:      detectedAt,
+			ForkPointHeight: forkPointHeight,
+			ForkPointHash:   forkPointHash,
+			Depth:           actualDepth,
+			OldHeadHeight:   dbHead.Height,
+			OldHeadHash:     dbHead.Hash,
+			NewHeadHeight:   newBlock.Height,
+			NewHeadHash:     newBlock.Hash,
+			Blocks:          replaced,
+		}
+		if err := rh.recorder.RecordReorg(ctx, event); err != nil {
+			util.Error("failed to record reorg event",
+				"error", err.Error(),
+				"fork_point", forkPointHeight,
+				"depth", actualDepth,
+			)
+		}
+	}
+
 	// AC4: Return success - live-tail will resume normal processing (Task 5.1)
 	util.Info("reorg handling completed - live-tail will resume from fork point",
 		"fork_point", forkPointHeight,
@@ -161,11 +281,14 @@ func (rh *ReorgHandlerImpl) HandleReorg(ctx context.Context, newBlock *Block) er
 }
 
 // findForkPoint walks backwards from current database head to find common ancestor block
+// Returns the fork point height and hash, and the replaced blocks above it (highest first)
 // Implements AC2: Fork Point Discovery
 // Addresses Task 3: Implement fork point discovery
-func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (uint64, error) {
+func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (uint64, []byte, []ReorgBlock, error) {
 	currentHeight := dbHead.Height
 	searchDepth := 0
+	finalized := rh.finalizedHeight()
+	var replaced []ReorgBlock
 
 	util.Debug("starting fork point search",
 		"start_height", currentHeight,
@@ -173,7 +296,8 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 	)
 
 	// Task 3.2: Walk backwards from current head height
-	for searchDepth <= rh.config.MaxDepth {
+	maxDepth := rh.config.searchDepth()
+	for searchDepth <= maxDepth {
 		// Task 3.7: Log each step of fork point search for debugging
 		util.Debug("checking fork point candidate",
 			"height", currentHeight,
@@ -185,7 +309,7 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 			util.Info("reached genesis block - using as fork point",
 				"fork_point_height", 0,
 			)
-			return 0, nil
+			return 0, nil, replaced, nil
 		}
 
 		// Task 3.3: Fetch blockchain block hash for this height via RPC
@@ -197,7 +321,7 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 				"height", currentHeight,
 				"search_depth", searchDepth,
 			)
-			return 0, fmt.Errorf("failed to fetch blockchain block at height %d: %w", currentHeight, err)
+			return 0, nil, nil, fmt.Errorf("failed to fetch blockchain block at height %d: %w", currentHeight, err)
 		}
 
 		// Task 3.4: Fetch database block hash at same height
@@ -209,7 +333,7 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 				"height", currentHeight,
 				"search_depth", searchDepth,
 			)
-			return 0, fmt.Errorf("failed to fetch database block at height %d: %w", currentHeight, err)
+			return 0, nil, nil, fmt.Errorf("failed to fetch database block at height %d: %w", currentHeight, err)
 		}
 
 		// Task 3.4: Compare blockchain hash with database hash
@@ -221,7 +345,19 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 				"hash", fmt.Sprintf("%x", chainHash),
 				"search_depth", searchDepth,
 			)
-			return currentHeight, nil
+			return currentHeight, chainHash, replaced, nil
+		}
+
+		// A finalized block can never be replaced: the node is on another chain or misbehaving
+		if finalized > 0 && currentHeight <= finalized {
+			util.Error("reorg below finalized height - manual intervention required",
+				"height", currentHeight,
+				"finalized_height", finalized,
+				"chain_hash", fmt.Sprintf("%x", chainHash),
+				"db_hash", fmt.Sprintf("%x", dbBlock.Hash),
+			)
+			return 0, nil, nil, fmt.Errorf("%w: height %d is at or below finalized height %d",
+				ErrFinalizedReorg, currentHeight, finalized)
 		}
 
 		// Hashes don't match - continue searching backwards
@@ -231,6 +367,12 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 			"db_hash", fmt.Sprintf("%x", dbBlock.Hash),
 		)
 
+		replaced = append(replaced, ReorgBlock{
+			Height:  currentHeight,
+			OldHash: dbBlock.Hash,
+			NewHash: chainHash,
+		})
+
 		// Move backwards
 		currentHeight--
 		searchDepth++
@@ -238,11 +380,95 @@ func (rh *ReorgHandlerImpl) findForkPoint(ctx context.Context, dbHead *Block) (u
 
 	// Task 3.6: Return error if max depth exceeded without finding fork point
 	util.Error("fork point not found within max depth",
-		"max_depth", rh.config.MaxDepth,
+		"max_depth", maxDepth,
 		"start_height", dbHead.Height,
 		"end_height", currentHeight,
 	)
-	return 0, fmt.Errorf("fork point not found within max depth (%d blocks)", rh.config.MaxDepth)
+	return 0, nil, replaced, fmt.Errorf("%w within max depth (%d blocks)", errForkPointNotFound, maxDepth)
+}
+
+// recoverDeepReorg replaces the divergent range [forkPointHeight+1, endHeight] with the new canonical blocks
+// All blocks are fetched, checked to link up from the fork point and parsed before anything is
+// written; InsertBlocks then demotes the replaced blocks and inserts the new ones in one database
+// transaction, so the API never sees the range without canonical blocks.
+func (rh *ReorgHandlerImpl) recoverDeepReorg(ctx context.Context, forkPointHeight uint64, forkPointHash []byte, endHeight uint64) error {
+	blocks := make([]*Block, 0, endHeight-forkPointHeight)
+	parentHash := forkPointHash // nil when the fork point is the genesis block
+
+	for height := forkPointHeight + 1; height <= endHeight; height++ {
+		rpcBlock, err := rh.rpcClient.GetBlockByNumber(ctx, height)
+		if err != nil {
+			return fmt.Errorf("failed to fetch canonical block %d: %w", height, err)
+		}
+		if parentHash != nil && !bytesEqual(rpcBlock.ParentHash().Bytes(), parentHash) {
+			// The chain moved again while recovering; the next head retries from scratch
+			return fmt.Errorf("canonical block %d does not extend block %d - chain changed during recovery",
+				height, height-1)
+		}
+
+		block, err := rh.parseBlock(ctx, rpcBlock)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+		parentHash = block.Hash
+	}
+
+	util.Info("re-indexing canonical blocks after deep reorg",
+		"start_height", forkPointHeight+1,
+		"end_height", endHeight,
+		"block_count", len(blocks),
+	)
+
+	if err := rh.store.InsertBlocks(ctx, blocks); err != nil {
+		return fmt.Errorf("failed to replace blocks %d-%d: %w", forkPointHeight+1, endHeight, err)
+	}
+	return nil
+}
+
+// parseBlock converts an RPC block to the domain model using the configured ingester
+// Falls back to header-only parsing when no ingester is set
+func (rh *ReorgHandlerImpl) parseBlock(ctx context.Context, rpcBlock *types.Block) (*Block, error) {
+	if rh.ingester == nil {
+		return ParseRPCBlockHeader(rpcBlock), nil
+	}
+
+	block, err := rh.ingester.ParseBlock(ctx, rpcBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block %d: %w", rpcBlock.NumberU64(), err)
+	}
+	return block, nil
+}
+
+// deepRecoveryFailed counts and logs a reorg deeper than MaxDepth that could not be recovered
+func (rh *ReorgHandlerImpl) deepRecoveryFailed(dbHeadHeight, depth uint64, err error) {
+	reorgDeepRecoveries.WithLabelValues("failed").Inc()
+	util.Error("deep reorg recovery failed - manual intervention required",
+		"alert", "deep_reorg",
+		"error", err.Error(),
+		"reorg_depth", depth,
+		"max_depth", rh.config.MaxDepth,
+		"recovery_max_depth", rh.config.RecoveryMaxDepth,
+		"db_head", dbHeadHeight,
+	)
+}
+
+// OrphanRange marks the canonical blocks in [startHeight, endHeight] as orphaned outside of reorg detection
+// Used by operators to drop a corrupted range before re-indexing it. Blocks at or below the
+// finalized height are refused with ErrFinalizedReorg.
+func (rh *ReorgHandlerImpl) OrphanRange(ctx context.Context, startHeight, endHeight uint64) error {
+	if startHeight > endHeight {
+		return fmt.Errorf("invalid range: start height %d > end height %d", startHeight, endHeight)
+	}
+	if finalized := rh.finalizedHeight(); finalized > 0 && startHeight <= finalized {
+		return fmt.Errorf("%w: height %d is at or below finalized height %d", ErrFinalizedReorg, startHeight, finalized)
+	}
+
+	if err := rh.markOrphanedBlocks(ctx, startHeight, endHeight); err != nil {
+		return err
+	}
+	atomic.AddUint64(&rh.orphanedBlocksTotal, endHeight-startHeight+1)
+	return nil
 }
 
 // markOrphanedBlocks marks all blocks in the range [startHeight, endHeight] as orphaned
@@ -291,5 +517,7 @@ func (rh *ReorgHandlerImpl) Stats() map[string]interface{} {
 		"reorg_depth":           atomic.LoadUint64(&rh.reorgDepth),
 		"orphaned_blocks_total": atomic.LoadUint64(&rh.orphanedBlocksTotal),
 		"max_depth":             rh.config.MaxDepth,
+		"recovery_max_depth":    rh.config.RecoveryMaxDepth,
+		"finalized_height":      rh.finalizedHeight(),
 	}
 }
@@ -10,6 +10,10 @@ import (
 // Addresses Task 6: Add configuration and metrics
 type ReorgConfig struct {
 	MaxDepth int // Maximum reorg depth to handle (default: 6 blocks)
+	// RecoveryMaxDepth is the hard ceiling for automatic recovery of reorgs deeper than MaxDepth
+	// (default: 128 blocks; 0 or a value <= MaxDepth disables recovery). The walk back also stops
+	// at the finalized height when finality is tracked.
+	RecoveryMaxDepth int
 }
 
 // NewReorgConfig creates a new reorg configuration from environment variables
@@ -31,8 +35,14 @@ func NewReorgConfig() (*ReorgConfig, error) {
 		maxDepth = depth
 	}
 
+	recoveryMaxDepth := getEnvInt("REORG_RECOVERY_MAX_DEPTH", 128)
+	if recoveryMaxDepth < 0 {
+		return nil, fmt.Errorf("invalid REORG_RECOVERY_MAX_DEPTH value %d: must be >= 0", recoveryMaxDepth)
+	}
+
 	config := &ReorgConfig{
-		MaxDepth: maxDepth,
+		MaxDepth:         maxDepth,
+		RecoveryMaxDepth: recoveryMaxDepth,
 	}
 
 	// Validate before returning
@@ -49,6 +59,9 @@ func (c *ReorgConfig) Validate() error {
 	if c.MaxDepth <= 0 {
 		return fmt.Errorf("max_depth must be > 0, got %d", c.MaxDepth)
 	}
+	if c.RecoveryMaxDepth < 0 {
+		return fmt.Errorf("recovery_max_depth must be >= 0, got %d", c.RecoveryMaxDepth)
+	}
 	if c.MaxDepth > 100 {
 		// Warn about unusually large max depth (may indicate misconfiguration)
 		// Deep reorgs are rare and may indicate network issues
@@ -57,10 +70,16 @@ func (c *ReorgConfig) Validate() error {
 	return nil
 }
 
+// searchDepth returns how far back the fork point search may walk
+func (c *ReorgConfig) searchDepth() int {
+	return max(c.MaxDepth, c.RecoveryMaxDepth)
+}
+
 // DefaultReorgConfig returns sensible defaults for reorg configuration
 // Addresses Task 6.2: Default configuration values
 func DefaultReorgConfig() *ReorgConfig {
 	return &ReorgConfig{
-		MaxDepth: 6, // Default: handle up to 6 block deep reorgs
+		MaxDepth:         6,   // Default: handle up to 6 block deep reorgs
+		RecoveryMaxDepth: 128, // Default: recover deeper reorgs up to 128 blocks
 	}
 }
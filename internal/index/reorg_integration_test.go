@@ -42,6 +42,15 @@ func (m *IntegrationMockBlockStoreExtended) MarkBlocksOrphaned(ctx context.Conte
 	return nil
 }
 
+func (m *IntegrationMockBlockStoreExtended) InsertBlocks(ctx context.Context, blocks []*Block) error {
+	for _, block := range blocks {
+		if err := m.InsertBlock(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *IntegrationMockBlockStoreExtended) IsOrphaned(height uint64) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +29,8 @@ type MockBlockStoreExtended struct {
 	getByHeightError     error
 	transactionCommitted bool
 	transactionRolledback bool
+	insertedBlocks       []*Block
+	insertBlocksError    error
 }
 
 func (m *MockBlockStoreExtended) GetLatestBlock(ctx context.Context) (*Block, error) {
@@ -40,6 +44,14 @@ func (m *MockBlockStoreExtended) InsertBlock(ctx context.Context, block *Block)
 	return nil // Not used in reorg tests
 }
 
+func (m *MockBlockStoreExtended) InsertBlocks(ctx context.Context, blocks []*Block) error {
+	if m.insertBlocksError != nil {
+		return m.insertBlocksError
+	}
+	m.insertedBlocks = append(m.insertedBlocks, blocks...) // Deep reorg recovery only
+	return nil
+}
+
 func (m *MockBlockStoreExtended) GetBlockByHeight(ctx context.Context, height uint64) (*Block, error) {
 	if m.getByHeightError != nil {
 		return nil, m.getByHeightError
@@ -285,6 +297,7 @@ func TestHandleReorg_DepthExceeded_7Blocks(t *testing.T) {
 	}
 
 	config := DefaultReorgConfig() // max depth = 6
+	config.RecoveryMaxDepth = 0    // Deep reorg recovery disabled
 	handler, err := NewReorgHandler(mockRPC, mockStore, config)
 	require.NoError(t, err)
 
@@ -381,6 +394,279 @@ func TestHandleReorg_TransactionRollback(t *testing.T) {
 	assert.False(t, mockStore.transactionCommitted, "transaction should not be committed on error")
 }
 
+// mockReorgRecorder captures recorded reorg events
+type mockReorgRecorder struct {
+	events []*ReorgEvent
+	err    error
+}
+
+func (m *mockReorgRecorder) RecordReorg(ctx context.Context, event *ReorgEvent) error {
+	m.events = append(m.events, event)
+	return m.err
+}
+
+func TestHandleReorg_RecordsEvent(t *testing.T) {
+	mockStore := &MockBlockStoreExtended{
+		blocksByHeight: make(map[uint64]*Block),
+	}
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+
+	// Fork point at 100, blocks 101-102 replaced
+	setupBlocksForRange(mockStore, mockRPC, 96, 100)
+	for h := uint64(101); h <= 102; h++ {
+		mockRPC.blockCache[h] = generateTestRPCBlockWithHash(h, nil)
+		mockStore.blocksByHeight[h] = &Block{Height: h, Hash: generateHash(h)}
+	}
+	mockStore.latestBlock = mockStore.blocksByHeight[102]
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, DefaultReorgConfig())
+	require.NoError(t, err)
+	recorder := &mockReorgRecorder{}
+	handler.SetRecorder(recorder)
+
+	newBlock := &Block{
+		Height:     103,
+		Hash:       generateHash(103),
+		ParentHash: mockRPC.blockCache[102].Hash().Bytes(),
+	}
+
+	err = handler.HandleReorg(context.Background(), newBlock)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.events, 1)
+	event := recorder.events[0]
+	assert.False(t, event.DetectedAt.IsZero())
+	assert.Equal(t, uint64(100), event.ForkPointHeight)
+	assert.Equal(t, mockRPC.blockCache[100].Hash().Bytes(), event.ForkPointHash)
+	assert.Equal(t, uint64(2), event.Depth)
+	assert.Equal(t, uint64(102), event.OldHeadHeight)
+	assert.Equal(t, generateHash(102), event.OldHeadHash)
+	assert.Equal(t, uint64(103), event.NewHeadHeight)
+	assert.Equal(t, generateHash(103), event.NewHeadHash)
+
+	// Replaced blocks are listed from the old head down, with both hashes
+	require.Len(t, event.Blocks, 2)
+	assert.Equal(t, uint64(102), event.Blocks[0].Height)
+	assert.Equal(t, generateHash(102), event.Blocks[0].OldHash)
+	assert.Equal(t, mockRPC.blockCache[102].Hash().Bytes(), event.Blocks[0].NewHash)
+	assert.Equal(t, uint64(101), event.Blocks[1].Height)
+}
+
+func TestHandleReorg_RecorderErrorDoesNotFailReorg(t *testing.T) {
+	mockStore := &MockBlockStoreExtended{
+		blocksByHeight: make(map[uint64]*Block),
+	}
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+
+	setupBlocksForRange(mockStore, mockRPC, 95, 99)
+	mockRPC.blockCache[100] = generateTestRPCBlockWithHash(100, nil)
+	mockStore.blocksByHeight[100] = &Block{Height: 100, Hash: generateHash(100)}
+	mockStore.latestBlock = mockStore.blocksByHeight[100]
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, DefaultReorgConfig())
+	require.NoError(t, err)
+	recorder := &mockReorgRecorder{err: errors.New("insert failed")}
+	handler.SetRecorder(recorder)
+
+	newBlock := &Block{Height: 101, Hash: generateHash(101), ParentHash: mockRPC.blockCache[100].Hash().Bytes()}
+
+	err = handler.HandleReorg(context.Background(), newBlock)
+	assert.NoError(t, err)
+	assert.True(t, mockStore.markOrphanedCalled)
+	assert.Len(t, recorder.events, 1)
+}
+
+// staticFinality implements FinalitySource with a fixed finalized height
+type staticFinality uint64
+
+func (f staticFinality) FinalizedHeight() uint64 {
+	return uint64(f)
+}
+
+func TestHandleReorg_RefusesReorgBelowFinalized(t *testing.T) {
+	mockStore := &MockBlockStoreExtended{
+		blocksByHeight: make(map[uint64]*Block),
+	}
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+
+	// Fork point at 100, blocks 101-102 replaced
+	setupBlocksForRange(mockStore, mockRPC, 96, 100)
+	for h := uint64(101); h <= 102; h++ {
+		mockRPC.blockCache[h] = generateTestRPCBlockWithHash(h, nil)
+		mockStore.blocksByHeight[h] = &Block{Height: h, Hash: generateHash(h)}
+	}
+	mockStore.latestBlock = mockStore.blocksByHeight[102]
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, DefaultReorgConfig())
+	require.NoError(t, err)
+	recorder := &mockReorgRecorder{}
+	handler.SetRecorder(recorder)
+
+	newBlock := &Block{
+		Height:     103,
+		Hash:       generateHash(103),
+		ParentHash: mockRPC.blockCache[102].Hash().Bytes(),
+	}
+
+	// Block 101 is finalized: nothing is orphaned
+	handler.SetFinality(staticFinality(101))
+	err = handler.HandleReorg(context.Background(), newBlock)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrFinalizedReorg)
+	assert.False(t, mockStore.markOrphanedCalled)
+	assert.Empty(t, recorder.events)
+
+	// Finalized at the fork point: blocks above it can be replaced
+	handler.SetFinality(staticFinality(100))
+	err = handler.HandleReorg(context.Background(), newBlock)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), mockStore.markOrphanedStart)
+	assert.Equal(t, uint64(100), handler.Stats()["finalized_height"])
+}
+
+func TestReorgHandler_OrphanRange(t *testing.T) {
+	mockStore := &MockBlockStoreExtended{}
+	mockRPC := &MockRPCBlockFetcher{}
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, DefaultReorgConfig())
+	require.NoError(t, err)
+	handler.SetFinality(staticFinality(100))
+
+	err = handler.OrphanRange(context.Background(), 100, 110)
+	assert.ErrorIs(t, err, ErrFinalizedReorg)
+	assert.False(t, mockStore.markOrphanedCalled)
+
+	err = handler.OrphanRange(context.Background(), 120, 110)
+	assert.Error(t, err)
+
+	err = handler.OrphanRange(context.Background(), 101, 110)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), mockStore.markOrphanedStart)
+	assert.Equal(t, uint64(110), mockStore.markOrphanedEnd)
+	assert.Equal(t, uint64(10), handler.Stats()["orphaned_blocks_total"])
+}
+
+// setupDeepReorg indexes heights [start, head] in the store and builds a linked RPC chain that
+// shares blocks up to forkPoint and diverges above it
+func setupDeepReorg(start, forkPoint, head uint64) (*MockBlockStoreExtended, *MockRPCBlockFetcher) {
+	mockStore := &MockBlockStoreExtended{blocksByHeight: make(map[uint64]*Block)}
+	mockRPC := &MockRPCBlockFetcher{blockCache: make(map[uint64]*types.Block)}
+
+	var parent common.Hash
+	for h := start; h <= head+1; h++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(h), ParentHash: parent, Time: h}
+		rpcBlock := types.NewBlockWithHeader(header)
+		mockRPC.blockCache[h] = rpcBlock
+		parent = rpcBlock.Hash()
+
+		if h > head {
+			break
+		}
+		hash := rpcBlock.Hash().Bytes()
+		if h > forkPoint {
+			hash = generateHash(h) // Replaced block
+		}
+		mockStore.blocksByHeight[h] = &Block{Height: h, Hash: hash}
+	}
+	mockStore.latestBlock = mockStore.blocksByHeight[head]
+	return mockStore, mockRPC
+}
+
+func TestHandleReorg_DeepReorgRecovery(t *testing.T) {
+	// Fork point at 90, blocks 91-100 replaced (depth 10 > max depth 6)
+	mockStore, mockRPC := setupDeepReorg(80, 90, 100)
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, &ReorgConfig{MaxDepth: 6, RecoveryMaxDepth: 20})
+	require.NoError(t, err)
+	recorder := &mockReorgRecorder{}
+	handler.SetRecorder(recorder)
+
+	recovered := testutil.ToFloat64(reorgDeepRecoveries.WithLabelValues("recovered"))
+
+	newBlock := ParseRPCBlockHeader(mockRPC.blockCache[101])
+	err = handler.HandleReorg(context.Background(), newBlock)
+	require.NoError(t, err)
+
+	// The new canonical blocks replace the whole divergent range in one batch
+	assert.False(t, mockStore.markOrphanedCalled, "replaced blocks are demoted by the batch insert")
+	require.Len(t, mockStore.insertedBlocks, 10)
+	for i, block := range mockStore.insertedBlocks {
+		height := uint64(91 + i)
+		assert.Equal(t, height, block.Height)
+		assert.Equal(t, mockRPC.blockCache[height].Hash().Bytes(), block.Hash)
+	}
+
+	assert.Equal(t, recovered+1, testutil.ToFloat64(reorgDeepRecoveries.WithLabelValues("recovered")))
+	assert.Equal(t, float64(10), testutil.ToFloat64(reorgDeepRecoveryDepth))
+	assert.Equal(t, uint64(10), handler.Stats()["orphaned_blocks_total"])
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, uint64(90), recorder.events[0].ForkPointHeight)
+	assert.Len(t, recorder.events[0].Blocks, 10)
+}
+
+func TestHandleReorg_DeepReorgStopsAtCeiling(t *testing.T) {
+	// Depth 20 exceeds the recovery ceiling of 12
+	mockStore, mockRPC := setupDeepReorg(70, 80, 100)
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, &ReorgConfig{MaxDepth: 6, RecoveryMaxDepth: 12})
+	require.NoError(t, err)
+
+	failed := testutil.ToFloat64(reorgDeepRecoveries.WithLabelValues("failed"))
+
+	err = handler.HandleReorg(context.Background(), ParseRPCBlockHeader(mockRPC.blockCache[101]))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fork point not found within max depth (12 blocks)")
+	assert.Empty(t, mockStore.insertedBlocks)
+	assert.Equal(t, failed+1, testutil.ToFloat64(reorgDeepRecoveries.WithLabelValues("failed")))
+}
+
+func TestHandleReorg_DeepReorgStopsAtFinalized(t *testing.T) {
+	mockStore, mockRPC := setupDeepReorg(80, 90, 100)
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, &ReorgConfig{MaxDepth: 6, RecoveryMaxDepth: 20})
+	require.NoError(t, err)
+	handler.SetFinality(staticFinality(92))
+
+	err = handler.HandleReorg(context.Background(), ParseRPCBlockHeader(mockRPC.blockCache[101]))
+	assert.ErrorIs(t, err, ErrFinalizedReorg)
+	assert.Empty(t, mockStore.insertedBlocks)
+}
+
+func TestHandleReorg_DeepReorgChainChanged(t *testing.T) {
+	mockStore, mockRPC := setupDeepReorg(80, 90, 100)
+
+	// Block 95 no longer extends block 94 when re-indexing
+	mockRPC.blockCache[95] = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(95)})
+
+	handler, err := NewReorgHandler(mockRPC, mockStore, &ReorgConfig{MaxDepth: 6, RecoveryMaxDepth: 20})
+	require.NoError(t, err)
+
+	err = handler.HandleReorg(context.Background(), ParseRPCBlockHeader(mockRPC.blockCache[101]))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chain changed during recovery")
+	assert.Empty(t, mockStore.insertedBlocks, "nothing is written when the new chain does not link up")
+	assert.False(t, mockStore.markOrphanedCalled)
+}
+
+func TestReorgConfig_RecoveryMaxDepth(t *testing.T) {
+	t.Setenv("REORG_MAX_DEPTH", "")
+	t.Setenv("REORG_RECOVERY_MAX_DEPTH", "")
+
+	config, err := NewReorgConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 128, config.RecoveryMaxDepth)
+	assert.Equal(t, 128, config.searchDepth())
+
+	t.Setenv("REORG_RECOVERY_MAX_DEPTH", "0")
+	config, err = NewReorgConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 6, config.searchDepth(), "recovery disabled")
+
+	t.Setenv("REORG_RECOVERY_MAX_DEPTH", "-1")
+	_, err = NewReorgConfig()
+	assert.Error(t, err)
+}
+
 // Test AC5: Configuration and Observability (Task 6)
 // Subtask 7.7: Test configuration validation and loading
 func TestReorgConfig_NewConfig_FromEnv(t *testing.T) {
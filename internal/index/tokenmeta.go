@@ -0,0 +1,393 @@
+package index
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// Token standards detected by the token metadata resolver
+const (
+	TokenStandardERC20   = "ERC-20"
+	TokenStandardERC721  = "ERC-721"
+	TokenStandardERC1155 = "ERC-1155"
+	TokenStandardUnknown = "unknown" // Neither ERC-165 nor ERC-20 calls identify the contract
+)
+
+// ERC-165 interface IDs checked with supportsInterface(bytes4)
+var (
+	interfaceIDERC165  = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	interfaceIDInvalid = [4]byte{0xff, 0xff, 0xff, 0xff} // Must not be supported by an ERC-165 contract
+	interfaceIDERC721  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	interfaceIDERC1155 = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+)
+
+// Function selectors of the token metadata calls
+var (
+	selectorName              = methodSelector("name()")
+	selectorSymbol            = methodSelector("symbol()")
+	selectorDecimals          = methodSelector("decimals()")
+	selectorTotalSupply       = methodSelector("totalSupply()")
+	selectorSupportsInterface = methodSelector("supportsInterface(bytes4)")
+)
+
+// maxTokenStringLength caps the length of names and symbols stored for a contract
+const maxTokenStringLength = 128
+
+// ContractCaller executes read-only contract calls (implemented by the RPC layer)
+// Calls that revert must return an error for which rpc.IsExecutionError reports true
+type ContractCaller interface {
+	CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error)
+}
+
+// TokenMetadataStore reads the queue of token contracts and records their metadata (implemented by the
+// storage layer on the tokens table, which contracts join when their first transfer is indexed)
+type TokenMetadataStore interface {
+	// GetPendingTokens returns up to limit unresolved contracts whose next attempt is due, longest waiting first
+	GetPendingTokens(ctx context.Context, limit int) ([][]byte, error)
+	// SaveTokenMetadata records the metadata of a contract; it is not resolved again
+	SaveTokenMetadata(ctx context.Context, metadata *TokenMetadata) error
+	// DeferToken postpones a contract after a failed attempt; the delay starts at retryDelay and
+	// doubles with every failed attempt
+	DeferToken(ctx context.Context, address []byte, retryDelay time.Duration) error
+}
+
+// TokenMetadata is the metadata of a token or NFT contract read with eth_call
+// Optional functions the contract does not implement are nil.
+type TokenMetadata struct {
+	Address     []byte
+	Standard    string // ERC-20, ERC-721, ERC-1155 or unknown
+	Name        *string
+	Symbol      *string
+	Decimals    *uint8   // ERC-20 only
+	TotalSupply *big.Int // Raw amount at resolution time
+}
+
+// TokenMetadataResolver resolves the metadata of newly seen token contracts in the background
+// ERC-165 supportsInterface classifies ERC-721 and ERC-1155 contracts; other contracts are ERC-20 if they
+// implement totalSupply(). name() and symbol() may return a string or a (non-standard) bytes32.
+// Each contract is resolved once and the result is cached in the database; contracts whose calls fail
+// for RPC reasons are retried with backoff, while reverting calls mean the function is not implemented.
+type TokenMetadataResolver struct {
+	rpcClient ContractCaller
+	store     TokenMetadataStore
+	config    *TokenMetadataConfig
+
+	resolved int64
+	deferred int64
+}
+
+// NewTokenMetadataResolver creates a new token metadata resolver with the provided configuration
+func NewTokenMetadataResolver(rpcClient ContractCaller, store TokenMetadataStore, config *TokenMetadataConfig) (*TokenMetadataResolver, error) {
+	if rpcClient == nil {
+		return nil, fmt.Errorf("rpcClient cannot be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &TokenMetadataResolver{
+		rpcClient: rpcClient,
+		store:     store,
+		config:    config,
+	}, nil
+}
+
+// Run resolves queued contracts every Interval until the context is cancelled
+// Each round drains the queue of contracts that are due. Does nothing when Interval is 0.
+func (r *TokenMetadataResolver) Run(ctx context.Context) {
+	if r.config.Interval <= 0 {
+		return
+	}
+
+	util.Info("token metadata resolver started",
+		"interval", r.config.Interval.String(),
+		"batch_size", r.config.BatchSize,
+	)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			processed, err := r.ResolvePending(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					util.Warn("token metadata resolution failed",
+						"error", err.Error(),
+					)
+				}
+				break
+			}
+			if processed < r.config.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			util.Info("token metadata resolver stopped")
+			return
+		}
+	}
+}
+
+// ResolvePending resolves one batch of queued contracts and returns how many were processed
+// A contract whose calls fail for RPC reasons is deferred and does not fail the batch.
+func (r *TokenMetadataResolver) ResolvePending(ctx context.Context) (int, error) {
+	pending, err := r.store.GetPendingTokens(ctx, r.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending tokens: %w", err)
+	}
+
+	for _, address := range pending {
+		metadata, err := r.Resolve(ctx, common.BytesToAddress(address))
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			util.Warn("failed to resolve token metadata, deferring",
+				"address", common.BytesToAddress(address).Hex(),
+				"error", err.Error(),
+			)
+			if err := r.store.DeferToken(ctx, address, r.config.Interval); err != nil {
+				return 0, fmt.Errorf("failed to defer token %x: %w", address, err)
+			}
+			tokenMetadataFailures.Inc()
+			r.deferred++
+			continue
+		}
+
+		if err := r.store.SaveTokenMetadata(ctx, metadata); err != nil {
+			return 0, fmt.Errorf("failed to save metadata of token %x: %w", address, err)
+		}
+		tokenMetadataResolved.WithLabelValues(metadata.Standard).Inc()
+		r.resolved++
+
+		util.Debug("token metadata resolved",
+			"address", common.BytesToAddress(address).Hex(),
+			"standard", metadata.Standard,
+		)
+	}
+
+	return len(pending), nil
+}
+
+// Resolve reads the metadata of a contract
+// Returns an error only if a call failed for RPC reasons; unimplemented functions are left nil
+func (r *TokenMetadataResolver) Resolve(ctx context.Context, address common.Address) (*TokenMetadata, error) {
+	metadata := &TokenMetadata{
+		Address:  address.Bytes(),
+		Standard: TokenStandardUnknown,
+	}
+
+	standard, err := r.nftStandard(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := r.call(ctx, address, selectorName)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Name = decodeABIString(output)
+
+	output, err = r.call(ctx, address, selectorSymbol)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Symbol = decodeABIString(output)
+
+	output, err = r.call(ctx, address, selectorTotalSupply)
+	if err != nil {
+		return nil, err
+	}
+	metadata.TotalSupply = decodeABIUint256(output)
+
+	if standard != "" {
+		metadata.Standard = standard
+		return metadata, nil
+	}
+
+	output, err = r.call(ctx, address, selectorDecimals)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Decimals = decodeABIUint8(output)
+
+	// totalSupply() is the only required ERC-20 view without arguments
+	if metadata.TotalSupply != nil {
+		metadata.Standard = TokenStandardERC20
+	}
+	return metadata, nil
+}
+
+// nftStandard returns ERC-721 or ERC-1155 if the contract declares the interface through ERC-165, "" otherwise
+// Following ERC-165, the contract must support the ERC-165 interface and reject 0xffffffff.
+func (r *TokenMetadataResolver) nftStandard(ctx context.Context, address common.Address) (string, error) {
+	checks := []struct {
+		interfaceID [4]byte
+		want        bool
+	}{
+		{interfaceIDERC165, true},
+		{interfaceIDInvalid, false},
+	}
+	for _, check := range checks {
+		supported, err := r.supportsInterface(ctx, address, check.interfaceID)
+		if err != nil {
+			return "", err
+		}
+		if supported == nil || *supported != check.want {
+			return "", nil
+		}
+	}
+
+	for _, standard := range []struct {
+		interfaceID [4]byte
+		name        string
+	}{
+		{interfaceIDERC721, TokenStandardERC721},
+		{interfaceIDERC1155, TokenStandardERC1155},
+	} {
+		supported, err := r.supportsInterface(ctx, address, standard.interfaceID)
+		if err != nil {
+			return "", err
+		}
+		if supported != nil && *supported {
+			return standard.name, nil
+		}
+	}
+	return "", nil
+}
+
+// supportsInterface calls supportsInterface(bytes4); nil means the call reverted or returned no bool
+func (r *TokenMetadataResolver) supportsInterface(ctx context.Context, address common.Address, interfaceID [4]byte) (*bool, error) {
+	// bytes4 arguments are left-aligned in their 32-byte word
+	data := make([]byte, 4+32)
+	copy(data, selectorSupportsInterface)
+	copy(data[4:], interfaceID[:])
+
+	output, err := r.call(ctx, address, data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeABIBool(output), nil
+}
+
+// call executes a contract call and returns its output, or nil if the call reverted
+func (r *TokenMetadataResolver) call(ctx context.Context, address common.Address, data []byte) ([]byte, error) {
+	output, err := r.rpcClient.CallContract(ctx, address, data)
+	if err != nil {
+		if rpc.IsExecutionError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to call %x on %s: %w", data[:4], address.Hex(), err)
+	}
+	return output, nil
+}
+
+// Stats returns token metadata resolver statistics for observability
+func (r *TokenMetadataResolver) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"resolved":   r.resolved,
+		"deferred":   r.deferred,
+		"interval":   r.config.Interval.String(),
+		"batch_size": r.config.BatchSize,
+	}
+}
+
+// methodSelector returns the 4-byte selector of a function signature
+func methodSelector(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))[:4]
+}
+
+// decodeABIString decodes a string returned by name() or symbol()
+// Besides ABI-encoded strings, 32-byte outputs are read as a NUL-padded bytes32 (e.g. MKR, SAI).
+// Invalid UTF-8 and NUL characters are dropped and the result is capped at maxTokenStringLength
+// characters; nil is returned for empty or malformed outputs.
+func decodeABIString(output []byte) *string {
+	var raw []byte
+	if len(output) == 32 {
+		raw = output
+	} else {
+		offset, ok := ABIWordUint64(output, 0)
+		if !ok {
+			return nil
+		}
+		length, ok := ABIWordUint64(output, offset)
+		if !ok || length > uint64(len(output))-offset-32 {
+			return nil
+		}
+		raw = output[offset+32 : offset+32+length]
+	}
+
+	s := strings.ReplaceAll(strings.ToValidUTF8(string(raw), ""), "\x00", "")
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(s) > maxTokenStringLength {
+		s = string([]rune(s)[:maxTokenStringLength])
+	}
+	return &s
+}
+
+// decodeABIUint256 decodes a uint256 output; nil for outputs shorter than a word
+func decodeABIUint256(output []byte) *big.Int {
+	if len(output) < 32 {
+		return nil
+	}
+	return new(big.Int).SetBytes(output[:32])
+}
+
+// decodeABIUint8 decodes a uint8 output such as decimals(); nil if the value does not fit
+func decodeABIUint8(output []byte) *uint8 {
+	value := decodeABIUint256(output)
+	if value == nil || !value.IsUint64() || value.Uint64() > 255 {
+		return nil
+	}
+	v := uint8(value.Uint64())
+	return &v
+}
+
+// decodeABIBool decodes a bool output; nil unless the value is 0 or 1
+func decodeABIBool(output []byte) *bool {
+	value := decodeABIUint256(output)
+	if value == nil || value.Cmp(big.NewInt(1)) > 0 {
+		return nil
+	}
+	v := value.Sign() == 1
+	return &v
+}
+
+// ABIWordUint64 reads the 32-byte word at pos of ABI-encoded data as an offset or length
+// It fails if the word is out of bounds or does not fit in a uint64. Shared by the contract call
+// decoders here and the event log decoders of the store.
+func ABIWordUint64(data []byte, pos uint64) (uint64, bool) {
+	if pos > uint64(len(data)) || uint64(len(data))-pos < 32 {
+		return 0, false
+	}
+	word := data[pos : pos+32]
+	for _, b := range word[:24] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return binary.BigEndian.Uint64(word[24:]), true
+}
@@ -0,0 +1,54 @@
+package index
+
+import (
+	"fmt"
+	"time"
+)
+
+// TokenMetadataConfig holds configuration for the token metadata resolver
+type TokenMetadataConfig struct {
+	// Interval is how often queued token contracts are resolved (0 disables the resolver); it is also
+	// the first retry delay of a contract whose calls failed
+	Interval time.Duration
+	// BatchSize is the number of queued contracts read from the database at a time
+	BatchSize int
+}
+
+// NewTokenMetadataConfig creates a new token metadata configuration from environment variables
+// Falls back to sensible defaults if env vars are not set
+func NewTokenMetadataConfig() (*TokenMetadataConfig, error) {
+	interval, err := getEnvDuration("TOKEN_METADATA_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &TokenMetadataConfig{
+		Interval:  interval,
+		BatchSize: getEnvInt("TOKEN_METADATA_BATCH_SIZE", 50),
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *TokenMetadataConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("interval must be >= 0, got %v", c.Interval)
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("batch_size must be >= 1, got %d", c.BatchSize)
+	}
+	return nil
+}
+
+// DefaultTokenMetadataConfig returns sensible defaults for token metadata resolution
+func DefaultTokenMetadataConfig() *TokenMetadataConfig {
+	return &TokenMetadataConfig{
+		Interval:  30 * time.Second,
+		BatchSize: 50,
+	}
+}
@@ -0,0 +1,283 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockContractCaller implements ContractCaller for testing
+// outputs maps call data (hex) to the returned output; unknown calls revert
+type mockContractCaller struct {
+	outputs map[string][]byte
+	err     error // Returned for every call when set
+	calls   int
+}
+
+func (m *mockContractCaller) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	output, ok := m.outputs[common.Bytes2Hex(data)]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return output, nil
+}
+
+// mockTokenMetadataStore implements TokenMetadataStore for testing
+type mockTokenMetadataStore struct {
+	pending  [][]byte
+	saved    []*TokenMetadata
+	deferred [][]byte
+}
+
+func (m *mockTokenMetadataStore) GetPendingTokens(ctx context.Context, limit int) ([][]byte, error) {
+	n := min(limit, len(m.pending))
+	pending := m.pending[:n]
+	m.pending = m.pending[n:]
+	return pending, nil
+}
+
+func (m *mockTokenMetadataStore) SaveTokenMetadata(ctx context.Context, metadata *TokenMetadata) error {
+	m.saved = append(m.saved, metadata)
+	return nil
+}
+
+func (m *mockTokenMetadataStore) DeferToken(ctx context.Context, address []byte, retryDelay time.Duration) error {
+	m.deferred = append(m.deferred, address)
+	return nil
+}
+
+// abiWord encodes an integer as a 32-byte ABI word
+func abiWord(v int64) []byte {
+	return common.BigToHash(big.NewInt(v)).Bytes()
+}
+
+// abiString ABI-encodes a string return value
+func abiString(s string) []byte {
+	padded := make([]byte, (len(s)+31)/32*32)
+	copy(padded, s)
+	output := append(abiWord(32), abiWord(int64(len(s)))...)
+	return append(output, padded...)
+}
+
+// supportsInterfaceCall returns the call data of supportsInterface(interfaceID) as hex
+func supportsInterfaceCall(interfaceID [4]byte) string {
+	data := make([]byte, 36)
+	copy(data, selectorSupportsInterface)
+	copy(data[4:], interfaceID[:])
+	return common.Bytes2Hex(data)
+}
+
+// erc165Outputs returns the supportsInterface outputs of an ERC-165 contract supporting interfaceID
+func erc165Outputs(interfaceID [4]byte) map[string][]byte {
+	return map[string][]byte{
+		supportsInterfaceCall(interfaceIDERC165):  abiWord(1),
+		supportsInterfaceCall(interfaceIDInvalid): abiWord(0),
+		supportsInterfaceCall(interfaceIDERC721):  abiWord(0),
+		supportsInterfaceCall(interfaceIDERC1155): abiWord(0),
+		supportsInterfaceCall(interfaceID):        abiWord(1),
+	}
+}
+
+func newTestResolver(t *testing.T, caller *mockContractCaller, store *mockTokenMetadataStore) *TokenMetadataResolver {
+	config := DefaultTokenMetadataConfig()
+	config.BatchSize = 2
+	resolver, err := NewTokenMetadataResolver(caller, store, config)
+	require.NoError(t, err)
+	return resolver
+}
+
+func TestNewTokenMetadataResolver(t *testing.T) {
+	_, err := NewTokenMetadataResolver(nil, &mockTokenMetadataStore{}, DefaultTokenMetadataConfig())
+	assert.Error(t, err)
+	_, err = NewTokenMetadataResolver(&mockContractCaller{}, nil, DefaultTokenMetadataConfig())
+	assert.Error(t, err)
+	_, err = NewTokenMetadataResolver(&mockContractCaller{}, &mockTokenMetadataStore{}, nil)
+	assert.Error(t, err)
+	_, err = NewTokenMetadataResolver(&mockContractCaller{}, &mockTokenMetadataStore{}, &TokenMetadataConfig{BatchSize: 0})
+	assert.Error(t, err)
+}
+
+func TestTokenMetadataResolver_Resolve(t *testing.T) {
+	address := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+
+	erc721 := erc165Outputs(interfaceIDERC721)
+	erc721[common.Bytes2Hex(selectorName)] = abiString("BoredApeYachtClub")
+	erc721[common.Bytes2Hex(selectorSymbol)] = abiString("BAYC")
+	erc721[common.Bytes2Hex(selectorTotalSupply)] = abiWord(10000)
+
+	tests := []struct {
+		name        string
+		outputs     map[string][]byte
+		standard    string
+		tokenName   *string
+		symbol      *string
+		decimals    *uint8
+		totalSupply *big.Int
+	}{
+		{
+			name: "ERC-20",
+			outputs: map[string][]byte{
+				common.Bytes2Hex(selectorName):        abiString("USD Coin"),
+				common.Bytes2Hex(selectorSymbol):      abiString("USDC"),
+				common.Bytes2Hex(selectorDecimals):    abiWord(6),
+				common.Bytes2Hex(selectorTotalSupply): abiWord(1_000_000),
+			},
+			standard:    TokenStandardERC20,
+			tokenName:   ptr("USD Coin"),
+			symbol:      ptr("USDC"),
+			decimals:    ptr(uint8(6)),
+			totalSupply: big.NewInt(1_000_000),
+		},
+		{
+			name: "ERC-20 with bytes32 name and symbol",
+			outputs: map[string][]byte{
+				common.Bytes2Hex(selectorName):        common.RightPadBytes([]byte("Maker"), 32),
+				common.Bytes2Hex(selectorSymbol):      common.RightPadBytes([]byte("MKR"), 32),
+				common.Bytes2Hex(selectorDecimals):    abiWord(18),
+				common.Bytes2Hex(selectorTotalSupply): abiWord(5),
+			},
+			standard:    TokenStandardERC20,
+			tokenName:   ptr("Maker"),
+			symbol:      ptr("MKR"),
+			decimals:    ptr(uint8(18)),
+			totalSupply: big.NewInt(5),
+		},
+		{
+			name:        "ERC-721",
+			outputs:     erc721,
+			standard:    TokenStandardERC721,
+			tokenName:   ptr("BoredApeYachtClub"),
+			symbol:      ptr("BAYC"),
+			totalSupply: big.NewInt(10000),
+		},
+		{
+			name:     "ERC-1155 without metadata",
+			outputs:  erc165Outputs(interfaceIDERC1155),
+			standard: TokenStandardERC1155,
+		},
+		{
+			name: "ERC-165 contract accepting any interface is not trusted",
+			outputs: map[string][]byte{
+				supportsInterfaceCall(interfaceIDERC165):  abiWord(1),
+				supportsInterfaceCall(interfaceIDInvalid): abiWord(1),
+				supportsInterfaceCall(interfaceIDERC721):  abiWord(1),
+			},
+			standard: TokenStandardUnknown,
+		},
+		{
+			name:     "address without code",
+			outputs:  map[string][]byte{common.Bytes2Hex(selectorName): {}, common.Bytes2Hex(selectorTotalSupply): {}},
+			standard: TokenStandardUnknown,
+		},
+		{
+			name: "out of range decimals",
+			outputs: map[string][]byte{
+				common.Bytes2Hex(selectorDecimals):    abiWord(256),
+				common.Bytes2Hex(selectorTotalSupply): abiWord(1),
+			},
+			standard:    TokenStandardERC20,
+			totalSupply: big.NewInt(1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newTestResolver(t, &mockContractCaller{outputs: tt.outputs}, &mockTokenMetadataStore{})
+
+			metadata, err := resolver.Resolve(context.Background(), address)
+			require.NoError(t, err)
+			assert.Equal(t, address.Bytes(), metadata.Address)
+			assert.Equal(t, tt.standard, metadata.Standard)
+			assert.Equal(t, tt.tokenName, metadata.Name)
+			assert.Equal(t, tt.symbol, metadata.Symbol)
+			assert.Equal(t, tt.decimals, metadata.Decimals)
+			assert.Equal(t, tt.totalSupply, metadata.TotalSupply)
+		})
+	}
+}
+
+func TestTokenMetadataResolver_ResolvePending(t *testing.T) {
+	usdc := common.HexToAddress("0x01").Bytes()
+	other := common.HexToAddress("0x02").Bytes()
+	store := &mockTokenMetadataStore{pending: [][]byte{usdc, other, common.HexToAddress("0x03").Bytes()}}
+	caller := &mockContractCaller{outputs: map[string][]byte{common.Bytes2Hex(selectorTotalSupply): abiWord(1)}}
+	resolver := newTestResolver(t, caller, store)
+
+	processed, err := resolver.ResolvePending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed, "one batch per call")
+	require.Len(t, store.saved, 2)
+	assert.Equal(t, usdc, store.saved[0].Address)
+	assert.Equal(t, TokenStandardERC20, store.saved[1].Standard)
+
+	// RPC failures defer the contract instead of caching a wrong result
+	caller.err = errors.New("connection refused")
+	processed, err = resolver.ResolvePending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Len(t, store.saved, 2)
+	assert.Equal(t, [][]byte{common.HexToAddress("0x03").Bytes()}, store.deferred)
+
+	stats := resolver.Stats()
+	assert.Equal(t, int64(2), stats["resolved"])
+	assert.Equal(t, int64(1), stats["deferred"])
+}
+
+func TestDecodeABIString(t *testing.T) {
+	long := strings.Repeat("a", 200)
+
+	tests := []struct {
+		name   string
+		output []byte
+		want   *string
+	}{
+		{"string", abiString("Wrapped Ether"), ptr("Wrapped Ether")},
+		{"bytes32", common.RightPadBytes([]byte("SAI"), 32), ptr("SAI")},
+		{"NUL characters dropped", abiString("DA\x00I"), ptr("DAI")},
+		{"invalid UTF-8 dropped", abiString("T\xffKN"), ptr("TKN")},
+		{"capped length", abiString(long), ptr(long[:maxTokenStringLength])},
+		{"empty output", nil, nil},
+		{"empty string", abiString(""), nil},
+		{"zero bytes32", make([]byte, 32), nil},
+		{"length past end", append(abiWord(32), abiWord(64)...), nil},
+		{"offset past end", append(abiWord(1024), abiWord(3)...), nil},
+		{"offset overflows uint64", append(common.MaxHash.Bytes(), abiWord(3)...), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeABIString(tt.output))
+		})
+	}
+}
+
+func TestDecodeABIBool(t *testing.T) {
+	assert.Equal(t, ptr(true), decodeABIBool(abiWord(1)))
+	assert.Equal(t, ptr(false), decodeABIBool(abiWord(0)))
+	assert.Nil(t, decodeABIBool(abiWord(2)))
+	assert.Nil(t, decodeABIBool([]byte{0x01}))
+}
+
+func TestMethodSelectors(t *testing.T) {
+	assert.Equal(t, "06fdde03", common.Bytes2Hex(selectorName))
+	assert.Equal(t, "95d89b41", common.Bytes2Hex(selectorSymbol))
+	assert.Equal(t, "313ce567", common.Bytes2Hex(selectorDecimals))
+	assert.Equal(t, "18160ddd", common.Bytes2Hex(selectorTotalSupply))
+	assert.Equal(t, "01ffc9a7", common.Bytes2Hex(selectorSupportsInterface))
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,82 @@
+package index
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Fields compared by CompareBlock, CompareTransactions and the parent-hash continuity check
+const (
+	FieldHash       = "hash"
+	FieldParentHash = "parent_hash"
+	FieldTxCount    = "tx_count"
+	FieldTxHash     = "tx_hash"
+	FieldParentLink = "parent_link" // Stored parent hash differs from the stored hash one height below
+)
+
+// BlockMismatch describes a field of a stored canonical block that differs from the chain
+// For parent_link mismatches Chain holds the stored hash of the canonical block one height below.
+type BlockMismatch struct {
+	Height uint64
+	Field  string
+	Stored string
+	Chain  string
+}
+
+// String formats the mismatch for logs and CLI output
+func (m BlockMismatch) String() string {
+	return fmt.Sprintf("height %d: %s stored=%s chain=%s", m.Height, m.Field, m.Stored, m.Chain)
+}
+
+// CompareBlock compares a stored canonical block with the block the chain reports at the same height
+// Returns nil when they match
+func CompareBlock(stored *Block, chain *types.Block) []BlockMismatch {
+	var mismatches []BlockMismatch
+	add := func(field, storedVal, chainVal string) {
+		if storedVal != chainVal {
+			mismatches = append(mismatches, BlockMismatch{
+				Height: stored.Height,
+				Field:  field,
+				Stored: storedVal,
+				Chain:  chainVal,
+			})
+		}
+	}
+
+	add(FieldHash, fmt.Sprintf("0x%x", stored.Hash), chain.Hash().Hex())
+	add(FieldParentHash, fmt.Sprintf("0x%x", stored.ParentHash), chain.ParentHash().Hex())
+	add(FieldTxCount, strconv.Itoa(stored.TxCount), strconv.Itoa(len(chain.Transactions())))
+	return mismatches
+}
+
+// CompareTransactions compares the stored transaction hashes of a block, in tx_index order, with the
+// chain's transactions and reports the first position that differs (a missing transaction included)
+// stored.Transactions must hold every stored transaction of the block; returns nil when they match
+func CompareTransactions(stored *Block, chain *types.Block) []BlockMismatch {
+	storedTxs := make([]Transaction, len(stored.Transactions))
+	copy(storedTxs, stored.Transactions)
+	slices.SortFunc(storedTxs, func(a, b Transaction) int { return a.TxIndex - b.TxIndex })
+
+	chainTxs := chain.Transactions()
+	for i := range max(len(storedTxs), len(chainTxs)) {
+		storedVal, chainVal := "missing", "missing"
+		if i < len(storedTxs) {
+			storedVal = fmt.Sprintf("0x%x", storedTxs[i].Hash)
+		}
+		if i < len(chainTxs) {
+			chainVal = chainTxs[i].Hash().Hex()
+		}
+		if storedVal != chainVal {
+			return []BlockMismatch{{
+				Height: stored.Height,
+				Field:  FieldTxHash,
+				Stored: fmt.Sprintf("%d:%s", i, storedVal),
+				Chain:  fmt.Sprintf("%d:%s", i, chainVal),
+			}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package index
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareBlock(t *testing.T) {
+	chain := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(100),
+		ParentHash: common.HexToHash("0x99"),
+	})
+
+	stored := ParseRPCBlockHeader(chain)
+	assert.Empty(t, CompareBlock(stored, chain))
+
+	stored.Hash = generateHash(100)
+	stored.TxCount = 3
+	mismatches := CompareBlock(stored, chain)
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "hash", mismatches[0].Field)
+	assert.Equal(t, chain.Hash().Hex(), mismatches[0].Chain)
+	assert.Equal(t, BlockMismatch{Height: 100, Field: "tx_count", Stored: "3", Chain: "0"}, mismatches[1])
+	assert.Equal(t, "height 100: tx_count stored=3 chain=0", mismatches[1].String())
+}
+
+func TestCompareTransactions(t *testing.T) {
+	tx0 := types.NewTx(&types.LegacyTx{Nonce: 0})
+	tx1 := types.NewTx(&types.LegacyTx{Nonce: 1})
+	chain := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(100)}).
+		WithBody(types.Body{Transactions: []*types.Transaction{tx0, tx1}})
+
+	// Stored order does not matter, tx_index does
+	stored := &Block{Height: 100, Transactions: []Transaction{
+		{Hash: tx1.Hash().Bytes(), TxIndex: 1},
+		{Hash: tx0.Hash().Bytes(), TxIndex: 0},
+	}}
+	assert.Empty(t, CompareTransactions(stored, chain))
+
+	// A missing transaction is reported at its position
+	stored.Transactions = []Transaction{{Hash: tx1.Hash().Bytes(), TxIndex: 0}}
+	mismatches := CompareTransactions(stored, chain)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, FieldTxHash, mismatches[0].Field)
+	assert.Equal(t, "0:"+tx1.Hash().Hex(), mismatches[0].Stored)
+	assert.Equal(t, "0:"+tx0.Hash().Hex(), mismatches[0].Chain)
+
+	stored.Transactions = []Transaction{{Hash: tx0.Hash().Bytes(), TxIndex: 0}}
+	mismatches = CompareTransactions(stored, chain)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "1:missing", mismatches[0].Stored)
+}
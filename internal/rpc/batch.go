@@ -0,0 +1,299 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// BatchError reports the items of a batch request that still failed after retries
+// Items are identified by their index in the request; results for all other items are valid
+type BatchError struct {
+	Method string
+	Total  int
+	Failed map[int]error
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	first := e.FailedIndexes()[0]
+	return fmt.Sprintf("%s batch: %d of %d items failed (item %d: %v)",
+		e.Method, len(e.Failed), e.Total, first, e.Failed[first])
+}
+
+// Unwrap returns the error of the first failed item
+func (e *BatchError) Unwrap() error {
+	return e.Failed[e.FailedIndexes()[0]]
+}
+
+// FailedIndexes returns the request indexes of the failed items in ascending order
+func (e *BatchError) FailedIndexes() []int {
+	indexes := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// rpcBlockBody mirrors the body fields of an eth_getBlockByNumber response (as decoded by ethclient)
+type rpcBlockBody struct {
+	Transactions []*types.Transaction `json:"transactions"`
+	UncleHashes  []common.Hash        `json:"uncles"`
+	Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+}
+
+// GetBlocksByNumber fetches several blocks (with transactions) using JSON-RPC batch requests
+// Blocks are returned in request order. Failed items are retried individually with backoff;
+// if some items still fail, the successful blocks are returned together with a *BatchError.
+// Uncle headers are not loaded (the indexer does not use them).
+func (c *Client) GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	blocks := make([]*types.Block, len(heights))
+	args := make([][]interface{}, len(heights))
+	for i, height := range heights {
+		args[i] = []interface{}{hexutil.EncodeUint64(height), true}
+	}
+
+	err := c.batchCall(ctx, "eth_getBlockByNumber", args, func(i int, raw json.RawMessage) error {
+		block, err := decodeBlock(raw)
+		if err != nil {
+			return err
+		}
+		if block.NumberU64() != heights[i] {
+			return fmt.Errorf("node returned block %d for height %d", block.NumberU64(), heights[i])
+		}
+		blocks[i] = block
+		return nil
+	})
+
+	return blocks, err
+}
+
+// GetTransactionReceipts fetches several transaction receipts using JSON-RPC batch requests
+// Receipts are returned in request order, with the same partial-failure semantics as GetBlocksByNumber
+func (c *Client) GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(txHashes))
+	args := make([][]interface{}, len(txHashes))
+	for i, txHash := range txHashes {
+		args[i] = []interface{}{txHash}
+	}
+
+	err := c.batchCall(ctx, "eth_getTransactionReceipt", args, func(i int, raw json.RawMessage) error {
+		var receipt *types.Receipt
+		if err := json.Unmarshal(raw, &receipt); err != nil {
+			return fmt.Errorf("failed to decode receipt: %w", err)
+		}
+		if receipt == nil {
+			return ethereum.NotFound
+		}
+		receipts[i] = receipt
+		return nil
+	})
+
+	return receipts, err
+}
+
+// batchCall sends one method with many argument lists, splitting into chunks of BatchSize
+// Transport failures retry the whole chunk; per-item failures retry only the failed items.
+// Permanent and not-found item errors are not retried.
+func (c *Client) batchCall(
+	ctx context.Context,
+	method string,
+	args [][]interface{},
+	decode func(i int, raw json.RawMessage) error,
+) error {
+	startTime := time.Now()
+	failed := make(map[int]error)
+
+	batchSize := c.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(args)
+	}
+
+	for start := 0; start < len(args); start += batchSize {
+		end := start + batchSize
+		if end > len(args) {
+			end = len(args)
+		}
+
+		pending := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			pending = append(pending, i)
+		}
+
+		if err := c.batchChunk(ctx, method, args, pending, decode, failed); err != nil {
+			return err
+		}
+	}
+
+	duration := time.Since(startTime)
+
+	if len(failed) > 0 {
+		batchErr := &BatchError{Method: method, Total: len(args), Failed: failed}
+		util.Error("batch request failed",
+			"method", method,
+			"items", len(args),
+			"failed_items", len(failed),
+			"error", batchErr.Error(),
+			"duration_ms", duration.Milliseconds(),
+		)
+		return batchErr
+	}
+
+	util.Debug("batch request succeeded",
+		"method", method,
+		"items", len(args),
+		"duration_ms", duration.Milliseconds(),
+	)
+	return nil
+}
+
+// batchChunk runs one chunk of a batch with retries, recording items that finally failed
+func (c *Client) batchChunk(
+	ctx context.Context,
+	method string,
+	args [][]interface{},
+	pending []int,
+	decode func(i int, raw json.RawMessage) error,
+	failed map[int]error,
+) error {
+	itemErrs := make(map[int]error)
+
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoffDelay := calculateBackoff(attempt-1, c.config.RetryBaseDelay)
+			util.Info("retrying failed batch items after backoff",
+				"method", method,
+				"attempt", attempt+1,
+				"items", len(pending),
+				"backoff_duration", backoffDelay.String(),
+			)
+			select {
+			case <-time.After(backoffDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		raws := make([]json.RawMessage, len(pending))
+		elems := make([]gethrpc.BatchElem, len(pending))
+		for j, i := range pending {
+			elems[j] = gethrpc.BatchElem{Method: method, Args: args[i], Result: &raws[j]}
+		}
+
+		done, err := c.begin(ctx, method, len(pending))
+		if err != nil {
+			if IsCircuitOpen(err) {
+				for _, i := range pending {
+					itemErrs[i] = err
+				}
+				break
+			}
+			return err
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+		err = c.ethClient.Client().BatchCallContext(reqCtx, elems)
+		cancel()
+		done(err)
+
+		if err != nil {
+			// Whole request failed (transport error): retry every pending item
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errorType := classifyError(err)
+			util.RecordRPCError(errorTypeToMetricsLabel(errorType))
+			util.Warn("batch request failed",
+				"method", method,
+				"attempt", attempt+1,
+				"items", len(pending),
+				"error_type", errorType.String(),
+				"error", err.Error(),
+			)
+			for _, i := range pending {
+				itemErrs[i] = NewRPCError("batch request failed", err)
+			}
+			if errorType == ErrPermanent {
+				break
+			}
+			continue
+		}
+
+		var retry []int
+		for j, i := range pending {
+			itemErr := elems[j].Error
+			if itemErr == nil {
+				if len(raws[j]) == 0 {
+					raws[j] = json.RawMessage("null")
+				}
+				itemErr = decode(i, raws[j])
+			}
+			if itemErr == nil {
+				delete(itemErrs, i)
+				continue
+			}
+
+			if errors.Is(itemErr, ethereum.NotFound) {
+				itemErrs[i] = itemErr // Not produced yet: retrying within the batch will not help
+				continue
+			}
+
+			errorType := classifyError(itemErr)
+			util.RecordRPCError(errorTypeToMetricsLabel(errorType))
+			itemErrs[i] = NewRPCError("batch item failed", itemErr)
+			if errorType != ErrPermanent {
+				retry = append(retry, i)
+			}
+		}
+
+		pending = retry
+		if len(pending) == 0 {
+			break
+		}
+	}
+
+	for i, err := range itemErrs {
+		failed[i] = err
+	}
+	return nil
+}
+
+// decodeBlock decodes an eth_getBlockByNumber response the same way ethclient does
+// Returns ethereum.NotFound for a null response
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, fmt.Errorf("failed to decode block header: %w", err)
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+
+	var body rpcBlockBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode block body: %w", err)
+	}
+
+	// Quick-verify the transaction list against the header (mirrors ethclient)
+	if head.TxHash == types.EmptyTxsHash && len(body.Transactions) > 0 {
+		return nil, errors.New("server returned non-empty transaction list but block header indicates no transactions")
+	}
+	if head.TxHash != types.EmptyTxsHash && len(body.Transactions) == 0 {
+		return nil, errors.New("server returned empty transaction list but block header indicates transactions")
+	}
+
+	return types.NewBlockWithHeader(head).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	}), nil
+}
@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchService serves eth_getBlockByNumber and eth_getTransactionReceipt for batch tests
+type fakeBatchService struct {
+	mu        sync.Mutex
+	blocks    map[uint64]*types.Block
+	receipts  map[common.Hash]*types.Receipt
+	failures  map[uint64]int // Remaining failures per height
+	permanent map[uint64]bool
+	calls     map[uint64]int
+}
+
+func (s *fakeBatchService) GetBlockByNumber(number hexutil.Uint64, fullTx bool) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	height := uint64(number)
+	s.calls[height]++
+	if s.permanent[height] {
+		return nil, errors.New("invalid block number")
+	}
+	if s.failures[height] > 0 {
+		s.failures[height]--
+		return nil, errors.New("upstream timeout")
+	}
+
+	block, ok := s.blocks[height]
+	if !ok {
+		return nil, nil
+	}
+
+	header, err := block.Header().MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(header, &result); err != nil {
+		return nil, err
+	}
+	result["transactions"] = block.Transactions()
+	result["uncles"] = []common.Hash{}
+	return result, nil
+}
+
+func (s *fakeBatchService) GetTransactionReceipt(txHash common.Hash) (*types.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.receipts[txHash], nil
+}
+
+func newFakeBatchClient(t *testing.T, service *fakeBatchService) *Client {
+	server := gethrpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	cfg := NewConfigWithDefaults(httpServer.URL)
+	cfg.RetryBaseDelay = time.Millisecond
+	cfg.MaxRetries = 2
+	cfg.BatchSize = 2 // Force several chunks
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+// newTestChain builds blocks [1, n] with one signed transaction each, plus their receipts
+func newTestChain(t *testing.T, n uint64) (map[uint64]*types.Block, map[common.Hash]*types.Receipt) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	blocks := make(map[uint64]*types.Block)
+	receipts := make(map[common.Hash]*types.Receipt)
+	for height := uint64(1); height <= n; height++ {
+		to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    height,
+			To:       &to,
+			Value:    big.NewInt(1),
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		}), signer, key)
+		require.NoError(t, err)
+
+		// Any non-empty TxHash marks the block as having transactions (the root is not verified)
+		header := &types.Header{Number: new(big.Int).SetUint64(height), Difficulty: big.NewInt(0), TxHash: tx.Hash()}
+		blocks[height] = types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+		receipts[tx.Hash()] = &types.Receipt{
+			Status:      types.ReceiptStatusSuccessful,
+			TxHash:      tx.Hash(),
+			GasUsed:     21000,
+			Logs:        []*types.Log{},
+			BlockNumber: header.Number,
+		}
+	}
+	return blocks, receipts
+}
+
+func newFakeBatchService(blocks map[uint64]*types.Block, receipts map[common.Hash]*types.Receipt) *fakeBatchService {
+	return &fakeBatchService{
+		blocks:    blocks,
+		receipts:  receipts,
+		failures:  make(map[uint64]int),
+		permanent: make(map[uint64]bool),
+		calls:     make(map[uint64]int),
+	}
+}
+
+func TestClient_GetBlocksByNumber(t *testing.T) {
+	blocks, _ := newTestChain(t, 5)
+	service := newFakeBatchService(blocks, nil)
+	client := newFakeBatchClient(t, service)
+
+	result, err := client.GetBlocksByNumber(context.Background(), []uint64{1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	require.Len(t, result, 5)
+
+	for i, block := range result {
+		want := blocks[uint64(i+1)]
+		assert.Equal(t, want.Hash(), block.Hash())
+		require.Len(t, block.Transactions(), 1)
+		assert.Equal(t, want.Transactions()[0].Hash(), block.Transactions()[0].Hash())
+	}
+}
+
+func TestClient_GetBlocksByNumber_RetriesOnlyFailedItems(t *testing.T) {
+	blocks, _ := newTestChain(t, 4)
+	service := newFakeBatchService(blocks, nil)
+	service.failures[3] = 2 // Fails twice, succeeds on the last retry
+	client := newFakeBatchClient(t, service)
+
+	result, err := client.GetBlocksByNumber(context.Background(), []uint64{1, 2, 3, 4})
+	require.NoError(t, err)
+	assert.Equal(t, blocks[3].Hash(), result[2].Hash())
+
+	assert.Equal(t, 1, service.calls[1])
+	assert.Equal(t, 1, service.calls[4])
+	assert.Equal(t, 3, service.calls[3])
+}
+
+func TestClient_GetBlocksByNumber_PartialFailure(t *testing.T) {
+	blocks, _ := newTestChain(t, 3)
+	service := newFakeBatchService(blocks, nil)
+	service.permanent[2] = true
+	client := newFakeBatchClient(t, service)
+
+	result, err := client.GetBlocksByNumber(context.Background(), []uint64{1, 2, 3, 9})
+	require.Error(t, err)
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{1, 3}, batchErr.FailedIndexes())
+	assert.ErrorIs(t, batchErr.Failed[3], ethereum.NotFound)
+	assert.Equal(t, ErrPermanent, errorClass(batchErr.Failed[1]))
+
+	// Permanent and not-found items are not retried
+	assert.Equal(t, 1, service.calls[2])
+	assert.Equal(t, 1, service.calls[9])
+
+	// Successful items are still returned
+	assert.Equal(t, blocks[1].Hash(), result[0].Hash())
+	assert.Nil(t, result[1])
+	assert.Equal(t, blocks[3].Hash(), result[2].Hash())
+}
+
+func TestClient_GetTransactionReceipts(t *testing.T) {
+	blocks, receipts := newTestChain(t, 3)
+	client := newFakeBatchClient(t, newFakeBatchService(blocks, receipts))
+
+	hashes := []common.Hash{
+		blocks[1].Transactions()[0].Hash(),
+		blocks[2].Transactions()[0].Hash(),
+		blocks[3].Transactions()[0].Hash(),
+	}
+	result, err := client.GetTransactionReceipts(context.Background(), hashes)
+	require.NoError(t, err)
+	for i, receipt := range result {
+		assert.Equal(t, hashes[i], receipt.TxHash)
+		assert.Equal(t, uint64(21000), receipt.GasUsed)
+	}
+
+	_, err = client.GetTransactionReceipts(context.Background(), []common.Hash{common.HexToHash("0xdead")})
+	assert.ErrorIs(t, err, ethereum.NotFound)
+}
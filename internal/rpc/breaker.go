@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// breakerState is the state of a circuit breaker
+type breakerState int
+
+const (
+	// breakerClosed lets every request through and counts consecutive failures
+	breakerClosed breakerState = iota
+	// breakerHalfOpen lets a limited number of probe requests through after the open timeout
+	breakerHalfOpen
+	// breakerOpen rejects every request until the open timeout has passed
+	breakerOpen
+)
+
+// String returns the string representation of breakerState
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half_open"
+	case breakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// errCircuitOpen is the underlying error of requests rejected by an open circuit breaker
+var errCircuitOpen = errors.New("endpoint unavailable")
+
+// IsCircuitOpen reports whether the request was rejected by an open circuit breaker without being sent
+func IsCircuitOpen(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Type == ErrCircuitOpen
+}
+
+// circuitBreaker stops sending requests to an endpoint that keeps failing
+// After FailureThreshold consecutive transient failures the breaker opens and requests fail fast.
+// Once BreakerOpenTimeout has passed it lets BreakerHalfOpenRequests probes through: if they all
+// succeed the breaker closes again, any failure re-opens it.
+type circuitBreaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+	probes      int
+	now         func() time.Time
+
+	mu              sync.Mutex
+	state           breakerState
+	failures        int
+	openedAt        time.Time
+	probesInFlight  int
+	probesSucceeded int
+}
+
+// newCircuitBreaker creates a breaker from the config, or returns nil when the breaker is disabled
+func newCircuitBreaker(name string, config *Config) *circuitBreaker {
+	if config.BreakerFailureThreshold <= 0 {
+		return nil
+	}
+
+	probes := config.BreakerHalfOpenRequests
+	if probes <= 0 {
+		probes = 1
+	}
+
+	b := &circuitBreaker{
+		name:        name,
+		threshold:   config.BreakerFailureThreshold,
+		openTimeout: config.BreakerOpenTimeout,
+		probes:      probes,
+		now:         time.Now,
+	}
+	circuitBreakerState.WithLabelValues(name).Set(float64(breakerClosed))
+	return b
+}
+
+// allow returns a typed RPCError if the request must not be sent
+// A nil breaker allows everything
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return b.openError()
+		}
+		b.transition(breakerHalfOpen)
+	}
+
+	if b.state == breakerHalfOpen {
+		if b.probesInFlight+b.probesSucceeded >= b.probes {
+			return b.openError()
+		}
+		b.probesInFlight++
+	}
+
+	return nil
+}
+
+// record updates the breaker with the result of an allowed request
+// Results of requests cancelled by the caller do not count; not-found and permanent errors
+// count as successes because the endpoint answered.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cancelled := err != nil && ctx.Err() != nil
+	failed := err != nil && !cancelled && !isNotFound(err) && errorClass(err) != ErrPermanent
+
+	switch b.state {
+	case breakerClosed:
+		if cancelled {
+			return
+		}
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+
+	case breakerHalfOpen:
+		if b.probesInFlight > 0 {
+			b.probesInFlight--
+		}
+		if cancelled {
+			return
+		}
+		if failed {
+			b.open()
+			return
+		}
+		b.probesSucceeded++
+		if b.probesSucceeded >= b.probes {
+			b.transition(breakerClosed)
+		}
+
+	case breakerOpen:
+		// Late result of a request admitted before the breaker opened
+	}
+}
+
+// State returns the current breaker state
+func (b *circuitBreaker) State() breakerState {
+	if b == nil {
+		return breakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// open trips the breaker (caller holds mu)
+func (b *circuitBreaker) open() {
+	failures := b.failures
+	b.openedAt = b.now()
+	b.transition(breakerOpen)
+	util.Warn("rpc circuit breaker opened",
+		"endpoint", b.name,
+		"consecutive_failures", failures,
+		"open_timeout", b.openTimeout.String(),
+	)
+}
+
+// transition moves the breaker to a new state and resets its counters (caller holds mu)
+func (b *circuitBreaker) transition(state breakerState) {
+	if state == breakerClosed && b.state != breakerClosed {
+		util.Info("rpc circuit breaker closed", "endpoint", b.name)
+	}
+
+	b.state = state
+	b.failures = 0
+	b.probesInFlight = 0
+	b.probesSucceeded = 0
+
+	circuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+	circuitBreakerTransitions.WithLabelValues(b.name, state.String()).Inc()
+}
+
+// openError builds the fail-fast error returned while the breaker is open (caller holds mu)
+func (b *circuitBreaker) openError() error {
+	circuitBreakerRejections.WithLabelValues(b.name).Inc()
+	return &RPCError{
+		Type:    ErrCircuitOpen,
+		Message: fmt.Sprintf("circuit breaker open for %s", b.name),
+		Err:     errCircuitOpen,
+	}
+}
@@ -0,0 +1,250 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBreaker creates a breaker driven by a fake clock
+func newTestBreaker(threshold, probes int) (*circuitBreaker, *time.Time) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.BreakerFailureThreshold = threshold
+	config.BreakerOpenTimeout = 10 * time.Second
+	config.BreakerHalfOpenRequests = probes
+
+	now := time.Unix(1_700_000_000, 0)
+	b := newCircuitBreaker("test", config)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, 1)
+	ctx := context.Background()
+	failure := errors.New("connection refused")
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.allow())
+		b.record(ctx, failure)
+	}
+	assert.Equal(t, breakerClosed, b.State())
+
+	// A success resets the consecutive failure count
+	require.NoError(t, b.allow())
+	b.record(ctx, nil)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.allow())
+		b.record(ctx, failure)
+	}
+	assert.Equal(t, breakerClosed, b.State())
+
+	require.NoError(t, b.allow())
+	b.record(ctx, failure)
+	assert.Equal(t, breakerOpen, b.State())
+
+	err := b.allow()
+	require.Error(t, err)
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, ErrCircuitOpen, errorClass(err))
+}
+
+func TestCircuitBreaker_IgnoresRequestErrors(t *testing.T) {
+	b, _ := newTestBreaker(1, 1)
+
+	// Not found and permanent errors mean the endpoint answered
+	b.record(context.Background(), ethereum.NotFound)
+	b.record(context.Background(), errors.New("invalid argument"))
+	assert.Equal(t, breakerClosed, b.State())
+
+	// Requests cancelled by the caller say nothing about the endpoint
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.record(ctx, context.Canceled)
+	assert.Equal(t, breakerClosed, b.State())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	b, now := newTestBreaker(1, 2)
+	ctx := context.Background()
+
+	require.NoError(t, b.allow())
+	b.record(ctx, errors.New("connection reset"))
+	require.Equal(t, breakerOpen, b.State())
+
+	// Still open before the timeout
+	*now = now.Add(5 * time.Second)
+	assert.True(t, IsCircuitOpen(b.allow()))
+
+	// After the timeout a limited number of probes get through
+	*now = now.Add(5 * time.Second)
+	require.NoError(t, b.allow())
+	require.NoError(t, b.allow())
+	assert.Equal(t, breakerHalfOpen, b.State())
+	assert.True(t, IsCircuitOpen(b.allow()), "only two probes allowed")
+
+	// All probes must succeed to close the breaker
+	b.record(ctx, nil)
+	assert.Equal(t, breakerHalfOpen, b.State())
+	b.record(ctx, nil)
+	assert.Equal(t, breakerClosed, b.State())
+	assert.NoError(t, b.allow())
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b, now := newTestBreaker(1, 1)
+	ctx := context.Background()
+
+	require.NoError(t, b.allow())
+	b.record(ctx, errors.New("connection reset"))
+
+	*now = now.Add(10 * time.Second)
+	require.NoError(t, b.allow())
+	b.record(ctx, errors.New("connection reset"))
+	assert.Equal(t, breakerOpen, b.State())
+
+	// The open timeout starts again
+	*now = now.Add(5 * time.Second)
+	assert.True(t, IsCircuitOpen(b.allow()))
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.BreakerFailureThreshold = 0
+
+	b := newCircuitBreaker("test", config)
+	assert.Nil(t, b)
+	assert.NoError(t, b.allow())
+	b.record(context.Background(), errors.New("connection refused"))
+	assert.Equal(t, breakerClosed, b.State())
+}
+
+func TestRetryWithBackoff_CircuitOpen(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := &retryConfig{maxRetries: 5, baseDelay: time.Second}
+
+	b, _ := newTestBreaker(1, 1)
+	b.record(context.Background(), errors.New("connection refused"))
+
+	callCount := 0
+	operation := func() error {
+		callCount++
+		return b.allow()
+	}
+
+	start := time.Now()
+	err := retryWithBackoff(context.Background(), cfg, operation, logger, "test-operation")
+
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, 1, callCount, "should not retry while the breaker is open")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestClient_CircuitBreakerFailsFast(t *testing.T) {
+	config := NewConfigWithDefaults("http://127.0.0.1:1") // Nothing listens on port 1
+	config.MaxRetries = 0
+	config.RequestTimeout = time.Second
+	config.BreakerFailureThreshold = 2
+
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := client.BlockNumber(ctx)
+		require.Error(t, err)
+		assert.False(t, IsCircuitOpen(err))
+	}
+
+	_, err = client.GetBlockByNumber(ctx, 1)
+	require.Error(t, err)
+	assert.True(t, IsCircuitOpen(err))
+
+	_, err = client.GetBlocksByNumber(ctx, []uint64{1, 2})
+	require.Error(t, err)
+	assert.True(t, IsCircuitOpen(err))
+}
+
+func TestPool_AllCircuitsOpenFailsFast(t *testing.T) {
+	open := &RPCError{Type: ErrCircuitOpen, Message: "circuit breaker open", Err: errCircuitOpen}
+	a := &fakeEndpoint{err: open}
+	b := &fakeEndpoint{err: open}
+	pool := newTestPool(a, b)
+	pool.baseDelay = time.Second
+
+	start := time.Now()
+	_, err := pool.GetBlockByNumber(context.Background(), 1)
+
+	require.Error(t, err)
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, 1, a.callCount(), "no backoff round when every breaker is open")
+	assert.Equal(t, 1, b.callCount())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestNewConfig_Breaker(t *testing.T) {
+	envKeys := []string{"RPC_URL", "RPC_BREAKER_FAILURE_THRESHOLD", "RPC_BREAKER_OPEN_TIMEOUT", "RPC_BREAKER_HALF_OPEN_REQUESTS"}
+	saved := make(map[string]string)
+	for _, key := range envKeys {
+		saved[key] = os.Getenv(key)
+	}
+	defer func() {
+		for key, val := range saved {
+			os.Setenv(key, val)
+		}
+	}()
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		wantErr       bool
+		wantThreshold int
+		wantTimeout   time.Duration
+		wantProbes    int
+	}{
+		{name: "defaults", env: map[string]string{}, wantThreshold: 5, wantTimeout: 30 * time.Second, wantProbes: 1},
+		{
+			name: "custom",
+			env: map[string]string{
+				"RPC_BREAKER_FAILURE_THRESHOLD":  "10",
+				"RPC_BREAKER_OPEN_TIMEOUT":       "1m",
+				"RPC_BREAKER_HALF_OPEN_REQUESTS": "3",
+			},
+			wantThreshold: 10, wantTimeout: time.Minute, wantProbes: 3,
+		},
+		{name: "disabled", env: map[string]string{"RPC_BREAKER_FAILURE_THRESHOLD": "0"}, wantThreshold: 0, wantTimeout: 30 * time.Second, wantProbes: 1},
+		{name: "invalid threshold", env: map[string]string{"RPC_BREAKER_FAILURE_THRESHOLD": "-1"}, wantErr: true},
+		{name: "invalid timeout", env: map[string]string{"RPC_BREAKER_OPEN_TIMEOUT": "soon"}, wantErr: true},
+		{name: "zero probes", env: map[string]string{"RPC_BREAKER_HALF_OPEN_REQUESTS": "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range envKeys {
+				os.Unsetenv(key)
+			}
+			os.Setenv("RPC_URL", "http://localhost:8545")
+			for key, val := range tt.env {
+				os.Setenv(key, val)
+			}
+
+			config, err := NewConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantThreshold, config.BreakerFailureThreshold)
+			assert.Equal(t, tt.wantTimeout, config.BreakerOpenTimeout)
+			assert.Equal(t, tt.wantProbes, config.BreakerHalfOpenRequests)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// CallContract executes a read-only contract call with eth_call against the latest block and returns its output
+// Calls that revert or fail in the EVM return a permanent error (see IsExecutionError) and are not retried;
+// calls to an address without code succeed with empty output
+func (c *Client) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	startTime := time.Now()
+
+	util.Debug("calling contract",
+		"method", "eth_call",
+		"to", to.Hex(),
+	)
+
+	var output []byte
+	var lastError error
+
+	// Create operation closure for retry logic
+	operation := func() error {
+		done, err := c.begin(ctx, "eth_call", 1)
+		if err != nil {
+			return err
+		}
+
+		// Create context with request timeout
+		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+		defer cancel()
+
+		result, err := c.ethClient.CallContract(reqCtx, ethereum.CallMsg{To: &to, Data: data}, nil)
+		done(err)
+		if err != nil {
+			lastError = err
+			return err
+		}
+
+		output = result
+		return nil
+	}
+
+	// Execute with retry logic
+	retryCfg := &retryConfig{
+		maxRetries: c.config.MaxRetries,
+		baseDelay:  c.config.RetryBaseDelay,
+	}
+
+	err := retryWithBackoff(
+		ctx,
+		retryCfg,
+		operation,
+		util.GlobalLogger,
+		fmt.Sprintf("CallContract(to=%s)", to.Hex()),
+	)
+
+	duration := time.Since(startTime)
+
+	if err != nil {
+		// Reverts are an expected outcome of probing contracts, not RPC failures
+		if IsExecutionError(err) {
+			util.Debug("contract call reverted",
+				"method", "eth_call",
+				"to", to.Hex(),
+				"error", err.Error(),
+			)
+			return nil, err
+		}
+
+		// Record RPC error metrics
+		if lastError != nil {
+			errorType := classifyError(lastError)
+			metricsErrorType := errorTypeToMetricsLabel(errorType)
+			util.RecordRPCError(metricsErrorType)
+		}
+
+		util.Error("failed to call contract",
+			"method", "eth_call",
+			"to", to.Hex(),
+			"error", err.Error(),
+			"duration_ms", duration.Milliseconds(),
+		)
+		return nil, err
+	}
+
+	util.Debug("successfully called contract",
+		"method", "eth_call",
+		"to", to.Hex(),
+		"output_bytes", len(output),
+		"duration_ms", duration.Milliseconds(),
+	)
+
+	return output, nil
+}
+
+// CallContract executes a read-only contract call on the best available provider
+// Execution errors are returned from the first provider, without failover or retries
+func (p *Pool) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	var output []byte
+	err := p.do(ctx, "eth_call", func(prov *provider) error {
+		result, err := prov.endpoint.CallContract(ctx, to, data)
+		if err != nil {
+			return err
+		}
+		output = result
+		return nil
+	})
+	return output, err
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/hieutt50/go-blockchain-explorer/internal/util"
 )
 
@@ -17,6 +19,8 @@ import (
 type Client struct {
 	ethClient *ethclient.Client
 	config    *Config
+	limiter   *rateLimiter    // nil when no client-side limit is configured
+	breaker   *circuitBreaker // nil when the circuit breaker is disabled
 }
 
 // NewClient creates a new RPC client with the provided configuration
@@ -45,9 +49,25 @@ func NewClient(config *Config) (*Client, error) {
 
 	util.Info("successfully connected to ethereum rpc")
 
+	limiter := newRateLimiter(config)
+	if limiter != nil {
+		util.Info("client-side rpc rate limiter enabled",
+			"rate_limit", config.RateLimit,
+			"burst", limiter.burst,
+			"max_concurrency", config.MaxConcurrency,
+		)
+	}
+
+	name := config.Name
+	if name == "" {
+		name = providerNames([]string{config.RPCURL})[0]
+	}
+
 	return &Client{
 		ethClient: ethClient,
 		config:    config,
+		limiter:   limiter,
+		breaker:   newCircuitBreaker(name, config),
 	}, nil
 }
 
@@ -75,16 +95,28 @@ func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.Bl
 
 	var block *types.Block
 	var lastError error
+	notFound := false
 
 	// Create operation closure for retry logic
 	operation := func() error {
+		done, err := c.begin(ctx, "eth_getBlockByNumber", 1)
+		if err != nil {
+			return err
+		}
+
 		// Create context with request timeout
 		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
 		defer cancel()
 
 		// Fetch block with transactions
 		blk, err := c.ethClient.BlockByNumber(reqCtx, big.NewInt(int64(height)))
+		done(err)
 		if err != nil {
+			// Block not produced yet (not an error to retry)
+			if errors.Is(err, ethereum.NotFound) {
+				notFound = true
+				return nil
+			}
 			lastError = err
 			return err
 		}
@@ -109,6 +141,14 @@ func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.Bl
 
 	duration := time.Since(startTime)
 
+	if err == nil && notFound {
+		util.Debug("block not found",
+			"method", "eth_getBlockByNumber",
+			"block_height", height,
+		)
+		return nil, ethereum.NotFound
+	}
+
 	if err != nil {
 		// Record RPC error metrics
 		if lastError != nil {
@@ -155,12 +195,18 @@ func (c *Client) GetTransactionReceipt(ctx context.Context, txHash common.Hash)
 
 	// Create operation closure for retry logic
 	operation := func() error {
+		done, err := c.begin(ctx, "eth_getTransactionReceipt", 1)
+		if err != nil {
+			return err
+		}
+
 		// Create context with request timeout
 		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
 		defer cancel()
 
 		// Fetch transaction receipt
 		rcpt, err := c.ethClient.TransactionReceipt(reqCtx, txHash)
+		done(err)
 		if err != nil {
 			// Check if transaction not found (not an error to retry)
 			if err == ethereum.NotFound {
@@ -211,13 +257,98 @@ func (c *Client) GetTransactionReceipt(ctx context.Context, txHash common.Hash)
 	return receipt, nil
 }
 
+// GetBlockReceipts fetches all transaction receipts of a block with one eth_getBlockReceipts call
+// Uses the same retry logic and error metrics as GetBlockByNumber
+func (c *Client) GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error) {
+	startTime := time.Now()
+
+	util.Debug("fetching block receipts",
+		"method", "eth_getBlockReceipts",
+		"block_height", height,
+	)
+
+	var receipts []*types.Receipt
+	var lastError error
+
+	// Create operation closure for retry logic
+	operation := func() error {
+		done, err := c.begin(ctx, "eth_getBlockReceipts", 1)
+		if err != nil {
+			return err
+		}
+
+		// Create context with request timeout
+		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+		defer cancel()
+
+		blockNumber := gethrpc.BlockNumberOrHashWithNumber(gethrpc.BlockNumber(height))
+		rcpts, err := c.ethClient.BlockReceipts(reqCtx, blockNumber)
+		done(err)
+		if err != nil {
+			lastError = err
+			return err
+		}
+
+		receipts = rcpts
+		return nil
+	}
+
+	// Execute with retry logic
+	retryCfg := &retryConfig{
+		maxRetries: c.config.MaxRetries,
+		baseDelay:  c.config.RetryBaseDelay,
+	}
+
+	err := retryWithBackoff(
+		ctx,
+		retryCfg,
+		operation,
+		util.GlobalLogger,
+		fmt.Sprintf("GetBlockReceipts(height=%d)", height),
+	)
+
+	duration := time.Since(startTime)
+
+	if err != nil {
+		// Record RPC error metrics
+		if lastError != nil {
+			errorType := classifyError(lastError)
+			metricsErrorType := errorTypeToMetricsLabel(errorType)
+			util.RecordRPCError(metricsErrorType)
+		}
+
+		util.Error("failed to fetch block receipts",
+			"method", "eth_getBlockReceipts",
+			"block_height", height,
+			"error", err.Error(),
+			"duration_ms", duration.Milliseconds(),
+		)
+		return nil, err
+	}
+
+	util.Debug("successfully fetched block receipts",
+		"method", "eth_getBlockReceipts",
+		"block_height", height,
+		"receipt_count", len(receipts),
+		"duration_ms", duration.Milliseconds(),
+	)
+
+	return receipts, nil
+}
+
 // ChainID returns the chain ID of the connected network
 // Useful for verifying we're connected to the correct network
 func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	done, err := c.begin(ctx, "eth_chainId", 1)
+	if err != nil {
+		return nil, err
+	}
+
 	reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
 	defer cancel()
 
 	chainID, err := c.ethClient.ChainID(reqCtx)
+	done(err)
 	if err != nil {
 		util.Error("failed to fetch chain id",
 			"error", err.Error(),
@@ -231,3 +362,73 @@ func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
 
 	return chainID, nil
 }
+
+// BlockNumber returns the latest block height known to the endpoint (single attempt, no retry)
+// Used by the provider pool to probe endpoint health and head height
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	done, err := c.begin(ctx, "eth_blockNumber", 1)
+	if err != nil {
+		return 0, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+	defer cancel()
+
+	height, err := c.ethClient.BlockNumber(reqCtx)
+	done(err)
+	return height, err
+}
+
+// FinalityHeights returns the heights of the latest "safe" and "finalized" blocks (single attempt, no retry)
+// Nodes without the post-merge block tags return an error
+func (c *Client) FinalityHeights(ctx context.Context) (uint64, uint64, error) {
+	safe, err := c.taggedHeight(ctx, gethrpc.SafeBlockNumber)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch safe block: %w", err)
+	}
+	finalized, err := c.taggedHeight(ctx, gethrpc.FinalizedBlockNumber)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch finalized block: %w", err)
+	}
+	return safe, finalized, nil
+}
+
+// taggedHeight returns the height of the block header at a block tag such as "finalized"
+func (c *Client) taggedHeight(ctx context.Context, tag gethrpc.BlockNumber) (uint64, error) {
+	done, err := c.begin(ctx, "eth_getBlockByNumber", 1)
+	if err != nil {
+		return 0, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+	defer cancel()
+
+	header, err := c.ethClient.HeaderByNumber(reqCtx, big.NewInt(int64(tag)))
+	done(err)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// begin admits a request of calls JSON-RPC calls through the circuit breaker and rate limiter
+// Fails fast with an ErrCircuitOpen RPCError while the breaker is open. The returned done
+// function must be called with the request's result.
+// Callers start their request timeout only after begin returns, so time spent waiting on
+// the limiter does not count against it.
+func (c *Client) begin(ctx context.Context, method string, calls int) (func(error), error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	release, err := c.limiter.acquire(ctx, method, calls)
+	if err != nil {
+		c.breaker.record(ctx, err)
+		return nil, err
+	}
+
+	return func(err error) {
+		release()
+		c.breaker.record(ctx, err)
+	}, nil
+}
@@ -3,6 +3,8 @@ package rpc
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +13,14 @@ type Config struct {
 	// RPCURL is the Ethereum RPC endpoint URL (from RPC_URL environment variable)
 	RPCURL string
 
+	// RPCURLs lists every RPC endpoint used by the provider pool, starting with RPCURL
+	// Additional endpoints come from the comma-separated RPC_URLS environment variable
+	RPCURLs []string
+
+	// WSURL is the WebSocket RPC endpoint used for eth_subscribe (from RPC_WS_URL environment variable)
+	// Defaults to RPCURL when it is a ws:// or wss:// URL; empty disables head subscriptions
+	WSURL string
+
 	// ConnectionTimeout is the timeout for establishing RPC connections (default: 10s)
 	ConnectionTimeout time.Duration
 
@@ -22,22 +32,136 @@ type Config struct {
 
 	// RetryBaseDelay is the base delay for exponential backoff (default: 1s)
 	RetryBaseDelay time.Duration
+
+	// BatchSize is the maximum number of calls per JSON-RPC batch request (default: 50, RPC_BATCH_SIZE)
+	BatchSize int
+
+	// RateLimit is the client-side request budget in cost units per second (RPC_RATE_LIMIT, 0 = unlimited)
+	// Every call costs one unit unless MethodCosts says otherwise; batches cost the sum of their calls
+	RateLimit float64
+
+	// RateBurst is the token bucket size in cost units (RPC_RATE_BURST, default: RateLimit)
+	RateBurst int
+
+	// MethodCosts maps RPC methods to their cost in units, e.g. provider compute units
+	// (RPC_METHOD_COSTS, comma-separated method=cost pairs)
+	MethodCosts map[string]float64
+
+	// MaxConcurrency caps in-flight requests per endpoint (RPC_MAX_CONCURRENCY, 0 = unlimited)
+	MaxConcurrency int
+
+	// Name identifies the endpoint in logs and metrics (default: host of RPCURL)
+	Name string
+
+	// BreakerFailureThreshold is the number of consecutive failures that opens the endpoint's
+	// circuit breaker (default: 5, RPC_BREAKER_FAILURE_THRESHOLD, 0 = disabled)
+	BreakerFailureThreshold int
+
+	// BreakerOpenTimeout is how long an open breaker rejects requests before probing the endpoint
+	// again (default: 30s, RPC_BREAKER_OPEN_TIMEOUT)
+	BreakerOpenTimeout time.Duration
+
+	// BreakerHalfOpenRequests is the number of probe requests that must succeed to close the
+	// breaker again (default: 1, RPC_BREAKER_HALF_OPEN_REQUESTS)
+	BreakerHalfOpenRequests int
 }
 
 // NewConfig creates a new Config with default values
-// RPCURL is read from RPC_URL environment variable
+// RPCURL is read from RPC_URL environment variable, extra pool endpoints from RPC_URLS
 func NewConfig() (*Config, error) {
 	rpcURL := os.Getenv("RPC_URL")
-	if rpcURL == "" {
+	rpcURLs := parseURLList(rpcURL, os.Getenv("RPC_URLS"))
+	if len(rpcURLs) == 0 {
 		return nil, fmt.Errorf("RPC_URL environment variable not set")
 	}
+	if rpcURL == "" {
+		rpcURL = rpcURLs[0]
+	}
+
+	wsURL := os.Getenv("RPC_WS_URL")
+	if wsURL == "" && isWebSocketURL(rpcURL) {
+		wsURL = rpcURL
+	}
+	if wsURL != "" && !isWebSocketURL(wsURL) {
+		return nil, fmt.Errorf("RPC_WS_URL must be a ws:// or wss:// URL")
+	}
+
+	batchSize := 50
+	if batchSizeStr := os.Getenv("RPC_BATCH_SIZE"); batchSizeStr != "" {
+		size, err := strconv.Atoi(batchSizeStr)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("RPC_BATCH_SIZE must be a positive integer, got %q", batchSizeStr)
+		}
+		batchSize = size
+	}
+
+	rateLimit := 0.0
+	if rateLimitStr := os.Getenv("RPC_RATE_LIMIT"); rateLimitStr != "" {
+		limit, err := strconv.ParseFloat(rateLimitStr, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("RPC_RATE_LIMIT must be a non-negative number, got %q", rateLimitStr)
+		}
+		rateLimit = limit
+	}
+
+	rateBurst, err := parseNonNegativeInt("RPC_RATE_BURST")
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrency, err := parseNonNegativeInt("RPC_MAX_CONCURRENCY")
+	if err != nil {
+		return nil, err
+	}
+
+	methodCosts, err := parseMethodCosts(os.Getenv("RPC_METHOD_COSTS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC_METHOD_COSTS: %w", err)
+	}
+
+	breakerThreshold := 5
+	if os.Getenv("RPC_BREAKER_FAILURE_THRESHOLD") != "" {
+		breakerThreshold, err = parseNonNegativeInt("RPC_BREAKER_FAILURE_THRESHOLD")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	breakerOpenTimeout := 30 * time.Second
+	if timeoutStr := os.Getenv("RPC_BREAKER_OPEN_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("RPC_BREAKER_OPEN_TIMEOUT must be a positive duration, got %q", timeoutStr)
+		}
+		breakerOpenTimeout = timeout
+	}
+
+	breakerHalfOpen := 1
+	if os.Getenv("RPC_BREAKER_HALF_OPEN_REQUESTS") != "" {
+		breakerHalfOpen, err = parseNonNegativeInt("RPC_BREAKER_HALF_OPEN_REQUESTS")
+		if err != nil || breakerHalfOpen == 0 {
+			return nil, fmt.Errorf("RPC_BREAKER_HALF_OPEN_REQUESTS must be a positive integer, got %q",
+				os.Getenv("RPC_BREAKER_HALF_OPEN_REQUESTS"))
+		}
+	}
 
 	return &Config{
 		RPCURL:            rpcURL,
+		RPCURLs:           rpcURLs,
+		WSURL:             wsURL,
 		ConnectionTimeout: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
 		MaxRetries:        5,
 		RetryBaseDelay:    1 * time.Second,
+		BatchSize:         batchSize,
+		RateLimit:         rateLimit,
+		RateBurst:         rateBurst,
+		MethodCosts:       methodCosts,
+		MaxConcurrency:    maxConcurrency,
+
+		BreakerFailureThreshold: breakerThreshold,
+		BreakerOpenTimeout:      breakerOpenTimeout,
+		BreakerHalfOpenRequests: breakerHalfOpen,
 	}, nil
 }
 
@@ -46,9 +170,70 @@ func NewConfig() (*Config, error) {
 func NewConfigWithDefaults(rpcURL string) *Config {
 	return &Config{
 		RPCURL:            rpcURL,
+		RPCURLs:           []string{rpcURL},
 		ConnectionTimeout: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
 		MaxRetries:        5,
 		RetryBaseDelay:    1 * time.Second,
+		BatchSize:         50,
+
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      30 * time.Second,
+		BreakerHalfOpenRequests: 1,
+	}
+}
+
+// isWebSocketURL reports whether the URL uses the ws:// or wss:// scheme
+func isWebSocketURL(url string) bool {
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// parseNonNegativeInt reads an optional non-negative integer environment variable (0 when unset)
+func parseNonNegativeInt(key string) (int, error) {
+	valStr := os.Getenv(key)
+	if valStr == "" {
+		return 0, nil
+	}
+	val, err := strconv.Atoi(valStr)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, valStr)
+	}
+	return val, nil
+}
+
+// parseMethodCosts parses comma-separated method=cost pairs, e.g. "eth_getBlockReceipts=20,eth_call=5"
+func parseMethodCosts(list string) (map[string]float64, error) {
+	costs := make(map[string]float64)
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, costStr, ok := strings.Cut(pair, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" {
+			return nil, fmt.Errorf("expected method=cost, got %q", pair)
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(costStr), 64)
+		if err != nil || cost < 0 {
+			return nil, fmt.Errorf("cost for %s must be a non-negative number, got %q", method, costStr)
+		}
+		costs[method] = cost
+	}
+	return costs, nil
+}
+
+// parseURLList combines the primary URL with a comma-separated list, dropping blanks and duplicates
+func parseURLList(primary, list string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, url := range append([]string{primary}, strings.Split(list, ",")...) {
+		url = strings.TrimSpace(url)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
 	}
+	return urls
 }
@@ -20,6 +20,9 @@ const (
 
 	// ErrRateLimit represents rate limiting errors (HTTP 429, quota exceeded)
 	ErrRateLimit
+
+	// ErrCircuitOpen represents requests rejected without being sent because the endpoint's circuit breaker is open
+	ErrCircuitOpen
 )
 
 // String returns the string representation of ErrorType
@@ -31,6 +34,8 @@ func (e ErrorType) String() string {
 		return "permanent"
 	case ErrRateLimit:
 		return "rate_limit"
+	case ErrCircuitOpen:
+		return "circuit_open"
 	default:
 		return "unknown"
 	}
@@ -89,9 +94,15 @@ func classifyError(err error) ErrorType {
 		return ErrTransient
 	}
 
+	// Check for EVM execution errors of eth_call (permanent - the call fails the same way on every attempt)
+	if isExecutionErrorMessage(errStrLower) {
+		return ErrPermanent
+	}
+
 	// Check for permanent errors (invalid parameters, method not found)
 	if strings.Contains(errStrLower, "invalid") ||
 		strings.Contains(errStrLower, "method not found") ||
+		strings.Contains(errStrLower, "does not exist/is not available") ||
 		strings.Contains(errStrLower, "missing required") ||
 		strings.Contains(errStrLower, "malformed") ||
 		strings.Contains(errStrLower, "parse error") {
@@ -151,3 +162,45 @@ func errorTypeToMetricsLabel(errType ErrorType) string {
 		return "other"
 	}
 }
+
+// IsRateLimit reports whether the error was caused by provider rate limiting (HTTP 429, quota exceeded)
+func IsRateLimit(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Type == ErrRateLimit
+	}
+	return classifyError(err) == ErrRateLimit
+}
+
+// executionErrorMessages are error messages of eth_call requests that failed in the EVM rather than in the RPC layer
+var executionErrorMessages = []string{
+	"execution reverted",
+	"out of gas",
+	"invalid opcode",
+	"invalid jump destination",
+	"stack underflow",
+	"stack overflow",
+	"write protection",
+}
+
+// isExecutionErrorMessage reports whether a lower-cased error message is an EVM execution error
+func isExecutionErrorMessage(errStrLower string) bool {
+	for _, msg := range executionErrorMessages {
+		if strings.Contains(errStrLower, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExecutionError reports whether an eth_call failed because the call reverted or hit an EVM error
+// (e.g. an unimplemented function) rather than because of the provider
+func IsExecutionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return isExecutionErrorMessage(strings.ToLower(err.Error()))
+}
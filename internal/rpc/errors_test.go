@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"syscall"
 	"testing"
@@ -18,6 +19,7 @@ func TestErrorType_String(t *testing.T) {
 		{"transient", ErrTransient, "transient"},
 		{"permanent", ErrPermanent, "permanent"},
 		{"rate_limit", ErrRateLimit, "rate_limit"},
+		{"circuit_open", ErrCircuitOpen, "circuit_open"},
 		{"unknown", ErrorType(999), "unknown"},
 	}
 
@@ -55,6 +57,7 @@ func TestClassifyError_Permanent(t *testing.T) {
 	}{
 		{"invalid parameter", errors.New("invalid block height parameter")},
 		{"method not found", errors.New("method eth_invalidMethod not found")},
+		{"method not available", errors.New("the method debug_traceBlockByNumber does not exist/is not available")},
 		{"missing required", errors.New("missing required field: address")},
 		{"malformed", errors.New("malformed JSON-RPC request")},
 		{"parse error", errors.New("parse error: invalid hex string")},
@@ -160,6 +163,28 @@ func TestRPCError(t *testing.T) {
 	})
 }
 
+func TestIsRateLimit(t *testing.T) {
+	assert.False(t, IsRateLimit(nil))
+	assert.True(t, IsRateLimit(errors.New("429 Too Many Requests")))
+	assert.True(t, IsRateLimit(NewRPCError("max retries exceeded", errors.New("rate limit exceeded"))))
+	assert.True(t, IsRateLimit(fmt.Errorf("batch item: %w", NewRPCError("max retries exceeded", errors.New("quota exceeded")))))
+	assert.False(t, IsRateLimit(NewRPCError("max retries exceeded", errors.New("connection reset"))))
+	assert.False(t, IsRateLimit(&RPCError{Type: ErrCircuitOpen, Message: "rate limit", Err: errCircuitOpen}))
+}
+
+func TestIsExecutionError(t *testing.T) {
+	assert.False(t, IsExecutionError(nil))
+	assert.True(t, IsExecutionError(errors.New("execution reverted")))
+	assert.True(t, IsExecutionError(NewRPCError("permanent error, not retrying", errors.New("execution reverted: Ownable: caller is not the owner"))))
+	assert.True(t, IsExecutionError(fmt.Errorf("eth_call failed on all 2 providers: %w", errors.New("invalid opcode: INVALID"))))
+	assert.True(t, IsExecutionError(errors.New("out of gas")))
+	assert.False(t, IsExecutionError(errors.New("connection refused")))
+	assert.False(t, IsExecutionError(errors.New("429 Too Many Requests")))
+
+	// Reverts are permanent so they are neither retried nor counted against the provider
+	assert.Equal(t, ErrPermanent, classifyError(errors.New("execution reverted")))
+}
+
 // testNetError is a helper type that implements net.Error for testing
 type testNetError struct {
 	timeout   bool
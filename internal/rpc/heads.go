@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// headBufferSize is the number of pending head notifications buffered per subscription
+const headBufferSize = 16
+
+// HeadSubscriber delivers new chain heads using eth_subscribe("newHeads") over a WebSocket endpoint
+type HeadSubscriber struct {
+	config *Config
+}
+
+// NewHeadSubscriber creates a new head subscriber for the configured WebSocket URL
+func NewHeadSubscriber(config *Config) (*HeadSubscriber, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if !isWebSocketURL(config.WSURL) {
+		return nil, fmt.Errorf("head subscriptions require a ws:// or wss:// URL")
+	}
+
+	return &HeadSubscriber{config: config}, nil
+}
+
+// Heads subscribes to new heads and returns a channel of head heights
+// The channel is closed when the context is cancelled or the subscription is lost.
+// Heads are not queued indefinitely: if the consumer falls behind, intermediate heights are dropped,
+// since each height only signals that the chain has advanced.
+func (s *HeadSubscriber) Heads(ctx context.Context) (<-chan uint64, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, s.config.ConnectionTimeout)
+	defer cancel()
+
+	ethClient, err := ethclient.DialContext(dialCtx, s.config.WSURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to websocket endpoint: %w", err)
+	}
+
+	headers := make(chan *types.Header, headBufferSize)
+	sub, err := ethClient.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		ethClient.Close()
+		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+
+	util.Info("subscribed to new heads",
+		"method", "eth_subscribe",
+		"url_length", len(s.config.WSURL), // Don't log full URL (may contain API key)
+	)
+
+	heads := make(chan uint64, headBufferSize)
+	go func() {
+		defer close(heads)
+		defer ethClient.Close()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err != nil {
+					util.Warn("new heads subscription lost", "error", err.Error())
+				}
+				return
+			case header := <-headers:
+				select {
+				case heads <- header.Number.Uint64():
+				default:
+					util.Debug("dropping head notification, consumer is behind",
+						"height", header.Number.Uint64(),
+					)
+				}
+			}
+		}
+	}()
+
+	return heads, nil
+}
@@ -0,0 +1,130 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthService serves eth_subscribe("newHeads") with a fixed sequence of heads
+type fakeEthService struct {
+	heights []uint64
+}
+
+func (s *fakeEthService) NewHeads(ctx context.Context) (*gethrpc.Subscription, error) {
+	notifier, ok := gethrpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, gethrpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		for _, height := range s.heights {
+			header := &types.Header{Number: new(big.Int).SetUint64(height), Difficulty: big.NewInt(0)}
+			if err := notifier.Notify(sub.ID, header); err != nil {
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+func newFakeHeadServer(t *testing.T, heights []uint64) string {
+	server := gethrpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{heights: heights}))
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	return "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func TestNewConfig_WSURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		rpcURL  string
+		wsURL   string
+		want    string
+		wantErr bool
+	}{
+		{name: "http only", rpcURL: "https://rpc.example", want: ""},
+		{name: "ws rpc url", rpcURL: "wss://rpc.example", want: "wss://rpc.example"},
+		{name: "explicit ws url", rpcURL: "https://rpc.example", wsURL: "ws://localhost:8546", want: "ws://localhost:8546"},
+		{name: "invalid ws url", rpcURL: "https://rpc.example", wsURL: "https://rpc.example", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RPC_URL", tt.rpcURL)
+			t.Setenv("RPC_WS_URL", tt.wsURL)
+
+			cfg, err := NewConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.WSURL)
+		})
+	}
+}
+
+func TestNewHeadSubscriber_RequiresWebSocketURL(t *testing.T) {
+	_, err := NewHeadSubscriber(nil)
+	assert.Error(t, err)
+
+	_, err = NewHeadSubscriber(NewConfigWithDefaults("https://rpc.example"))
+	assert.Error(t, err)
+}
+
+func TestHeadSubscriber_Heads(t *testing.T) {
+	cfg := NewConfigWithDefaults("http://unused")
+	cfg.WSURL = newFakeHeadServer(t, []uint64{100, 101, 102})
+
+	subscriber, err := NewHeadSubscriber(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	heads, err := subscriber.Heads(ctx)
+	require.NoError(t, err)
+
+	for _, want := range []uint64{100, 101, 102} {
+		select {
+		case got := <-heads:
+			assert.Equal(t, want, got)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for head %d", want)
+		}
+	}
+
+	// Channel is closed after cancellation
+	cancel()
+	for range heads {
+	}
+}
+
+func TestHeadSubscriber_DialFailure(t *testing.T) {
+	cfg := NewConfigWithDefaults("http://unused")
+	cfg.WSURL = "ws://127.0.0.1:1"
+	cfg.ConnectionTimeout = time.Second
+
+	subscriber, err := NewHeadSubscriber(cfg)
+	require.NoError(t, err)
+
+	_, err = subscriber.Heads(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Requests sent to each provider by method and outcome
+	providerRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_rpc_provider_requests_total",
+		Help: "Total number of RPC requests per provider by method and status",
+	}, []string{"provider", "method", "status"})
+
+	// Request latency per provider
+	providerRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "explorer_rpc_provider_request_duration_seconds",
+		Help:    "RPC request duration per provider (seconds)",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0},
+	}, []string{"provider", "method"})
+
+	// Errors per provider by error class
+	providerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_rpc_provider_errors_total",
+		Help: "Total number of RPC errors per provider by type",
+	}, []string{"provider", "error_type"})
+
+	// Whether each provider is currently eligible for requests (1 healthy, 0 cooling down)
+	providerHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "explorer_rpc_provider_healthy",
+		Help: "Whether the RPC provider is healthy (1) or cooling down (0)",
+	}, []string{"provider"})
+
+	// Latest head height reported by each provider
+	providerHeadHeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "explorer_rpc_provider_head_height",
+		Help: "Latest block height reported by the RPC provider",
+	}, []string{"provider"})
+
+	// Current selection score of each provider (lower is preferred)
+	providerScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "explorer_rpc_provider_score",
+		Help: "RPC provider selection score (lower is better)",
+	}, []string{"provider"})
+
+	// Time requests spent waiting for the client-side rate limiter
+	rateLimiterWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "explorer_rpc_rate_limiter_wait_seconds",
+		Help:    "Time RPC requests waited for the client-side rate limiter (seconds)",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0},
+	}, []string{"method"})
+
+	// Circuit breaker state per endpoint (0 closed, 1 half-open, 2 open)
+	circuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "explorer_rpc_circuit_breaker_state",
+		Help: "RPC circuit breaker state per endpoint (0 closed, 1 half-open, 2 open)",
+	}, []string{"endpoint"})
+
+	// Circuit breaker state changes per endpoint
+	circuitBreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_rpc_circuit_breaker_transitions_total",
+		Help: "Total number of RPC circuit breaker transitions per endpoint by new state",
+	}, []string{"endpoint", "state"})
+
+	// Requests rejected by an open circuit breaker
+	circuitBreakerRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "explorer_rpc_circuit_breaker_rejections_total",
+		Help: "Total number of RPC requests rejected by an open circuit breaker",
+	}, []string{"endpoint"})
+)
@@ -0,0 +1,645 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// Provider scoring and health parameters
+const (
+	// latencyEWMAWeight is the weight of the newest sample in the latency moving average
+	latencyEWMAWeight = 0.3
+	// failurePenaltyMs is added to the score for each consecutive transient failure
+	failurePenaltyMs = 500.0
+	// headLagPenaltyMs is added to the score for each block a provider is behind the best known head
+	headLagPenaltyMs = 100.0
+	// failureThreshold is the number of consecutive transient failures before a provider cools down
+	failureThreshold = 3
+	// transientCooldown is the first cooldown after reaching failureThreshold (doubles per further failure)
+	transientCooldown = 10 * time.Second
+	// maxCooldown caps the transient failure cooldown
+	maxCooldown = 2 * time.Minute
+	// rateLimitCooldown is how long a rate-limited provider is skipped
+	rateLimitCooldown = 30 * time.Second
+	// healthCheckInterval is how often Run probes every provider with eth_blockNumber
+	healthCheckInterval = 15 * time.Second
+)
+
+// endpoint is a single RPC provider (implemented by *Client)
+type endpoint interface {
+	GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error)
+	GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error)
+	GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error)
+	GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	FinalityHeights(ctx context.Context) (uint64, uint64, error)
+	TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]TxCallTrace, error)
+	CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error)
+	Close()
+}
+
+// provider tracks the health of one endpoint
+type provider struct {
+	name     string
+	endpoint endpoint
+
+	mu                  sync.Mutex
+	latencyMs           float64 // Moving average of successful request latency
+	consecutiveFailures int
+	cooldownUntil       time.Time
+	headHeight          uint64
+}
+
+// Pool spreads RPC requests over several providers and fails over between them
+// Providers are ranked by latency, recent failures and head height; rate-limited or
+// repeatedly failing providers cool down and are only used when no healthy provider is left.
+// Pool implements index.RPCBlockFetcher, index.ContractCaller, store.ReceiptFetcher and store.TraceFetcher.
+type Pool struct {
+	providers  []*provider
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// NewPool creates a provider pool with one client per configured RPC URL
+// Each client makes a single attempt per request; the pool retries across providers with backoff
+func NewPool(config *Config) (*Pool, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	urls := config.RPCURLs
+	if len(urls) == 0 {
+		urls = []string{config.RPCURL}
+	}
+	names := providerNames(urls)
+
+	endpoints := make([]endpoint, 0, len(urls))
+	for i, rpcURL := range urls {
+		clientConfig := *config
+		clientConfig.RPCURL = rpcURL
+		clientConfig.RPCURLs = nil
+		clientConfig.MaxRetries = 0 // Retries are handled by the pool
+		clientConfig.Name = names[i]
+
+		client, err := NewClient(&clientConfig)
+		if err != nil {
+			for _, e := range endpoints {
+				e.Close()
+			}
+			return nil, fmt.Errorf("failed to create client for provider %s: %w", names[i], err)
+		}
+		endpoints = append(endpoints, client)
+	}
+
+	return newPool(names, endpoints, config), nil
+}
+
+// newPool creates a pool over existing endpoints
+func newPool(names []string, endpoints []endpoint, config *Config) *Pool {
+	providers := make([]*provider, len(endpoints))
+	for i, e := range endpoints {
+		providers[i] = &provider{name: names[i], endpoint: e}
+		providerHealthy.WithLabelValues(names[i]).Set(1)
+	}
+
+	util.Info("rpc provider pool created",
+		"providers", len(providers),
+	)
+
+	return &Pool{
+		providers:  providers,
+		maxRetries: config.MaxRetries,
+		baseDelay:  config.RetryBaseDelay,
+	}
+}
+
+// providerNames derives metric-safe provider names from URL hosts (never the full URL, which may contain API keys)
+func providerNames(urls []string) []string {
+	names := make([]string, len(urls))
+	seen := make(map[string]int)
+	for i, rpcURL := range urls {
+		name := fmt.Sprintf("provider-%d", i)
+		if parsed, err := url.Parse(rpcURL); err == nil && parsed.Host != "" {
+			name = parsed.Host
+		}
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// Close closes every provider connection
+func (p *Pool) Close() {
+	for _, prov := range p.providers {
+		prov.endpoint.Close()
+	}
+}
+
+// GetBlockByNumber fetches a block from the best available provider
+// Returns ethereum.NotFound if no provider has the block yet
+func (p *Pool) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	var block *types.Block
+	err := p.do(ctx, "eth_getBlockByNumber", func(prov *provider) error {
+		blk, err := prov.endpoint.GetBlockByNumber(ctx, height)
+		if err != nil {
+			return err
+		}
+		prov.observeHead(height)
+		block = blk
+		return nil
+	})
+	return block, err
+}
+
+// BlockNumber returns the latest block height reported by the best available provider
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	var height uint64
+	err := p.do(ctx, "eth_blockNumber", func(prov *provider) error {
+		head, err := prov.endpoint.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		prov.observeHead(head)
+		height = head
+		return nil
+	})
+	return height, err
+}
+
+// FinalityHeights returns the latest safe and finalized block heights from the best available provider
+func (p *Pool) FinalityHeights(ctx context.Context) (uint64, uint64, error) {
+	var safe, finalized uint64
+	err := p.do(ctx, "eth_getBlockByNumber", func(prov *provider) error {
+		s, f, err := prov.endpoint.FinalityHeights(ctx)
+		if err != nil {
+			return err
+		}
+		safe, finalized = s, f
+		return nil
+	})
+	return safe, finalized, err
+}
+
+// GetBlockReceipts fetches all receipts of a block from the best available provider
+func (p *Pool) GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error) {
+	var receipts []*types.Receipt
+	err := p.do(ctx, "eth_getBlockReceipts", func(prov *provider) error {
+		rcpts, err := prov.endpoint.GetBlockReceipts(ctx, height)
+		if err != nil {
+			return err
+		}
+		receipts = rcpts
+		return nil
+	})
+	return receipts, err
+}
+
+// GetTransactionReceipt fetches a transaction receipt from the best available provider
+func (p *Pool) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	err := p.do(ctx, "eth_getTransactionReceipt", func(prov *provider) error {
+		rcpt, err := prov.endpoint.GetTransactionReceipt(ctx, txHash)
+		if err != nil {
+			return err
+		}
+		receipt = rcpt
+		return nil
+	})
+	return receipt, err
+}
+
+// GetBlocksByNumber fetches several blocks with batch requests
+// Items that fail on one provider are retried on the next; see Client.GetBlocksByNumber
+func (p *Pool) GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	blocks := make([]*types.Block, len(heights))
+	pending := allIndexes(len(heights))
+
+	err := p.do(ctx, "eth_getBlockByNumber_batch", func(prov *provider) error {
+		request := make([]uint64, len(pending))
+		for j, i := range pending {
+			request[j] = heights[i]
+		}
+
+		result, err := prov.endpoint.GetBlocksByNumber(ctx, request)
+		for j, block := range result {
+			if block != nil {
+				blocks[pending[j]] = block
+				prov.observeHead(request[j])
+			}
+		}
+		pending = remainingIndexes(pending, err)
+		return err
+	})
+	if err != nil {
+		return blocks, newPoolBatchError("eth_getBlockByNumber", len(heights), pending, err)
+	}
+
+	return blocks, nil
+}
+
+// GetTransactionReceipts fetches several receipts with batch requests
+// Items that fail on one provider are retried on the next; see Client.GetTransactionReceipts
+func (p *Pool) GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(txHashes))
+	pending := allIndexes(len(txHashes))
+
+	err := p.do(ctx, "eth_getTransactionReceipt_batch", func(prov *provider) error {
+		request := make([]common.Hash, len(pending))
+		for j, i := range pending {
+			request[j] = txHashes[i]
+		}
+
+		result, err := prov.endpoint.GetTransactionReceipts(ctx, request)
+		for j, receipt := range result {
+			if receipt != nil {
+				receipts[pending[j]] = receipt
+			}
+		}
+		pending = remainingIndexes(pending, err)
+		return err
+	})
+	if err != nil {
+		return receipts, newPoolBatchError("eth_getTransactionReceipt", len(txHashes), pending, err)
+	}
+
+	return receipts, nil
+}
+
+// newPoolBatchError reports the items still pending after all providers were tried
+func newPoolBatchError(method string, total int, pending []int, err error) error {
+	if len(pending) == 0 {
+		return err
+	}
+	failed := make(map[int]error, len(pending))
+	for _, i := range pending {
+		failed[i] = err
+	}
+	return &BatchError{Method: method, Total: total, Failed: failed}
+}
+
+// allIndexes returns 0..n-1
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// remainingIndexes maps the failed items of a batch error back to the original request indexes
+// Any other error leaves all items pending
+func remainingIndexes(pending []int, err error) []int {
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		if err == nil {
+			return nil
+		}
+		return pending
+	}
+
+	remaining := make([]int, 0, len(batchErr.Failed))
+	for _, j := range batchErr.FailedIndexes() {
+		remaining = append(remaining, pending[j])
+	}
+	return remaining
+}
+
+// do runs a request against providers in rank order, retrying whole rounds with backoff
+// Not-found responses and permanent errors move on to the next provider without retrying the round.
+// Execution errors (a reverting eth_call) are the call's result and are returned at once.
+func (p *Pool) do(ctx context.Context, method string, call func(prov *provider) error) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		candidates := p.rank()
+		notFound, permanent, open := 0, 0, 0
+
+		for _, prov := range candidates {
+			start := time.Now()
+			err := call(prov)
+			if err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+			prov.record(method, time.Since(start), err)
+
+			if err == nil {
+				return nil
+			}
+			if isNotFound(err) {
+				notFound++
+				continue
+			}
+			if IsExecutionError(err) {
+				return err // Every provider would revert the same way
+			}
+
+			lastErr = err
+			switch errorClass(err) {
+			case ErrPermanent:
+				permanent++
+			case ErrCircuitOpen:
+				open++
+				continue // Not sent; nothing to report
+			}
+			util.Warn("rpc provider request failed, trying next provider",
+				"provider", prov.name,
+				"method", method,
+				"error", err.Error(),
+			)
+		}
+
+		if notFound == len(candidates) {
+			return ethereum.NotFound
+		}
+		if notFound+permanent == len(candidates) {
+			break // Retrying will not help
+		}
+		if open == len(candidates) {
+			break // Every provider is known to be down: fail fast
+		}
+
+		if attempt < p.maxRetries {
+			backoffDelay := calculateBackoff(attempt, p.baseDelay)
+			util.Info("all rpc providers failed, retrying after backoff",
+				"method", method,
+				"attempt", attempt+1,
+				"backoff_duration", backoffDelay.String(),
+			)
+			select {
+			case <-time.After(backoffDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return fmt.Errorf("%s failed on all %d providers: %w", method, len(p.providers), lastErr)
+}
+
+// rank orders providers for the next request: healthy providers by score, then cooling-down ones
+func (p *Pool) rank() []*provider {
+	now := time.Now()
+
+	var bestHead uint64
+	for _, prov := range p.providers {
+		if head := prov.head(); head > bestHead {
+			bestHead = head
+		}
+	}
+
+	type rankedProvider struct {
+		prov    *provider
+		score   float64
+		healthy bool
+	}
+	ranked := make([]rankedProvider, len(p.providers))
+	for i, prov := range p.providers {
+		score, healthy := prov.status(now, bestHead)
+		ranked[i] = rankedProvider{prov: prov, score: score, healthy: healthy}
+		providerScore.WithLabelValues(prov.name).Set(score)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].healthy != ranked[j].healthy {
+			return ranked[i].healthy
+		}
+		return ranked[i].score < ranked[j].score
+	})
+
+	providers := make([]*provider, len(ranked))
+	for i, r := range ranked {
+		providers[i] = r.prov
+	}
+	return providers
+}
+
+// Run probes every provider with eth_blockNumber until the context is cancelled
+// Probes keep head heights and latency current even for providers that receive no traffic
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.probe(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// probe checks all providers concurrently
+func (p *Pool) probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, prov := range p.providers {
+		wg.Add(1)
+		go func(prov *provider) {
+			defer wg.Done()
+
+			start := time.Now()
+			head, err := prov.endpoint.BlockNumber(ctx)
+			if err != nil && ctx.Err() != nil {
+				return
+			}
+			prov.record("eth_blockNumber", time.Since(start), err)
+			if err == nil {
+				prov.observeHead(head)
+			}
+		}(prov)
+	}
+	wg.Wait()
+}
+
+// Stats returns per-provider health statistics
+func (p *Pool) Stats() map[string]interface{} {
+	now := time.Now()
+
+	var bestHead uint64
+	for _, prov := range p.providers {
+		if head := prov.head(); head > bestHead {
+			bestHead = head
+		}
+	}
+
+	stats := make(map[string]interface{}, len(p.providers))
+	for _, prov := range p.providers {
+		score, healthy := prov.status(now, bestHead)
+		prov.mu.Lock()
+		stats[prov.name] = map[string]interface{}{
+			"healthy":              healthy,
+			"score":                score,
+			"latency_ms":           prov.latencyMs,
+			"head_height":          prov.headHeight,
+			"consecutive_failures": prov.consecutiveFailures,
+		}
+		prov.mu.Unlock()
+	}
+	return stats
+}
+
+// record updates metrics and health state after a request
+func (prov *provider) record(method string, duration time.Duration, err error) {
+	providerRequestDuration.WithLabelValues(prov.name, method).Observe(duration.Seconds())
+
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+
+	if err == nil || isNotFound(err) {
+		status := "success"
+		if err != nil {
+			status = "not_found"
+		}
+		providerRequests.WithLabelValues(prov.name, method, status).Inc()
+
+		// Only responses count towards latency (fast failures would look like a fast provider)
+		ms := float64(duration.Milliseconds())
+		if prov.latencyMs == 0 {
+			prov.latencyMs = ms
+		} else {
+			prov.latencyMs = latencyEWMAWeight*ms + (1-latencyEWMAWeight)*prov.latencyMs
+		}
+		prov.consecutiveFailures = 0
+		return
+	}
+
+	errType := errorClass(err)
+	providerRequests.WithLabelValues(prov.name, method, "error").Inc()
+	providerErrors.WithLabelValues(prov.name, errType.String()).Inc()
+
+	switch errType {
+	case ErrRateLimit:
+		prov.cooldownUntil = time.Now().Add(rateLimitCooldown)
+		util.Warn("rpc provider rate limited, cooling down",
+			"provider", prov.name,
+			"cooldown", rateLimitCooldown.String(),
+		)
+	case ErrTransient:
+		prov.consecutiveFailures++
+		if prov.consecutiveFailures >= failureThreshold {
+			cooldown := calculateBackoff(prov.consecutiveFailures-failureThreshold, transientCooldown)
+			if cooldown > maxCooldown {
+				cooldown = maxCooldown
+			}
+			prov.cooldownUntil = time.Now().Add(cooldown)
+			util.Warn("rpc provider failing, cooling down",
+				"provider", prov.name,
+				"consecutive_failures", prov.consecutiveFailures,
+				"cooldown", cooldown.String(),
+			)
+		}
+	case ErrCircuitOpen:
+		// The endpoint's breaker already counted the failures; rank it last until it recovers
+		prov.cooldownUntil = time.Now().Add(transientCooldown)
+	case ErrPermanent:
+		// Permanent errors are caused by the request, not the provider
+	}
+
+	providerHealthy.WithLabelValues(prov.name).Set(boolToFloat(!time.Now().Before(prov.cooldownUntil)))
+}
+
+// observeHead raises the provider's known head height
+func (prov *provider) observeHead(height uint64) {
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+
+	if height > prov.headHeight {
+		prov.headHeight = height
+		providerHeadHeight.WithLabelValues(prov.name).Set(float64(height))
+	}
+}
+
+// head returns the provider's known head height (0 if unknown)
+func (prov *provider) head() uint64 {
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+	return prov.headHeight
+}
+
+// status returns the provider's score (lower is better) and whether it is out of cooldown
+func (prov *provider) status(now time.Time, bestHead uint64) (float64, bool) {
+	prov.mu.Lock()
+	defer prov.mu.Unlock()
+
+	score := prov.latencyMs + float64(prov.consecutiveFailures)*failurePenaltyMs
+	if prov.headHeight > 0 && bestHead > prov.headHeight {
+		score += float64(bestHead-prov.headHeight) * headLagPenaltyMs
+	}
+
+	healthy := !now.Before(prov.cooldownUntil)
+	providerHealthy.WithLabelValues(prov.name).Set(boolToFloat(healthy))
+	return score, healthy
+}
+
+// isNotFound reports whether the request failed only because the data does not exist (yet)
+// For batch errors, every failed item must be not found
+func isNotFound(err error) bool {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		for _, itemErr := range batchErr.Failed {
+			if !errors.Is(itemErr, ethereum.NotFound) {
+				return false
+			}
+		}
+		return true
+	}
+	return errors.Is(err, ethereum.NotFound)
+}
+
+// errorClass returns the error type, preferring the classification already made by the client
+func errorClass(err error) ErrorType {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErrorClass(batchErr)
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Type
+	}
+	return classifyError(err)
+}
+
+// batchErrorClass returns the most actionable error type among the failed items of a batch
+// Rate limits take precedence over transient errors, then open circuits, then permanent errors
+func batchErrorClass(batchErr *BatchError) ErrorType {
+	class := ErrPermanent
+	for _, err := range batchErr.Failed {
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		switch errorClass(err) {
+		case ErrRateLimit:
+			return ErrRateLimit
+		case ErrTransient:
+			class = ErrTransient
+		case ErrCircuitOpen:
+			if class == ErrPermanent {
+				class = ErrCircuitOpen
+			}
+		}
+	}
+	return class
+}
+
+// boolToFloat converts a bool to a gauge value
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,392 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEndpoint implements endpoint for pool tests
+type fakeEndpoint struct {
+	mu    sync.Mutex
+	err   error
+	head  uint64
+	delay time.Duration
+	calls int
+}
+
+func (f *fakeEndpoint) call() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return f.err
+}
+
+func (f *fakeEndpoint) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeEndpoint) GetBlockByNumber(ctx context.Context, height uint64) (*types.Block, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	if f.head > 0 && height > f.head {
+		return nil, ethereum.NotFound
+	}
+	return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(height)}), nil
+}
+
+func (f *fakeEndpoint) GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return []*types.Receipt{{Status: types.ReceiptStatusSuccessful}}, nil
+}
+
+func (f *fakeEndpoint) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return &types.Receipt{TxHash: txHash}, nil
+}
+
+func (f *fakeEndpoint) GetBlocksByNumber(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	blocks := make([]*types.Block, len(heights))
+	failed := make(map[int]error)
+	for i, height := range heights {
+		if f.head > 0 && height > f.head {
+			failed[i] = ethereum.NotFound
+			continue
+		}
+		blocks[i] = types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(height)})
+	}
+	if len(failed) > 0 {
+		return blocks, &BatchError{Method: "eth_getBlockByNumber", Total: len(heights), Failed: failed}
+	}
+	return blocks, nil
+}
+
+func (f *fakeEndpoint) GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	receipts := make([]*types.Receipt, len(txHashes))
+	for i, txHash := range txHashes {
+		receipts[i] = &types.Receipt{TxHash: txHash}
+	}
+	return receipts, nil
+}
+
+func (f *fakeEndpoint) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := f.call(); err != nil {
+		return 0, err
+	}
+	return f.head, nil
+}
+
+func (f *fakeEndpoint) FinalityHeights(ctx context.Context) (uint64, uint64, error) {
+	if err := f.call(); err != nil {
+		return 0, 0, err
+	}
+	// One epoch behind the head is safe, two are finalized
+	return f.head - min(f.head, 32), f.head - min(f.head, 64), nil
+}
+
+func (f *fakeEndpoint) TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]TxCallTrace, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return []TxCallTrace{{Result: &CallFrame{Type: "CALL"}}}, nil
+}
+
+func (f *fakeEndpoint) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return to.Bytes(), nil
+}
+
+func (f *fakeEndpoint) Close() {}
+
+func newTestPool(endpoints ...*fakeEndpoint) *Pool {
+	names := make([]string, len(endpoints))
+	eps := make([]endpoint, len(endpoints))
+	for i, e := range endpoints {
+		names[i] = "test" + string(rune('a'+i))
+		eps[i] = e
+	}
+	config := NewConfigWithDefaults("http://test")
+	config.MaxRetries = 1
+	config.RetryBaseDelay = time.Millisecond
+	return newPool(names, eps, config)
+}
+
+func TestNewConfig_RPCURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		rpcURL   string
+		rpcURLs  string
+		wantURL  string
+		wantURLs []string
+		wantErr  bool
+	}{
+		{name: "single url", rpcURL: "https://a.example", wantURL: "https://a.example", wantURLs: []string{"https://a.example"}},
+		{name: "primary plus list", rpcURL: "https://a.example", rpcURLs: "https://b.example, https://c.example", wantURL: "https://a.example", wantURLs: []string{"https://a.example", "https://b.example", "https://c.example"}},
+		{name: "list only", rpcURLs: "https://b.example,https://c.example", wantURL: "https://b.example", wantURLs: []string{"https://b.example", "https://c.example"}},
+		{name: "duplicates and blanks", rpcURL: "https://a.example", rpcURLs: "https://a.example,,https://b.example", wantURL: "https://a.example", wantURLs: []string{"https://a.example", "https://b.example"}},
+		{name: "none", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RPC_URL", tt.rpcURL)
+			t.Setenv("RPC_URLS", tt.rpcURLs)
+			t.Setenv("RPC_WS_URL", "")
+
+			cfg, err := NewConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURL, cfg.RPCURL)
+			assert.Equal(t, tt.wantURLs, cfg.RPCURLs)
+		})
+	}
+}
+
+func TestProviderNames(t *testing.T) {
+	names := providerNames([]string{
+		"https://eth-mainnet.g.alchemy.com/v2/secret-key",
+		"https://eth-mainnet.g.alchemy.com/v2/other-key",
+		"http://localhost:8545",
+		"::invalid",
+	})
+
+	assert.Equal(t, []string{
+		"eth-mainnet.g.alchemy.com",
+		"eth-mainnet.g.alchemy.com#2",
+		"localhost:8545",
+		"provider-3",
+	}, names)
+}
+
+func TestPool_FailsOverToHealthyProvider(t *testing.T) {
+	failing := &fakeEndpoint{err: errors.New("connection refused")}
+	healthy := &fakeEndpoint{}
+	pool := newTestPool(failing, healthy)
+
+	block, err := pool.GetBlockByNumber(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), block.NumberU64())
+
+	// The failing provider is now ranked behind the healthy one
+	assert.Equal(t, healthy, pool.rank()[0].endpoint)
+	assert.Equal(t, 1, failing.callCount())
+	assert.Equal(t, 1, healthy.callCount())
+}
+
+func TestPool_BlockNumber(t *testing.T) {
+	failing := &fakeEndpoint{err: errors.New("connection refused")}
+	healthy := &fakeEndpoint{head: 250}
+	pool := newTestPool(failing, healthy)
+
+	head, err := pool.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(250), head)
+	assert.Equal(t, uint64(250), pool.Stats()["testb"].(map[string]interface{})["head_height"])
+}
+
+func TestPool_FinalityHeights(t *testing.T) {
+	failing := &fakeEndpoint{err: errors.New("connection refused")}
+	healthy := &fakeEndpoint{head: 250}
+	pool := newTestPool(failing, healthy)
+
+	safe, finalized, err := pool.FinalityHeights(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(218), safe)
+	assert.Equal(t, uint64(186), finalized)
+}
+
+func TestPool_RateLimitedProviderCoolsDown(t *testing.T) {
+	limited := &fakeEndpoint{err: errors.New("429 Too Many Requests")}
+	backup := &fakeEndpoint{delay: 5 * time.Millisecond} // Slower, so only used on failover
+	pool := newTestPool(limited, backup)
+
+	_, err := pool.GetBlockReceipts(context.Background(), 100)
+	require.NoError(t, err)
+
+	limited.mu.Lock()
+	limited.err = nil
+	limited.mu.Unlock()
+
+	// Still cooling down: requests go to the backup even though the limited provider is faster
+	for i := 0; i < 3; i++ {
+		_, err := pool.GetTransactionReceipt(context.Background(), common.HexToHash("0x01"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, limited.callCount())
+	assert.Equal(t, 4, backup.callCount())
+
+	stats := pool.Stats()
+	assert.Equal(t, false, stats["testa"].(map[string]interface{})["healthy"])
+}
+
+func TestPool_TransientFailuresTriggerCooldown(t *testing.T) {
+	failing := &fakeEndpoint{err: errors.New("connection reset by peer")}
+	pool := newTestPool(failing)
+
+	for i := 0; i < failureThreshold; i++ {
+		_, err := pool.GetBlockByNumber(context.Background(), 1)
+		require.Error(t, err)
+	}
+
+	_, healthy := pool.providers[0].status(time.Now(), 0)
+	assert.False(t, healthy)
+
+	// A cooling-down provider is still used as a last resort
+	failing.mu.Lock()
+	failing.err = nil
+	failing.mu.Unlock()
+	_, err := pool.GetBlockByNumber(context.Background(), 1)
+	assert.NoError(t, err)
+}
+
+func TestPool_RetriesRoundsThenFails(t *testing.T) {
+	a := &fakeEndpoint{err: errors.New("connection refused")}
+	b := &fakeEndpoint{err: errors.New("connection refused")}
+	pool := newTestPool(a, b)
+
+	_, err := pool.GetBlockByNumber(context.Background(), 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "eth_getBlockByNumber failed on all 2 providers")
+
+	// MaxRetries = 1: two rounds over both providers
+	assert.Equal(t, 2, a.callCount())
+	assert.Equal(t, 2, b.callCount())
+}
+
+func TestPool_PermanentErrorDoesNotRetry(t *testing.T) {
+	a := &fakeEndpoint{err: errors.New("invalid argument 0")}
+	pool := newTestPool(a)
+
+	_, err := pool.GetBlockByNumber(context.Background(), 1)
+	require.Error(t, err)
+	assert.Equal(t, 1, a.callCount())
+
+	_, healthy := pool.providers[0].status(time.Now(), 0)
+	assert.True(t, healthy, "permanent errors do not affect provider health")
+}
+
+func TestPool_CallContractRevertDoesNotRetry(t *testing.T) {
+	a := &fakeEndpoint{err: errors.New("execution reverted")}
+	b := &fakeEndpoint{err: errors.New("execution reverted")}
+	pool := newTestPool(a, b)
+
+	_, err := pool.CallContract(context.Background(), common.HexToAddress("0x01"), []byte{0x06, 0xfd, 0xde, 0x03})
+	require.Error(t, err)
+	assert.True(t, IsExecutionError(err))
+
+	// A revert is the call's result: no failover to the other provider, and no effect on provider health
+	assert.Equal(t, 1, a.callCount()+b.callCount())
+	_, healthy := pool.providers[0].status(time.Now(), 0)
+	assert.True(t, healthy)
+}
+
+func TestPool_NotFoundOnAllProviders(t *testing.T) {
+	a := &fakeEndpoint{head: 99}
+	b := &fakeEndpoint{head: 99}
+	pool := newTestPool(a, b)
+
+	_, err := pool.GetBlockByNumber(context.Background(), 100)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+	assert.Equal(t, "not found", err.Error())
+}
+
+func TestPool_NotFoundFallsThroughToProviderAhead(t *testing.T) {
+	behind := &fakeEndpoint{head: 99}
+	ahead := &fakeEndpoint{head: 100, delay: 5 * time.Millisecond}
+	pool := newTestPool(behind, ahead)
+
+	block, err := pool.GetBlockByNumber(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), block.NumberU64())
+}
+
+func TestPool_RankPenalizesHeadLag(t *testing.T) {
+	behind := &fakeEndpoint{head: 90}
+	current := &fakeEndpoint{head: 100}
+	pool := newTestPool(behind, current)
+
+	pool.probe(context.Background())
+
+	assert.Equal(t, current, pool.rank()[0].endpoint)
+	assert.Equal(t, uint64(90), pool.providers[0].head())
+	assert.Equal(t, uint64(100), pool.providers[1].head())
+}
+
+func TestPool_ContextCancelled(t *testing.T) {
+	a := &fakeEndpoint{err: errors.New("connection refused")}
+	pool := newTestPool(a)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pool.GetBlockByNumber(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, pool.providers[0].consecutiveFailures, "cancellation does not count against the provider")
+}
+
+func TestPool_GetBlocksByNumber_FailsOverOnlyMissingItems(t *testing.T) {
+	behind := &fakeEndpoint{head: 101}
+	ahead := &fakeEndpoint{head: 103, delay: 5 * time.Millisecond}
+	pool := newTestPool(behind, ahead)
+
+	blocks, err := pool.GetBlocksByNumber(context.Background(), []uint64{100, 101, 102, 103})
+	require.NoError(t, err)
+	for i, block := range blocks {
+		assert.Equal(t, uint64(100+i), block.NumberU64())
+	}
+	assert.Equal(t, 1, behind.callCount())
+	assert.Equal(t, 1, ahead.callCount())
+}
+
+func TestPool_GetBlocksByNumber_NotFound(t *testing.T) {
+	pool := newTestPool(&fakeEndpoint{head: 100})
+
+	blocks, err := pool.GetBlocksByNumber(context.Background(), []uint64{100, 101})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ethereum.NotFound)
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{1}, batchErr.FailedIndexes())
+	assert.Equal(t, uint64(100), blocks[0].NumberU64())
+}
+
+func TestPool_GetTransactionReceipts(t *testing.T) {
+	failing := &fakeEndpoint{err: errors.New("connection refused")}
+	pool := newTestPool(failing, &fakeEndpoint{})
+
+	hashes := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")}
+	receipts, err := pool.GetTransactionReceipts(context.Background(), hashes)
+	require.NoError(t, err)
+	assert.Equal(t, hashes[1], receipts[1].TxHash)
+}
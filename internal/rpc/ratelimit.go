@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// rateLimiter is a token-bucket limiter with a concurrency budget, shared by every caller of a Client
+// Tokens are request cost units (one per call unless a per-method cost is configured), refilled at
+// RateLimit units per second up to RateBurst. A request larger than the bucket is admitted once the
+// bucket is full and leaves it in debt, so oversized batches are delayed rather than rejected.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // units per second (0 = unlimited)
+	burst  float64
+	tokens float64
+	last   time.Time
+	costs  map[string]float64
+	now    func() time.Time
+
+	slots chan struct{} // in-flight request budget (nil = unlimited)
+}
+
+// newRateLimiter creates a limiter from the config, or returns nil when no limit is configured
+func newRateLimiter(config *Config) *rateLimiter {
+	if config.RateLimit <= 0 && config.MaxConcurrency <= 0 {
+		return nil
+	}
+
+	burst := float64(config.RateBurst)
+	if burst <= 0 {
+		burst = config.RateLimit
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	costs := make(map[string]float64, len(config.MethodCosts))
+	for method, cost := range config.MethodCosts {
+		costs[method] = cost
+	}
+
+	l := &rateLimiter{
+		rate:   config.RateLimit,
+		burst:  burst,
+		tokens: burst,
+		costs:  costs,
+		now:    time.Now,
+	}
+	l.last = l.now()
+
+	if config.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+
+	return l
+}
+
+// cost returns the cost of calls requests of the given method
+func (l *rateLimiter) cost(method string, calls int) float64 {
+	unit, ok := l.costs[method]
+	if !ok {
+		unit = 1
+	}
+	return unit * float64(calls)
+}
+
+// acquire waits until calls requests of the method fit the rate and concurrency budgets
+// The returned release function must be called when the request completes
+func (l *rateLimiter) acquire(ctx context.Context, method string, calls int) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	startTime := time.Now()
+
+	if err := l.waitTokens(ctx, l.cost(method, calls)); err != nil {
+		return nil, err
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if waited := time.Since(startTime); waited > time.Millisecond {
+		rateLimiterWait.WithLabelValues(method).Observe(waited.Seconds())
+		util.Debug("rpc request delayed by client rate limiter",
+			"method", method,
+			"calls", calls,
+			"wait_ms", waited.Milliseconds(),
+		)
+	}
+
+	return func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}, nil
+}
+
+// waitTokens reserves cost units from the bucket, sleeping until the reservation is covered
+func (l *rateLimiter) waitTokens(ctx context.Context, cost float64) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(cost)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.refund(cost)
+		return ctx.Err()
+	}
+}
+
+// reserve takes cost units from the bucket and returns how long the caller must wait for them
+func (l *rateLimiter) reserve(cost float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+
+	// Oversized requests may only start from a full bucket
+	if cost > l.burst {
+		wait := (l.burst - l.tokens) / l.rate
+		l.tokens -= cost
+		return time.Duration(wait * float64(time.Second))
+	}
+
+	l.tokens -= cost
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refund returns units of a reservation that was abandoned
+func (l *rateLimiter) refund(cost float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens += cost
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// refill adds the units accrued since the last update (caller holds mu)
+func (l *rateLimiter) refill() {
+	now := l.now()
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	if elapsed <= 0 {
+		return
+	}
+
+	l.tokens += elapsed * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
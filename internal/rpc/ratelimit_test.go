@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter creates a limiter driven by a fake clock
+func newTestLimiter(config *Config) (*rateLimiter, *time.Time) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newRateLimiter(config)
+	l.now = func() time.Time { return now }
+	l.last = now
+	return l, &now
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, newRateLimiter(NewConfigWithDefaults("http://localhost:8545")))
+
+	// A nil limiter admits everything
+	var l *rateLimiter
+	release, err := l.acquire(context.Background(), "eth_getBlockByNumber", 100)
+	require.NoError(t, err)
+	release()
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.RateLimit = 10
+	config.RateBurst = 5
+	l, now := newTestLimiter(config)
+
+	// Burst is available immediately
+	for i := 0; i < 5; i++ {
+		assert.Zero(t, l.reserve(1))
+	}
+
+	// Next unit needs 1/10s of refill
+	assert.Equal(t, 100*time.Millisecond, l.reserve(1))
+
+	// Refill is capped at burst
+	*now = now.Add(10 * time.Second)
+	for i := 0; i < 5; i++ {
+		assert.Zero(t, l.reserve(1))
+	}
+	assert.Greater(t, l.reserve(1), time.Duration(0))
+}
+
+func TestRateLimiter_MethodCosts(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.RateLimit = 100
+	config.MethodCosts = map[string]float64{"eth_getBlockReceipts": 20}
+	l := newRateLimiter(config)
+
+	assert.Equal(t, 1.0, l.cost("eth_getBlockByNumber", 1))
+	assert.Equal(t, 50.0, l.cost("eth_getBlockByNumber", 50))
+	assert.Equal(t, 60.0, l.cost("eth_getBlockReceipts", 3))
+}
+
+func TestRateLimiter_OversizedRequest(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.RateLimit = 10
+	config.RateBurst = 10
+	l, now := newTestLimiter(config)
+
+	// Larger than the bucket: admitted from a full bucket, leaving it in debt
+	assert.Zero(t, l.reserve(30))
+	assert.Equal(t, 2*time.Second+100*time.Millisecond, l.reserve(1))
+
+	*now = now.Add(5 * time.Second)
+	assert.Zero(t, l.reserve(1))
+}
+
+func TestRateLimiter_AcquireWaits(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.RateLimit = 20
+	config.RateBurst = 1
+	l := newRateLimiter(config)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(context.Background(), "eth_getBlockByNumber", 1)
+		require.NoError(t, err)
+		release()
+	}
+
+	// 1 unit of burst, then two refills at 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_AcquireCancelled(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.RateLimit = 1
+	config.RateBurst = 1
+	l, _ := newTestLimiter(config)
+
+	require.Zero(t, l.reserve(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := l.acquire(ctx, "eth_getBlockByNumber", 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The abandoned reservation is refunded
+	assert.Equal(t, time.Second, l.reserve(1))
+}
+
+func TestRateLimiter_MaxConcurrency(t *testing.T) {
+	config := NewConfigWithDefaults("http://localhost:8545")
+	config.MaxConcurrency = 2
+	l := newRateLimiter(config)
+	require.NotNil(t, l)
+
+	release1, err := l.acquire(context.Background(), "eth_getBlockByNumber", 1)
+	require.NoError(t, err)
+	release2, err := l.acquire(context.Background(), "eth_getBlockByNumber", 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx, "eth_getBlockByNumber", 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "third request should wait for a free slot")
+
+	release1()
+	release3, err := l.acquire(context.Background(), "eth_getBlockByNumber", 1)
+	require.NoError(t, err)
+
+	release2()
+	release3()
+}
+
+func TestNewConfig_RateLimit(t *testing.T) {
+	envKeys := []string{"RPC_URL", "RPC_RATE_LIMIT", "RPC_RATE_BURST", "RPC_METHOD_COSTS", "RPC_MAX_CONCURRENCY"}
+	saved := make(map[string]string)
+	for _, key := range envKeys {
+		saved[key] = os.Getenv(key)
+	}
+	defer func() {
+		for key, val := range saved {
+			os.Setenv(key, val)
+		}
+	}()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantErr     bool
+		wantLimit   float64
+		wantBurst   int
+		wantCosts   map[string]float64
+		wantMaxConc int
+	}{
+		{
+			name:      "defaults disable limiting",
+			env:       map[string]string{},
+			wantCosts: map[string]float64{},
+		},
+		{
+			name: "all settings",
+			env: map[string]string{
+				"RPC_RATE_LIMIT":      "25.5",
+				"RPC_RATE_BURST":      "50",
+				"RPC_METHOD_COSTS":    "eth_getBlockReceipts=20, eth_getBlockByNumber=2",
+				"RPC_MAX_CONCURRENCY": "8",
+			},
+			wantLimit:   25.5,
+			wantBurst:   50,
+			wantCosts:   map[string]float64{"eth_getBlockReceipts": 20, "eth_getBlockByNumber": 2},
+			wantMaxConc: 8,
+		},
+		{name: "negative rate", env: map[string]string{"RPC_RATE_LIMIT": "-1"}, wantErr: true},
+		{name: "invalid burst", env: map[string]string{"RPC_RATE_BURST": "lots"}, wantErr: true},
+		{name: "invalid concurrency", env: map[string]string{"RPC_MAX_CONCURRENCY": "-2"}, wantErr: true},
+		{name: "malformed costs", env: map[string]string{"RPC_METHOD_COSTS": "eth_call"}, wantErr: true},
+		{name: "invalid cost", env: map[string]string{"RPC_METHOD_COSTS": "eth_call=x"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range envKeys {
+				os.Unsetenv(key)
+			}
+			os.Setenv("RPC_URL", "http://localhost:8545")
+			for key, val := range tt.env {
+				os.Setenv(key, val)
+			}
+
+			config, err := NewConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, config.RateLimit)
+			assert.Equal(t, tt.wantBurst, config.RateBurst)
+			assert.Equal(t, tt.wantCosts, config.MethodCosts)
+			assert.Equal(t, tt.wantMaxConc, config.MaxConcurrency)
+		})
+	}
+}
+
+func TestClient_RateLimitedBatch(t *testing.T) {
+	blocks, _ := newTestChain(t, 4)
+	client := newFakeBatchClient(t, newFakeBatchService(blocks, nil))
+
+	// Each block costs 2 units at 40 units/s with room for one chunk of 2 blocks:
+	// the second chunk waits ~100ms for refill
+	client.limiter = newRateLimiter(&Config{
+		RateLimit:   40,
+		RateBurst:   4,
+		MethodCosts: map[string]float64{"eth_getBlockByNumber": 2},
+	})
+
+	start := time.Now()
+	result, err := client.GetBlocksByNumber(context.Background(), []uint64{1, 2, 3, 4})
+	require.NoError(t, err)
+	assert.Len(t, result, 4)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestClient_LimiterWaitExcludedFromRequestTimeout(t *testing.T) {
+	blocks, receipts := newTestChain(t, 2)
+	client := newFakeBatchClient(t, newFakeBatchService(blocks, receipts))
+
+	// The second call waits ~100ms for refill, longer than the request timeout,
+	// and has no retries to fall back on
+	client.config.RequestTimeout = 50 * time.Millisecond
+	client.config.MaxRetries = 0
+	client.limiter = newRateLimiter(&Config{RateLimit: 10, RateBurst: 1})
+
+	for height := uint64(1); height <= 2; height++ {
+		txHash := blocks[height].Transactions()[0].Hash()
+		receipt, err := client.GetTransactionReceipt(context.Background(), txHash)
+		require.NoError(t, err)
+		assert.Equal(t, txHash, receipt.TxHash)
+	}
+}
@@ -53,6 +53,15 @@ func retryWithBackoff(
 
 		lastErr = err
 
+		// Open circuit: the endpoint is known to be down, fail fast instead of backing off
+		if IsCircuitOpen(err) {
+			logger.Warn("circuit breaker open, not retrying",
+				"operation", operationName,
+				"attempt", attempt+1,
+			)
+			return err
+		}
+
 		// Classify the error
 		errorType := classifyError(err)
 
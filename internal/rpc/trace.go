@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hieutt50/go-blockchain-explorer/internal/util"
+)
+
+// callTracerConfig selects go-ethereum's built-in call tracer for debug_trace* calls
+var callTracerConfig = map[string]interface{}{"tracer": "callTracer"}
+
+// CallFrame is one call of a callTracer result; Calls holds the calls it made, in execution order
+type CallFrame struct {
+	Type    string          `json:"type"` // CALL, STATICCALL, DELEGATECALL, CALLCODE, CREATE, CREATE2, SELFDESTRUCT
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"` // nil for a failed contract creation
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []CallFrame     `json:"calls,omitempty"`
+}
+
+// TxCallTrace is the call tree of one transaction of a debug_traceBlockByHash response
+// TxHash is zero on nodes that do not report it; traces are then in transaction order
+type TxCallTrace struct {
+	TxHash common.Hash `json:"txHash"`
+	Result *CallFrame  `json:"result"`
+	Error  string      `json:"error,omitempty"` // Set when tracing this transaction failed
+}
+
+// TraceBlockCalls traces every transaction of a block with debug_traceBlockByHash and the callTracer
+// The block is selected by hash so the traces belong to the same block as the fetched transactions,
+// even if the chain reorganizes in between.
+// Requires the debug API on the node; "method does not exist" errors are permanent and not retried
+func (c *Client) TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]TxCallTrace, error) {
+	startTime := time.Now()
+
+	util.Debug("tracing block calls",
+		"method", "debug_traceBlockByHash",
+		"block_hash", blockHash.Hex(),
+	)
+
+	var traces []TxCallTrace
+	var lastError error
+
+	// Create operation closure for retry logic
+	operation := func() error {
+		done, err := c.begin(ctx, "debug_traceBlockByHash", 1)
+		if err != nil {
+			return err
+		}
+
+		// Create context with request timeout
+		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+		defer cancel()
+
+		var result []TxCallTrace
+		err = c.ethClient.Client().CallContext(reqCtx, &result, "debug_traceBlockByHash",
+			blockHash, callTracerConfig)
+		done(err)
+		if err != nil {
+			lastError = err
+			return err
+		}
+
+		traces = result
+		return nil
+	}
+
+	// Execute with retry logic
+	retryCfg := &retryConfig{
+		maxRetries: c.config.MaxRetries,
+		baseDelay:  c.config.RetryBaseDelay,
+	}
+
+	err := retryWithBackoff(
+		ctx,
+		retryCfg,
+		operation,
+		util.GlobalLogger,
+		fmt.Sprintf("TraceBlockCalls(hash=%s)", blockHash.Hex()),
+	)
+
+	duration := time.Since(startTime)
+
+	if err != nil {
+		// Record RPC error metrics
+		if lastError != nil {
+			errorType := classifyError(lastError)
+			metricsErrorType := errorTypeToMetricsLabel(errorType)
+			util.RecordRPCError(metricsErrorType)
+		}
+
+		util.Error("failed to trace block calls",
+			"method", "debug_traceBlockByHash",
+			"block_hash", blockHash.Hex(),
+			"error", err.Error(),
+			"duration_ms", duration.Milliseconds(),
+		)
+		return nil, err
+	}
+
+	util.Debug("successfully traced block calls",
+		"method", "debug_traceBlockByHash",
+		"block_hash", blockHash.Hex(),
+		"trace_count", len(traces),
+		"duration_ms", duration.Milliseconds(),
+	)
+
+	return traces, nil
+}
+
+// TraceBlockCalls traces every transaction of a block on the best available provider
+// Only providers with the debug API can serve it; see Client.TraceBlockCalls
+func (p *Pool) TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]TxCallTrace, error) {
+	var traces []TxCallTrace
+	err := p.do(ctx, "debug_traceBlockByHash", func(prov *provider) error {
+		result, err := prov.endpoint.TraceBlockCalls(ctx, blockHash)
+		if err != nil {
+			return err
+		}
+		traces = result
+		return nil
+	})
+	return traces, err
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDebugService serves debug_traceBlockByHash for trace tests
+type fakeDebugService struct {
+	traces map[common.Hash][]TxCallTrace
+	tracer string
+	calls  int
+}
+
+func (s *fakeDebugService) TraceBlockByHash(hash common.Hash, config map[string]interface{}) ([]TxCallTrace, error) {
+	s.calls++
+	s.tracer, _ = config["tracer"].(string)
+	traces, ok := s.traces[hash]
+	if !ok {
+		return nil, errors.New("block " + hash.Hex() + " not found")
+	}
+	return traces, nil
+}
+
+func newFakeTraceClient(t *testing.T, namespace string, service interface{}) *Client {
+	server := gethrpc.NewServer()
+	require.NoError(t, server.RegisterName(namespace, service))
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	cfg := NewConfigWithDefaults(httpServer.URL)
+	cfg.RetryBaseDelay = time.Millisecond
+	cfg.MaxRetries = 2
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient_TraceBlockCalls(t *testing.T) {
+	to := common.HexToAddress("0x02")
+	txHash := common.HexToHash("0xaa")
+	blockHash := common.HexToHash("0xb7")
+	service := &fakeDebugService{traces: map[common.Hash][]TxCallTrace{
+		blockHash: {{
+			TxHash: txHash,
+			Result: &CallFrame{
+				Type:  "CALL",
+				From:  common.HexToAddress("0x01"),
+				To:    &to,
+				Value: (*hexutil.Big)(big.NewInt(5)),
+				Gas:   100000,
+				Calls: []CallFrame{{Type: "DELEGATECALL", From: to, To: &to, Error: "execution reverted"}},
+			},
+		}},
+	}}
+	client := newFakeTraceClient(t, "debug", service)
+
+	traces, err := client.TraceBlockCalls(context.Background(), blockHash)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+
+	assert.Equal(t, "callTracer", service.tracer)
+	assert.Equal(t, txHash, traces[0].TxHash)
+	assert.Equal(t, big.NewInt(5), traces[0].Result.Value.ToInt())
+	require.Len(t, traces[0].Result.Calls, 1)
+	assert.Equal(t, "execution reverted", traces[0].Result.Calls[0].Error)
+}
+
+func TestClient_TraceBlockCalls_NoDebugAPI(t *testing.T) {
+	// A node without the debug namespace: the method does not exist and is not retried
+	client := newFakeTraceClient(t, "eth", &fakeBatchService{})
+
+	_, err := client.TraceBlockCalls(context.Background(), common.HexToHash("0xb7"))
+	require.Error(t, err)
+	assert.Equal(t, ErrPermanent, classifyError(err))
+}
+
+func TestPool_TraceBlockCalls_FailsOver(t *testing.T) {
+	noDebug := &fakeEndpoint{err: errors.New("the method debug_traceBlockByHash does not exist/is not available")}
+	archive := &fakeEndpoint{}
+	pool := newTestPool(noDebug, archive)
+
+	traces, err := pool.TraceBlockCalls(context.Background(), common.HexToHash("0xb7"))
+	require.NoError(t, err)
+	assert.Len(t, traces, 1)
+	assert.Equal(t, 1, archive.callCount())
+}
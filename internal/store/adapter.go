@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"log/slog"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/hieutt50/go-blockchain-explorer/internal/db"
 	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/jackc/pgx/v5"
 )
 
 // IndexerAdapter adapts the Store to implement BlockStore and BlockStoreExtended interfaces
@@ -25,7 +27,7 @@ func NewIndexerAdapter(pool *db.Pool) *IndexerAdapter {
 	return &IndexerAdapter{pool: pool}
 }
 
-// GetLatestBlock returns the latest non-orphaned block from the database
+// GetLatestBlock returns the latest canonical block from the database
 func (a *IndexerAdapter) GetLatestBlock(ctx context.Context) (*index.Block, error) {
 	var b index.Block
 	var hashBytes, parentHashBytes, minerBytes []byte
@@ -33,7 +35,7 @@ func (a *IndexerAdapter) GetLatestBlock(ctx context.Context) (*index.Block, erro
 	err := a.pool.Pool.QueryRow(ctx, `
 		SELECT height, hash, parent_hash, miner, gas_used, timestamp, tx_count
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 		ORDER BY height DESC
 		LIMIT 1
 	`).Scan(&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
@@ -51,17 +53,17 @@ func (a *IndexerAdapter) GetLatestBlock(ctx context.Context) (*index.Block, erro
 	return &b, nil
 }
 
-// GetBlockByHeight returns a block by height
+// GetBlockByHeight returns the canonical block at the given height
 func (a *IndexerAdapter) GetBlockByHeight(ctx context.Context, height uint64) (*index.Block, error) {
 	var b index.Block
 	var hashBytes, parentHashBytes, minerBytes []byte
 
 	err := a.pool.Pool.QueryRow(ctx, `
-		SELECT height, hash, parent_hash, miner, gas_used, timestamp, tx_count, orphaned
+		SELECT height, hash, parent_hash, miner, gas_used, timestamp, tx_count
 		FROM blocks
-		WHERE height = $1
+		WHERE height = $1 AND canonical = TRUE
 	`, height).Scan(&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
-		&b.GasUsed, &b.Timestamp, &b.TxCount, new(bool)) // orphaned placeholder
+		&b.GasUsed, &b.Timestamp, &b.TxCount)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block by height %d: %w", height, err)
@@ -74,8 +76,13 @@ func (a *IndexerAdapter) GetBlockByHeight(ctx context.Context, height uint64) (*
 	return &b, nil
 }
 
-// InsertBlock inserts a single block with its transactions into the database
-// Transactions are inserted in the same database transaction for consistency
+// InsertBlock inserts a single block with its transactions, logs, token and NFT transfers, internal transactions
+// and withdrawals into the database, queueing new token contracts for metadata resolution
+// The block becomes the canonical block at its height; a different block previously stored at
+// that height is kept as a non-canonical (orphaned) block together with its transactions and logs,
+// and token balances and NFT ownership are adjusted for the transfers of both blocks. If the block itself
+// was stored before, its stored contents are replaced by the fetched ones.
+// Transactions and logs are inserted in the same database transaction for consistency
 func (a *IndexerAdapter) InsertBlock(ctx context.Context, block *index.Block) error {
 	tx, err := a.pool.Pool.Begin(ctx)
 	if err != nil {
@@ -83,45 +90,152 @@ func (a *IndexerAdapter) InsertBlock(ctx context.Context, block *index.Block) er
 	}
 	defer tx.Rollback(ctx)
 
+	// Demote any other canonical block at this height (kept for history) and reverse its transfers
+	demoted, err := queryBlockHashes(ctx, tx, `
+		UPDATE blocks SET canonical = FALSE, finality = 'latest', updated_at = NOW()
+		WHERE height = $1 AND hash <> $2 AND canonical = TRUE
+		RETURNING hash
+	`, block.Height, block.Hash)
+
+	if err != nil {
+		return fmt.Errorf("failed to demote previous block at height %d: %w", block.Height, err)
+	}
+	if err := applyBlockTransfers(ctx, tx, demoted, true); err != nil {
+		return fmt.Errorf("failed to reverse transfers of previous block at height %d: %w", block.Height, err)
+	}
+
+	// Drop the contents stored for this block before (re-ingested or re-included orphaned block)
+	// so they are rewritten from the fetched block
+	if err := clearBlockContents(ctx, tx, [][]byte{block.Hash}); err != nil {
+		return fmt.Errorf("failed to clear stored contents of block %d: %w", block.Height, err)
+	}
+
 	// Insert block
 	_, err = tx.Exec(ctx, `
-		INSERT INTO blocks (height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, orphaned)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		ON CONFLICT (height) DO UPDATE SET
-			hash = EXCLUDED.hash,
+		INSERT INTO blocks (height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, canonical, `+strings.Join(blockHeaderColumns, ", ")+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		ON CONFLICT (hash) DO UPDATE SET
+			height = EXCLUDED.height,
 			parent_hash = EXCLUDED.parent_hash,
 			miner = EXCLUDED.miner,
 			gas_used = EXCLUDED.gas_used,
 			gas_limit = EXCLUDED.gas_limit,
 			timestamp = EXCLUDED.timestamp,
 			tx_count = EXCLUDED.tx_count,
-			orphaned = EXCLUDED.orphaned,
+			canonical = EXCLUDED.canonical,
+			`+blockHeaderUpdateSQL+`,
 			updated_at = NOW()
-	`, block.Height, block.Hash, block.ParentHash, block.Miner,
-		block.GasUsed, 0, block.Timestamp, block.TxCount, false) // gas_limit not in domain model
+	`, append([]any{block.Height, block.Hash, block.ParentHash, block.Miner,
+		block.GasUsed, block.GasLimit, block.Timestamp, block.TxCount, true}, blockHeaderValues(block)...)...)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert block %d: %w", block.Height, err)
 	}
 
 	// Insert transactions extracted from block
+	var contracts [][]byte // Emitters of decoded token and NFT transfers
 	for _, txn := range block.Transactions {
 		// Calculate fee_wei: gas_used * gas_price
-		feeWei := new(big.Int).Mul(
-			new(big.Int).SetUint64(txn.GasUsed),
-			new(big.Int).SetUint64(txn.GasPrice),
-		).String()
+		feeWei := txFeeWei(txn).String()
+
+		accessList, err := accessListJSON(txn.AccessList)
+		if err != nil {
+			return fmt.Errorf("failed to encode access list of transaction %x: %w", txn.Hash, err)
+		}
 
 		_, err = tx.Exec(ctx, `
-			INSERT INTO transactions (hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei, gas_used, gas_price, nonce, success, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-			ON CONFLICT (hash) DO NOTHING
-		`, txn.Hash, block.Height, txn.TxIndex, txn.FromAddr, txn.ToAddr,
-			txn.ValueWei, feeWei, txn.GasUsed, txn.GasPrice, txn.Nonce, txn.Success, time.Now())
+			INSERT INTO transactions (hash, block_hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei, gas_used, gas_price, nonce, success, contract_address, created_at,
+			                          tx_type, max_fee_per_gas, max_priority_fee_per_gas, effective_gas_price, input, access_list,
+			                          max_fee_per_blob_gas, blob_versioned_hashes, blob_gas_used, blob_gas_price)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+			ON CONFLICT (hash, block_hash) DO NOTHING
+		`, txn.Hash, block.Hash, block.Height, txn.TxIndex, txn.FromAddr, txn.ToAddr,
+			txn.ValueWei, feeWei, txn.GasUsed, numericFromBig(txn.GasPrice), txn.Nonce, txn.Success, txn.ContractAddress, time.Now(),
+			int16(txn.Type), numericFromBig(txn.MaxFeePerGas), numericFromBig(txn.MaxPriorityFeePerGas),
+			numericFromBig(txn.EffectiveGasPrice), txn.Input, accessList,
+			numericFromBig(txn.MaxFeePerBlobGas), txn.BlobHashes, blobGasUsed(txn), numericFromBig(txn.BlobGasPrice))
 
 		if err != nil {
 			return fmt.Errorf("failed to insert transaction %x for block %d: %w", txn.Hash, block.Height, err)
 		}
+
+		// Insert receipt logs (empty in basic mode)
+		for _, log := range txn.Logs {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO logs (tx_hash, block_hash, log_index, address, topic0, topic1, topic2, topic3, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				ON CONFLICT (tx_hash, block_hash, log_index) DO NOTHING
+			`, txn.Hash, block.Hash, log.LogIndex, log.Address,
+				log.Topics[0], log.Topics[1], log.Topics[2], log.Topics[3], log.Data)
+
+			if err != nil {
+				return fmt.Errorf("failed to insert log %d of transaction %x: %w", log.LogIndex, txn.Hash, err)
+			}
+		}
+
+		// Insert ERC-20 transfers decoded from the logs and update token balances
+		for _, transfer := range decodeTokenTransfers(txn.Logs) {
+			_, err = tx.Exec(ctx, tokenLedger.newTransfersSQL(`
+				INSERT INTO token_transfers (tx_hash, block_hash, log_index, block_height, token_address, from_addr, to_addr, amount)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				ON CONFLICT (tx_hash, block_hash, log_index) DO NOTHING
+			`), txn.Hash, block.Hash, int32(transfer.LogIndex), block.Height, transfer.Token, transfer.From, transfer.To,
+				numericFromBig(transfer.Amount))
+
+			if err != nil {
+				return fmt.Errorf("failed to insert token transfer %d of transaction %x: %w", transfer.LogIndex, txn.Hash, err)
+			}
+			contracts = append(contracts, transfer.Token)
+		}
+
+		// Insert ERC-721 and ERC-1155 transfers decoded from the logs and update NFT ownership
+		for _, transfer := range decodeNFTTransfers(txn.Logs) {
+			_, err = tx.Exec(ctx, nftLedger.newTransfersSQL(`
+				INSERT INTO nft_transfers (tx_hash, block_hash, log_index, batch_index, block_height, contract_address, token_id,
+				                           standard, from_addr, to_addr, amount)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+				ON CONFLICT (tx_hash, block_hash, log_index, batch_index) DO NOTHING
+			`), append([]any{txn.Hash, block.Hash}, nftTransferValues(transfer, block.Height)...)...)
+
+			if err != nil {
+				return fmt.Errorf("failed to insert NFT transfer %d of transaction %x: %w", transfer.LogIndex, txn.Hash, err)
+			}
+			contracts = append(contracts, transfer.Contract)
+		}
+
+		// Insert internal transactions (empty unless call tracing is enabled)
+		for _, call := range txn.InternalTxs {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO internal_transactions (tx_hash, block_hash, block_height, trace_address, call_type, from_addr, to_addr,
+				                                   value_wei, gas, gas_used, depth, error)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				ON CONFLICT (tx_hash, block_hash, trace_address) DO NOTHING
+			`, append([]any{txn.Hash, block.Hash, block.Height}, internalTxValues(call)...)...)
+
+			if err != nil {
+				return fmt.Errorf("failed to insert internal transaction %v of transaction %x: %w", call.TraceAddress, txn.Hash, err)
+			}
+		}
+	}
+
+	// Queue token and NFT contracts for metadata resolution
+	if len(contracts) > 0 {
+		if _, err := tx.Exec(ctx, queueTokensSQL, contracts); err != nil {
+			return fmt.Errorf("failed to queue token contracts of block %d: %w", block.Height, err)
+		}
+	}
+
+	// Insert withdrawals (none before Shanghai)
+	for _, w := range block.Withdrawals {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO withdrawals (block_hash, block_height, withdrawal_index, validator_index, address, amount_gwei)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (block_hash, withdrawal_index) DO NOTHING
+		`, block.Hash, block.Height, w.Index, w.ValidatorIndex, w.Address, numericFromUint64(w.AmountGwei))
+
+		if err != nil {
+			return fmt.Errorf("failed to insert withdrawal %d for block %d: %w", w.Index, block.Height, err)
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -137,7 +251,33 @@ func (a *IndexerAdapter) InsertBlock(ctx context.Context, block *index.Block) er
 	return nil
 }
 
-// MarkBlocksOrphaned marks blocks as orphaned (soft delete for reorg handling)
+// clearBlockContents deletes the stored transactions (with their logs, token and NFT transfers and internal
+// transactions) and withdrawals of blocks that are about to be written again, after reversing the transfers of
+// the canonical ones. Re-ingesting a block then replaces stale or corrupted rows instead of keeping them next
+// to the fetched ones. Block hashes that are not stored yet are ignored.
+func clearBlockContents(ctx context.Context, tx pgx.Tx, blockHashes [][]byte) error {
+	canonical, err := queryBlockHashes(ctx, tx, `
+		SELECT hash FROM blocks WHERE hash = ANY($1) AND canonical = TRUE
+	`, blockHashes)
+	if err != nil {
+		return fmt.Errorf("failed to find stored canonical blocks: %w", err)
+	}
+	if err := applyBlockTransfers(ctx, tx, canonical, true); err != nil {
+		return fmt.Errorf("failed to reverse stored transfers: %w", err)
+	}
+
+	// Logs, transfers and internal transactions cascade from their transactions
+	if _, err := tx.Exec(ctx, `DELETE FROM transactions WHERE block_hash = ANY($1)`, blockHashes); err != nil {
+		return fmt.Errorf("failed to delete stored transactions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM withdrawals WHERE block_hash = ANY($1)`, blockHashes); err != nil {
+		return fmt.Errorf("failed to delete stored withdrawals: %w", err)
+	}
+	return nil
+}
+
+// MarkBlocksOrphaned marks the canonical blocks in the height range as orphaned (soft delete for reorg handling)
+// The rows are kept and stay queryable by hash; token balances and NFT ownership no longer include their transfers
 func (a *IndexerAdapter) MarkBlocksOrphaned(ctx context.Context, startHeight, endHeight uint64) error {
 	tx, err := a.pool.Pool.Begin(ctx)
 	if err != nil {
@@ -145,15 +285,19 @@ func (a *IndexerAdapter) MarkBlocksOrphaned(ctx context.Context, startHeight, en
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, `
+	orphaned, err := queryBlockHashes(ctx, tx, `
 		UPDATE blocks
-		SET orphaned = true, updated_at = NOW()
-		WHERE height >= $1 AND height <= $2
+		SET canonical = FALSE, finality = 'latest', updated_at = NOW()
+		WHERE height >= $1 AND height <= $2 AND canonical = TRUE
+		RETURNING hash
 	`, startHeight, endHeight)
 
 	if err != nil {
 		return fmt.Errorf("failed to mark blocks as orphaned: %w", err)
 	}
+	if err := applyBlockTransfers(ctx, tx, orphaned, true); err != nil {
+		return fmt.Errorf("failed to reverse transfers of orphaned blocks: %w", err)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit orphaned blocks update: %w", err)
@@ -162,23 +306,496 @@ func (a *IndexerAdapter) MarkBlocksOrphaned(ctx context.Context, startHeight, en
 	return nil
 }
 
+// RecordReorg stores a reorg event with its replaced and replacing block hashes
+func (a *IndexerAdapter) RecordReorg(ctx context.Context, event *index.ReorgEvent) error {
+	tx, err := a.pool.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var reorgID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO reorgs (detected_at, fork_point_height, fork_point_hash, depth,
+		                    old_head_height, old_head_hash, new_head_height, new_head_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, event.DetectedAt, event.ForkPointHeight, event.ForkPointHash, event.Depth,
+		event.OldHeadHeight, event.OldHeadHash, event.NewHeadHeight, event.NewHeadHash).Scan(&reorgID)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert reorg: %w", err)
+	}
+
+	for _, block := range event.Blocks {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO reorg_blocks (reorg_id, height, old_hash, new_hash)
+			VALUES ($1, $2, $3, $4)
+		`, reorgID, block.Height, block.OldHash, block.NewHash)
+
+		if err != nil {
+			return fmt.Errorf("failed to insert reorg block %d: %w", block.Height, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reorg %d: %w", reorgID, err)
+	}
+
+	return nil
+}
+
+// SaveCompletedRange records [startHeight, endHeight] as indexed in indexer_state
+// Overlapping and adjacent ranges are merged into one row
+func (a *IndexerAdapter) SaveCompletedRange(ctx context.Context, startHeight, endHeight uint64) error {
+	tx, err := a.pool.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize writers so concurrent merges cannot interleave
+	if _, err := tx.Exec(ctx, `LOCK TABLE indexer_state IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock indexer_state: %w", err)
+	}
+
+	var mergedStart, mergedEnd int64
+	err = tx.QueryRow(ctx, `
+		WITH merged AS (
+			DELETE FROM indexer_state
+			WHERE start_height <= $2 + 1 AND end_height >= $1 - 1
+			RETURNING start_height, end_height
+		)
+		SELECT LEAST($1, COALESCE(MIN(start_height), $1)), GREATEST($2, COALESCE(MAX(end_height), $2))
+		FROM merged
+	`, int64(startHeight), int64(endHeight)).Scan(&mergedStart, &mergedEnd)
+	if err != nil {
+		return fmt.Errorf("failed to merge completed ranges: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO indexer_state (start_height, end_height, updated_at)
+		VALUES ($1, $2, NOW())
+	`, mergedStart, mergedEnd)
+	if err != nil {
+		return fmt.Errorf("failed to insert completed range %d-%d: %w", mergedStart, mergedEnd, err)
+	}
+
+	// Heights of the range are indexed now, so they leave the dead-letter list
+	_, err = tx.Exec(ctx, `
+		DELETE FROM backfill_failures WHERE height BETWEEN $1 AND $2
+	`, int64(startHeight), int64(endHeight))
+	if err != nil {
+		return fmt.Errorf("failed to clear failed heights %d-%d: %w", startHeight, endHeight, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit completed range %d-%d: %w", startHeight, endHeight, err)
+	}
+
+	return nil
+}
+
+// RecordFailedHeight upserts a dead-lettered height into backfill_failures
+// Attempts accumulate across backfill runs
+func (a *IndexerAdapter) RecordFailedHeight(ctx context.Context, height uint64, attempts int, lastErr string) error {
+	_, err := a.pool.Pool.Exec(ctx, `
+		INSERT INTO backfill_failures (height, attempts, last_error, failed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (height) DO UPDATE SET
+			attempts = backfill_failures.attempts + EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			failed_at = EXCLUDED.failed_at
+	`, int64(height), attempts, lastErr)
+	if err != nil {
+		return fmt.Errorf("failed to record failed height %d: %w", height, err)
+	}
+	return nil
+}
+
+// GetCompletedRanges returns the completed height ranges ordered by start height
+func (a *IndexerAdapter) GetCompletedRanges(ctx context.Context) ([]index.HeightRange, error) {
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT start_height, end_height
+		FROM indexer_state
+		ORDER BY start_height
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query completed ranges: %w", err)
+	}
+	defer rows.Close()
+
+	var ranges []index.HeightRange
+	for rows.Next() {
+		var r index.HeightRange
+		if err := rows.Scan(&r.Start, &r.End); err != nil {
+			return nil, fmt.Errorf("failed to scan completed range: %w", err)
+		}
+		ranges = append(ranges, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate completed ranges: %w", err)
+	}
+
+	return ranges, nil
+}
+
+// FindMissingRanges returns the ranges of [startHeight, endHeight] without a canonical block
+// Uses a LEAD() window over the existing heights, with sentinels just outside the range so
+// leading and trailing gaps are found too
+func (a *IndexerAdapter) FindMissingRanges(ctx context.Context, startHeight, endHeight uint64) ([]index.HeightRange, error) {
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT height + 1 AS gap_start, next_height - 1 AS gap_end
+		FROM (
+			SELECT height, LEAD(height) OVER (ORDER BY height) AS next_height
+			FROM (
+				SELECT height FROM blocks
+				WHERE canonical = TRUE AND height BETWEEN $1 AND $2
+				UNION ALL SELECT $1::BIGINT - 1
+				UNION ALL SELECT $2::BIGINT + 1
+			) heights
+		) pairs
+		WHERE next_height > height + 1
+		ORDER BY gap_start
+	`, int64(startHeight), int64(endHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query missing heights: %w", err)
+	}
+	defer rows.Close()
+
+	var gaps []index.HeightRange
+	for rows.Next() {
+		var gap index.HeightRange
+		if err := rows.Scan(&gap.Start, &gap.End); err != nil {
+			return nil, fmt.Errorf("failed to scan missing range: %w", err)
+		}
+		gaps = append(gaps, gap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate missing ranges: %w", err)
+	}
+
+	return gaps, nil
+}
+
+// UpdateFinality promotes canonical blocks to finalized (height <= finalized) and safe (height <= safe)
+// Only blocks not yet at their status are touched, so repeated calls are cheap
+func (a *IndexerAdapter) UpdateFinality(ctx context.Context, safe, finalized uint64) error {
+	tx, err := a.pool.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE blocks SET finality = $2, updated_at = NOW()
+		WHERE canonical = TRUE AND height <= $1 AND finality <> $2
+	`, int64(finalized), index.FinalityFinalized)
+	if err != nil {
+		return fmt.Errorf("failed to mark blocks finalized up to %d: %w", finalized, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE blocks SET finality = $3, updated_at = NOW()
+		WHERE canonical = TRUE AND height > $1 AND height <= $2 AND finality = $4
+	`, int64(finalized), int64(safe), index.FinalitySafe, index.FinalityLatest)
+	if err != nil {
+		return fmt.Errorf("failed to mark blocks safe up to %d: %w", safe, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit finality update: %w", err)
+	}
+
+	return nil
+}
+
+// GetFinalizedHeight returns the height of the highest finalized canonical block (0 if none)
+func (a *IndexerAdapter) GetFinalizedHeight(ctx context.Context) (uint64, error) {
+	var height int64
+	err := a.pool.Pool.QueryRow(ctx, `
+		SELECT COALESCE(MAX(height), 0)
+		FROM blocks
+		WHERE canonical = TRUE AND finality = $1
+	`, index.FinalityFinalized).Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query finalized height: %w", err)
+	}
+	return uint64(height), nil
+}
+
+// GetPendingTokens returns up to limit token contracts queued for metadata resolution whose next attempt is due
+func (a *IndexerAdapter) GetPendingTokens(ctx context.Context, limit int) ([][]byte, error) {
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT address
+		FROM tokens
+		WHERE resolved_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY next_attempt_at ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses [][]byte
+	for rows.Next() {
+		var address []byte
+		if err := rows.Scan(&address); err != nil {
+			return nil, fmt.Errorf("failed to scan pending token: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending tokens: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// SaveTokenMetadata stores the resolved metadata of a token contract, removing it from the queue
+func (a *IndexerAdapter) SaveTokenMetadata(ctx context.Context, metadata *index.TokenMetadata) error {
+	var decimals *int16
+	if metadata.Decimals != nil {
+		d := int16(*metadata.Decimals)
+		decimals = &d
+	}
+
+	_, err := a.pool.Pool.Exec(ctx, `
+		INSERT INTO tokens (address, standard, name, symbol, decimals, total_supply, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (address) DO UPDATE SET
+			standard = EXCLUDED.standard,
+			name = EXCLUDED.name,
+			symbol = EXCLUDED.symbol,
+			decimals = EXCLUDED.decimals,
+			total_supply = EXCLUDED.total_supply,
+			resolved_at = EXCLUDED.resolved_at
+	`, metadata.Address, metadata.Standard, metadata.Name, metadata.Symbol, decimals, numericFromBig(metadata.TotalSupply))
+	if err != nil {
+		return fmt.Errorf("failed to save token metadata: %w", err)
+	}
+	return nil
+}
+
+// DeferToken postpones the next metadata resolution attempt of a token contract after a failure
+// The delay is retryDelay doubled for every earlier failed attempt (capped at 2^10 times retryDelay)
+func (a *IndexerAdapter) DeferToken(ctx context.Context, address []byte, retryDelay time.Duration) error {
+	_, err := a.pool.Pool.Exec(ctx, `
+		UPDATE tokens
+		SET next_attempt_at = NOW() + $2 * POWER(2, LEAST(attempts, 10)) * INTERVAL '1 second',
+		    attempts = attempts + 1
+		WHERE address = $1
+	`, address, retryDelay.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to defer token: %w", err)
+	}
+	return nil
+}
+
+// GetBlocksWithTxHashes returns the canonical blocks at the given heights ordered by height
+// Transactions only carry Hash and TxIndex; heights without a canonical block are skipped
+func (a *IndexerAdapter) GetBlocksWithTxHashes(ctx context.Context, heights []uint64) ([]*index.Block, error) {
+	params := make([]int64, len(heights))
+	for i, h := range heights {
+		params[i] = int64(h)
+	}
+
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT height, hash, parent_hash, miner, gas_used, timestamp, tx_count
+		FROM blocks
+		WHERE canonical = TRUE AND height = ANY($1)
+		ORDER BY height
+	`, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []*index.Block
+	byHash := make(map[string]*index.Block)
+	for rows.Next() {
+		var b index.Block
+		if err := rows.Scan(&b.Height, &b.Hash, &b.ParentHash, &b.Miner,
+			&b.GasUsed, &b.Timestamp, &b.TxCount); err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocks = append(blocks, &b)
+		byHash[string(b.Hash)] = &b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate blocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	blockHashes := make([][]byte, len(blocks))
+	for i, b := range blocks {
+		blockHashes[i] = b.Hash
+	}
+	txRows, err := a.pool.Pool.Query(ctx, `
+		SELECT block_hash, hash, tx_index
+		FROM transactions
+		WHERE block_hash = ANY($1)
+		ORDER BY block_hash, tx_index
+	`, blockHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transaction hashes: %w", err)
+	}
+	defer txRows.Close()
+
+	for txRows.Next() {
+		var blockHash []byte
+		var tx index.Transaction
+		if err := txRows.Scan(&blockHash, &tx.Hash, &tx.TxIndex); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction hash: %w", err)
+		}
+		if b, ok := byHash[string(blockHash)]; ok {
+			b.Transactions = append(b.Transactions, tx)
+		}
+	}
+	if err := txRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transaction hashes: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// FindParentHashBreaks returns the canonical blocks of [startHeight, endHeight] whose parent hash
+// differs from the hash of the canonical block one height below (Chain holds that hash)
+func (a *IndexerAdapter) FindParentHashBreaks(ctx context.Context, startHeight, endHeight uint64) ([]index.BlockMismatch, error) {
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT b.height, b.parent_hash, p.hash
+		FROM blocks b
+		JOIN blocks p ON p.height = b.height - 1 AND p.canonical = TRUE
+		WHERE b.canonical = TRUE AND b.height BETWEEN $1 AND $2 AND b.parent_hash <> p.hash
+		ORDER BY b.height
+	`, int64(startHeight), int64(endHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query parent hash links: %w", err)
+	}
+	defer rows.Close()
+
+	var breaks []index.BlockMismatch
+	for rows.Next() {
+		var height int64
+		var parentHash, previousHash []byte
+		if err := rows.Scan(&height, &parentHash, &previousHash); err != nil {
+			return nil, fmt.Errorf("failed to scan parent hash link: %w", err)
+		}
+		breaks = append(breaks, index.BlockMismatch{
+			Height: uint64(height),
+			Field:  index.FieldParentLink,
+			Stored: fmt.Sprintf("0x%x", parentHash),
+			Chain:  fmt.Sprintf("0x%x", previousHash),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate parent hash links: %w", err)
+	}
+
+	return breaks, nil
+}
+
+// RecordIntegrityResults upserts the open mismatches found for fields in the checked ranges and
+// resolves the other open mismatches of those fields and ranges, in one transaction
+func (a *IndexerAdapter) RecordIntegrityResults(ctx context.Context, fields []string, checked []index.HeightRange, mismatches []index.BlockMismatch) error {
+	rangeStarts := make([]int64, len(checked))
+	rangeEnds := make([]int64, len(checked))
+	for i, r := range checked {
+		rangeStarts[i] = int64(r.Start)
+		rangeEnds[i] = int64(r.End)
+	}
+	mismatchHeights := make([]int64, len(mismatches))
+	mismatchFields := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		mismatchHeights[i] = int64(m.Height)
+		mismatchFields[i] = m.Field
+	}
+
+	tx, err := a.pool.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE integrity_mismatches SET resolved_at = NOW()
+		WHERE resolved_at IS NULL
+			AND field = ANY($1)
+			AND EXISTS (
+				SELECT 1 FROM unnest($2::BIGINT[], $3::BIGINT[]) AS r(start_height, end_height)
+				WHERE height BETWEEN r.start_height AND r.end_height
+			)
+			AND (height, field) NOT IN (
+				SELECT m.height, m.field FROM unnest($4::BIGINT[], $5::TEXT[]) AS m(height, field)
+			)
+	`, fields, rangeStarts, rangeEnds, mismatchHeights, mismatchFields)
+	if err != nil {
+		return fmt.Errorf("failed to resolve integrity mismatches: %w", err)
+	}
+
+	for _, m := range mismatches {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO integrity_mismatches (height, field, stored, chain)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (height, field) WHERE resolved_at IS NULL DO UPDATE SET
+				stored = EXCLUDED.stored,
+				chain = EXCLUDED.chain,
+				last_seen_at = NOW()
+		`, int64(m.Height), m.Field, m.Stored, m.Chain)
+		if err != nil {
+			return fmt.Errorf("failed to record integrity mismatch at height %d: %w", m.Height, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit integrity results: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpenIntegrityMismatches returns the unresolved integrity mismatches, ordered by height and field
+func (a *IndexerAdapter) GetOpenIntegrityMismatches(ctx context.Context) ([]index.BlockMismatch, error) {
+	rows, err := a.pool.Pool.Query(ctx, `
+		SELECT height, field, stored, chain
+		FROM integrity_mismatches
+		WHERE resolved_at IS NULL
+		ORDER BY height, field
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open integrity mismatches: %w", err)
+	}
+	defer rows.Close()
+
+	var mismatches []index.BlockMismatch
+	for rows.Next() {
+		var m index.BlockMismatch
+		var height int64
+		if err := rows.Scan(&height, &m.Field, &m.Stored, &m.Chain); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity mismatch: %w", err)
+		}
+		m.Height = uint64(height)
+		mismatches = append(mismatches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate integrity mismatches: %w", err)
+	}
+
+	return mismatches, nil
+}
+
 // ParseRPCBlock converts an ethereum block to the index.Block domain model
-// Includes full transaction extraction with signature recovery
+// Includes the full header and withdrawals, and transaction extraction with signature recovery
 func ParseRPCBlock(rpcBlock *types.Block) *index.Block {
 	if rpcBlock == nil {
 		return nil
 	}
 
-	block := &index.Block{
-		Height:       rpcBlock.NumberU64(),
-		Hash:         rpcBlock.Hash().Bytes(),
-		ParentHash:   rpcBlock.ParentHash().Bytes(),
-		Timestamp:    rpcBlock.Time(),
-		Miner:        rpcBlock.Coinbase().Bytes(),
-		GasUsed:      rpcBlock.GasUsed(),
-		TxCount:      len(rpcBlock.Transactions()),
-		Transactions: make([]index.Transaction, 0, len(rpcBlock.Transactions())),
-	}
+	block := index.ParseRPCBlockHeader(rpcBlock)
+	block.Transactions = make([]index.Transaction, 0, len(rpcBlock.Transactions()))
 
 	// Extract transactions from block
 	for txIndex, tx := range rpcBlock.Transactions() {
@@ -212,13 +829,13 @@ func parseTransaction(tx *types.Transaction, txIndex int) index.Transaction {
 	}
 	// If nil, toAddr remains nil (contract creation transaction)
 
-	// Get gas price in wei
-	gasPrice := uint64(0)
+	// Get gas price in wei (the fee cap for EIP-1559 types)
+	gasPrice := new(big.Int)
 	if tx.GasPrice() != nil {
-		gasPrice = tx.GasPrice().Uint64()
+		gasPrice.Set(tx.GasPrice())
 	}
 
-	return index.Transaction{
+	txn := index.Transaction{
 		Hash:     tx.Hash().Bytes(),
 		TxIndex:  txIndex,
 		FromAddr: fromAddr,
@@ -229,7 +846,29 @@ func parseTransaction(tx *types.Transaction, txIndex int) index.Transaction {
 		Nonce:    tx.Nonce(),
 		Success:  true,            // Assume success (no receipt data)
 		Logs:     []index.Log{},   // Empty for basic mode (no receipt)
+		Type:     tx.Type(),
+		Input:    append([]byte{}, tx.Data()...), // transactions.input is NOT NULL
 	}
+
+	// Typed transaction fields; go-ethereum reports the gas price as fee and tip cap for older types
+	if tx.Type() != types.LegacyTxType {
+		txn.AccessList = tx.AccessList()
+		if txn.AccessList == nil {
+			txn.AccessList = types.AccessList{}
+		}
+	}
+	if tx.Type() >= types.DynamicFeeTxType {
+		txn.MaxFeePerGas = new(big.Int).Set(tx.GasFeeCap())
+		txn.MaxPriorityFeePerGas = new(big.Int).Set(tx.GasTipCap())
+	}
+	if tx.Type() == types.BlobTxType {
+		txn.MaxFeePerBlobGas = new(big.Int).Set(tx.BlobGasFeeCap())
+		for _, blobHash := range tx.BlobHashes() {
+			txn.BlobHashes = append(txn.BlobHashes, blobHash.Bytes())
+		}
+	}
+
+	return txn
 }
 
 // FormatBlockForDisplay converts index.Block to display-friendly hex strings
@@ -241,6 +880,7 @@ func FormatBlockForDisplay(block *index.Block) map[string]interface{} {
 		"timestamp":   block.Timestamp,
 		"miner":       "0x" + hex.EncodeToString(block.Miner),
 		"gas_used":    block.GasUsed,
+		"gas_limit":   block.GasLimit,
 		"tx_count":    block.TxCount,
 	}
 }
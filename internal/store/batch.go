@@ -0,0 +1,583 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Staging tables for bulk writes. They live for a single DB transaction (ON COMMIT DROP),
+// so concurrent batch writers on different connections never see each other's rows
+const createStagingTablesSQL = `
+	CREATE TEMP TABLE blocks_staging (
+		height BIGINT NOT NULL,
+		hash BYTEA NOT NULL,
+		parent_hash BYTEA NOT NULL,
+		miner BYTEA NOT NULL,
+		gas_used NUMERIC NOT NULL,
+		gas_limit NUMERIC NOT NULL,
+		timestamp BIGINT NOT NULL,
+		tx_count INTEGER NOT NULL,
+		base_fee_per_gas NUMERIC,
+		difficulty NUMERIC NOT NULL,
+		nonce BYTEA NOT NULL,
+		extra_data BYTEA NOT NULL,
+		state_root BYTEA NOT NULL,
+		transactions_root BYTEA NOT NULL,
+		receipts_root BYTEA NOT NULL,
+		logs_bloom BYTEA NOT NULL,
+		mix_hash BYTEA NOT NULL,
+		sha3_uncles BYTEA NOT NULL,
+		size BIGINT NOT NULL,
+		withdrawals_root BYTEA,
+		blob_gas_used NUMERIC,
+		excess_blob_gas NUMERIC,
+		parent_beacon_block_root BYTEA
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE withdrawals_staging (
+		block_hash BYTEA NOT NULL,
+		block_height BIGINT NOT NULL,
+		withdrawal_index BIGINT NOT NULL,
+		validator_index BIGINT NOT NULL,
+		address BYTEA NOT NULL,
+		amount_gwei NUMERIC NOT NULL
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE transactions_staging (
+		hash BYTEA NOT NULL,
+		block_hash BYTEA NOT NULL,
+		block_height BIGINT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		from_addr BYTEA NOT NULL,
+		to_addr BYTEA,
+		value_wei NUMERIC NOT NULL,
+		fee_wei NUMERIC NOT NULL,
+		gas_used NUMERIC NOT NULL,
+		gas_price NUMERIC NOT NULL,
+		nonce BIGINT NOT NULL,
+		success BOOLEAN NOT NULL,
+		contract_address BYTEA,
+		tx_type SMALLINT NOT NULL,
+		max_fee_per_gas NUMERIC,
+		max_priority_fee_per_gas NUMERIC,
+		effective_gas_price NUMERIC,
+		input BYTEA NOT NULL,
+		access_list JSONB,
+		max_fee_per_blob_gas NUMERIC,
+		blob_versioned_hashes BYTEA[],
+		blob_gas_used NUMERIC,
+		blob_gas_price NUMERIC
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE logs_staging (
+		tx_hash BYTEA NOT NULL,
+		block_hash BYTEA NOT NULL,
+		log_index INTEGER NOT NULL,
+		address BYTEA NOT NULL,
+		topic0 BYTEA,
+		topic1 BYTEA,
+		topic2 BYTEA,
+		topic3 BYTEA,
+		data BYTEA NOT NULL
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE token_transfers_staging (
+		tx_hash BYTEA NOT NULL,
+		block_hash BYTEA NOT NULL,
+		log_index INTEGER NOT NULL,
+		block_height BIGINT NOT NULL,
+		token_address BYTEA NOT NULL,
+		from_addr BYTEA NOT NULL,
+		to_addr BYTEA NOT NULL,
+		amount NUMERIC NOT NULL
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE nft_transfers_staging (
+		tx_hash BYTEA NOT NULL,
+		block_hash BYTEA NOT NULL,
+		log_index INTEGER NOT NULL,
+		batch_index INTEGER NOT NULL,
+		block_height BIGINT NOT NULL,
+		contract_address BYTEA NOT NULL,
+		token_id NUMERIC NOT NULL,
+		standard TEXT NOT NULL,
+		from_addr BYTEA NOT NULL,
+		to_addr BYTEA NOT NULL,
+		amount NUMERIC NOT NULL
+	) ON COMMIT DROP;
+
+	CREATE TEMP TABLE internal_transactions_staging (
+		tx_hash BYTEA NOT NULL,
+		block_hash BYTEA NOT NULL,
+		block_height BIGINT NOT NULL,
+		trace_address INTEGER[] NOT NULL,
+		call_type TEXT NOT NULL,
+		from_addr BYTEA NOT NULL,
+		to_addr BYTEA,
+		value_wei NUMERIC NOT NULL,
+		gas NUMERIC NOT NULL,
+		gas_used NUMERIC NOT NULL,
+		depth INTEGER NOT NULL,
+		error TEXT
+	) ON COMMIT DROP;
+`
+
+// blockHeaderColumns are the header columns of the blocks table written after the base columns,
+// in the order of the values returned by blockHeaderValues
+var blockHeaderColumns = []string{
+	"base_fee_per_gas", "difficulty", "nonce", "extra_data", "state_root", "transactions_root", "receipts_root",
+	"logs_bloom", "mix_hash", "sha3_uncles", "size", "withdrawals_root", "blob_gas_used", "excess_blob_gas",
+	"parent_beacon_block_root",
+}
+
+// blockHeaderUpdateSQL updates the header columns of an existing block row in ON CONFLICT clauses
+var blockHeaderUpdateSQL = func() string {
+	set := make([]string, len(blockHeaderColumns))
+	for i, column := range blockHeaderColumns {
+		set[i] = column + " = EXCLUDED." + column
+	}
+	return strings.Join(set, ",\n\t\t\t")
+}()
+
+// copyRows holds the COPY rows of a batch, one slice per staging table
+type copyRows struct {
+	blocks         [][]any
+	txs            [][]any
+	logs           [][]any
+	tokenTransfers [][]any
+	nftTransfers   [][]any
+	withdrawals    [][]any
+	internalTxs    [][]any
+}
+
+// InsertBlocks writes a whole batch of blocks with their transactions, logs, token and NFT transfers, internal
+// transactions and withdrawals in one DB transaction
+// Rows are streamed into staging tables with COPY and then merged into the real tables, using the
+// same rules as InsertBlock (each block becomes canonical at its height, replaced blocks are kept as orphaned,
+// the stored contents of re-ingested blocks are replaced, new token contracts are queued for metadata resolution)
+func (a *IndexerAdapter) InsertBlocks(ctx context.Context, blocks []*index.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	rows, err := buildCopyRows(blocks)
+	if err != nil {
+		return err
+	}
+
+	tx, err := a.pool.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, createStagingTablesSQL); err != nil {
+		return fmt.Errorf("failed to create staging tables: %w", err)
+	}
+
+	// COPY rows into staging tables
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"blocks_staging"},
+		append([]string{"height", "hash", "parent_hash", "miner", "gas_used", "gas_limit", "timestamp", "tx_count"},
+			blockHeaderColumns...),
+		pgx.CopyFromRows(rows.blocks)); err != nil {
+		return fmt.Errorf("failed to copy %d blocks: %w", len(rows.blocks), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"transactions_staging"},
+		[]string{"hash", "block_hash", "block_height", "tx_index", "from_addr", "to_addr", "value_wei", "fee_wei",
+			"gas_used", "gas_price", "nonce", "success", "contract_address",
+			"tx_type", "max_fee_per_gas", "max_priority_fee_per_gas", "effective_gas_price", "input", "access_list",
+			"max_fee_per_blob_gas", "blob_versioned_hashes", "blob_gas_used", "blob_gas_price"},
+		pgx.CopyFromRows(rows.txs)); err != nil {
+		return fmt.Errorf("failed to copy %d transactions: %w", len(rows.txs), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"logs_staging"},
+		[]string{"tx_hash", "block_hash", "log_index", "address", "topic0", "topic1", "topic2", "topic3", "data"},
+		pgx.CopyFromRows(rows.logs)); err != nil {
+		return fmt.Errorf("failed to copy %d logs: %w", len(rows.logs), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"token_transfers_staging"},
+		[]string{"tx_hash", "block_hash", "log_index", "block_height", "token_address", "from_addr", "to_addr", "amount"},
+		pgx.CopyFromRows(rows.tokenTransfers)); err != nil {
+		return fmt.Errorf("failed to copy %d token transfers: %w", len(rows.tokenTransfers), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"nft_transfers_staging"},
+		[]string{"tx_hash", "block_hash", "log_index", "batch_index", "block_height", "contract_address", "token_id",
+			"standard", "from_addr", "to_addr", "amount"},
+		pgx.CopyFromRows(rows.nftTransfers)); err != nil {
+		return fmt.Errorf("failed to copy %d NFT transfers: %w", len(rows.nftTransfers), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"withdrawals_staging"},
+		[]string{"block_hash", "block_height", "withdrawal_index", "validator_index", "address", "amount_gwei"},
+		pgx.CopyFromRows(rows.withdrawals)); err != nil {
+		return fmt.Errorf("failed to copy %d withdrawals: %w", len(rows.withdrawals), err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"internal_transactions_staging"},
+		[]string{"tx_hash", "block_hash", "block_height", "trace_address", "call_type", "from_addr", "to_addr",
+			"value_wei", "gas", "gas_used", "depth", "error"},
+		pgx.CopyFromRows(rows.internalTxs)); err != nil {
+		return fmt.Errorf("failed to copy %d internal transactions: %w", len(rows.internalTxs), err)
+	}
+
+	// Merge staging tables into real tables (parents first for foreign keys)
+	// Demote other canonical blocks at the staged heights (kept for history) and reverse their transfers
+	demoted, err := queryBlockHashes(ctx, tx, `
+		UPDATE blocks b SET canonical = FALSE, finality = 'latest', updated_at = NOW()
+		FROM blocks_staging s
+		WHERE b.height = s.height AND b.hash <> s.hash AND b.canonical = TRUE
+		RETURNING b.hash
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to demote replaced blocks: %w", err)
+	}
+	if err := applyBlockTransfers(ctx, tx, demoted, true); err != nil {
+		return fmt.Errorf("failed to reverse transfers of replaced blocks: %w", err)
+	}
+
+	// Drop the contents stored for staged blocks before (reindexed or re-included orphaned blocks)
+	// so they are rewritten from the fetched blocks
+	stored, err := queryBlockHashes(ctx, tx, `
+		SELECT b.hash FROM blocks b JOIN blocks_staging s ON s.hash = b.hash
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to check stored staged blocks: %w", err)
+	}
+	if len(stored) > 0 {
+		if err := clearBlockContents(ctx, tx, stored); err != nil {
+			return fmt.Errorf("failed to clear stored contents of staged blocks: %w", err)
+		}
+	}
+
+	headerColumns := strings.Join(blockHeaderColumns, ", ")
+	_, err = tx.Exec(ctx, `
+		INSERT INTO blocks (height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, canonical, `+headerColumns+`)
+		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, TRUE, `+headerColumns+`
+		FROM blocks_staging
+		ON CONFLICT (hash) DO UPDATE SET
+			height = EXCLUDED.height,
+			parent_hash = EXCLUDED.parent_hash,
+			miner = EXCLUDED.miner,
+			gas_used = EXCLUDED.gas_used,
+			gas_limit = EXCLUDED.gas_limit,
+			timestamp = EXCLUDED.timestamp,
+			tx_count = EXCLUDED.tx_count,
+			canonical = EXCLUDED.canonical,
+			`+blockHeaderUpdateSQL+`,
+			updated_at = NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged blocks: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (hash, block_hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei,
+		                          gas_used, gas_price, nonce, success, contract_address,
+		                          tx_type, max_fee_per_gas, max_priority_fee_per_gas, effective_gas_price, input, access_list,
+		                          max_fee_per_blob_gas, blob_versioned_hashes, blob_gas_used, blob_gas_price)
+		SELECT hash, block_hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei,
+		       gas_used, gas_price, nonce, success, contract_address,
+		       tx_type, max_fee_per_gas, max_priority_fee_per_gas, effective_gas_price, input, access_list,
+		       max_fee_per_blob_gas, blob_versioned_hashes, blob_gas_used, blob_gas_price
+		FROM transactions_staging
+		ON CONFLICT (hash, block_hash) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged transactions: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO logs (tx_hash, block_hash, log_index, address, topic0, topic1, topic2, topic3, data)
+		SELECT tx_hash, block_hash, log_index, address, topic0, topic1, topic2, topic3, data
+		FROM logs_staging
+		ON CONFLICT (tx_hash, block_hash, log_index) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged logs: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, tokenLedger.newTransfersSQL(`
+		INSERT INTO token_transfers (tx_hash, block_hash, log_index, block_height, token_address, from_addr, to_addr, amount)
+		SELECT tx_hash, block_hash, log_index, block_height, token_address, from_addr, to_addr, amount
+		FROM token_transfers_staging
+		ON CONFLICT (tx_hash, block_hash, log_index) DO NOTHING
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to merge staged token transfers: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, nftLedger.newTransfersSQL(`
+		INSERT INTO nft_transfers (tx_hash, block_hash, log_index, batch_index, block_height, contract_address, token_id,
+		                           standard, from_addr, to_addr, amount)
+		SELECT tx_hash, block_hash, log_index, batch_index, block_height, contract_address, token_id,
+		       standard, from_addr, to_addr, amount
+		FROM nft_transfers_staging
+		ON CONFLICT (tx_hash, block_hash, log_index, batch_index) DO NOTHING
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to merge staged NFT transfers: %w", err)
+	}
+
+	// Queue token and NFT contracts for metadata resolution
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tokens (address)
+		SELECT token_address FROM token_transfers_staging
+		UNION
+		SELECT contract_address FROM nft_transfers_staging
+		ORDER BY 1
+		ON CONFLICT (address) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to queue staged token contracts: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO internal_transactions (tx_hash, block_hash, block_height, trace_address, call_type, from_addr, to_addr,
+		                                   value_wei, gas, gas_used, depth, error)
+		SELECT tx_hash, block_hash, block_height, trace_address, call_type, from_addr, to_addr,
+		       value_wei, gas, gas_used, depth, error
+		FROM internal_transactions_staging
+		ON CONFLICT (tx_hash, block_hash, trace_address) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged internal transactions: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO withdrawals (block_hash, block_height, withdrawal_index, validator_index, address, amount_gwei)
+		SELECT block_hash, block_height, withdrawal_index, validator_index, address, amount_gwei
+		FROM withdrawals_staging
+		ON CONFLICT (block_hash, withdrawal_index) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to merge staged withdrawals: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit batch of %d blocks: %w", len(blocks), err)
+	}
+
+	slog.Debug("inserted block batch",
+		slog.Int("blocks", len(rows.blocks)),
+		slog.Int("transactions", len(rows.txs)),
+		slog.Int("logs", len(rows.logs)),
+		slog.Int("token_transfers", len(rows.tokenTransfers)),
+		slog.Int("nft_transfers", len(rows.nftTransfers)),
+		slog.Int("internal_transactions", len(rows.internalTxs)),
+		slog.Int("withdrawals", len(rows.withdrawals)),
+		slog.Uint64("first_height", blocks[0].Height),
+		slog.Uint64("last_height", blocks[len(blocks)-1].Height))
+
+	return nil
+}
+
+// buildCopyRows flattens domain blocks into COPY rows for the staging tables
+// If a height appears more than once, only the last block for it is kept, since a height
+// has a single canonical block
+func buildCopyRows(blocks []*index.Block) (*copyRows, error) {
+	lastByHeight := make(map[uint64]int, len(blocks))
+	for i, block := range blocks {
+		if block == nil {
+			return nil, fmt.Errorf("nil block in batch")
+		}
+		lastByHeight[block.Height] = i
+	}
+
+	rows := &copyRows{blocks: make([][]any, 0, len(lastByHeight))}
+	for i, block := range blocks {
+		if lastByHeight[block.Height] != i {
+			continue
+		}
+
+		rows.blocks = append(rows.blocks, append([]any{
+			int64(block.Height), block.Hash, block.ParentHash, block.Miner,
+			numericFromUint64(block.GasUsed), numericFromUint64(block.GasLimit),
+			int64(block.Timestamp), int32(block.TxCount),
+		}, blockHeaderValues(block)...))
+
+		for _, w := range block.Withdrawals {
+			rows.withdrawals = append(rows.withdrawals, []any{
+				block.Hash, int64(block.Height), int64(w.Index), int64(w.ValidatorIndex), w.Address,
+				numericFromUint64(w.AmountGwei),
+			})
+		}
+
+		for _, txn := range block.Transactions {
+			valueWei, err := numericFromString(txn.ValueWei)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value_wei for tx %x: %w", txn.Hash, err)
+			}
+
+			accessList, err := accessListJSON(txn.AccessList)
+			if err != nil {
+				return nil, fmt.Errorf("invalid access list for tx %x: %w", txn.Hash, err)
+			}
+
+			rows.txs = append(rows.txs, []any{
+				txn.Hash, block.Hash, int64(block.Height), int32(txn.TxIndex), txn.FromAddr, txn.ToAddr,
+				valueWei, numericFromBig(txFeeWei(txn)),
+				numericFromUint64(txn.GasUsed), numericFromBig(txn.GasPrice),
+				int64(txn.Nonce), txn.Success, txn.ContractAddress,
+				int16(txn.Type), numericFromBig(txn.MaxFeePerGas), numericFromBig(txn.MaxPriorityFeePerGas),
+				numericFromBig(txn.EffectiveGasPrice), txn.Input, accessList,
+				numericFromBig(txn.MaxFeePerBlobGas), txn.BlobHashes, blobGasUsed(txn), numericFromBig(txn.BlobGasPrice),
+			})
+
+			for _, log := range txn.Logs {
+				rows.logs = append(rows.logs, []any{
+					txn.Hash, block.Hash, int32(log.LogIndex), log.Address,
+					log.Topics[0], log.Topics[1], log.Topics[2], log.Topics[3], log.Data,
+				})
+			}
+
+			for _, transfer := range decodeTokenTransfers(txn.Logs) {
+				rows.tokenTransfers = append(rows.tokenTransfers, []any{
+					txn.Hash, block.Hash, int32(transfer.LogIndex), int64(block.Height),
+					transfer.Token, transfer.From, transfer.To, numericFromBig(transfer.Amount),
+				})
+			}
+
+			for _, transfer := range decodeNFTTransfers(txn.Logs) {
+				rows.nftTransfers = append(rows.nftTransfers, append([]any{
+					txn.Hash, block.Hash,
+				}, nftTransferValues(transfer, block.Height)...))
+			}
+
+			for _, call := range txn.InternalTxs {
+				rows.internalTxs = append(rows.internalTxs, append([]any{
+					txn.Hash, block.Hash, int64(block.Height),
+				}, internalTxValues(call)...))
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// internalTxValues returns the values of an internal transaction row after its tx_hash, block_hash and
+// block_height: trace_address, call_type, from_addr, to_addr, value_wei, gas, gas_used, depth and error
+func internalTxValues(call index.InternalTransaction) []any {
+	traceAddress := make([]int32, len(call.TraceAddress))
+	for i, position := range call.TraceAddress {
+		traceAddress[i] = int32(position)
+	}
+
+	valueWei := call.ValueWei
+	if valueWei == nil {
+		valueWei = new(big.Int)
+	}
+
+	var callError *string
+	if call.Error != "" {
+		callError = &call.Error
+	}
+
+	return []any{
+		traceAddress, call.Type, call.FromAddr, call.ToAddr, numericFromBig(valueWei),
+		numericFromUint64(call.Gas), numericFromUint64(call.GasUsed), int32(call.Depth), callError,
+	}
+}
+
+// blockHeaderValues returns the values of blockHeaderColumns for a block
+// Header columns that every block has are NOT NULL, so missing values are written as zero values
+func blockHeaderValues(block *index.Block) []any {
+	difficulty := block.Difficulty
+	if difficulty == nil {
+		difficulty = new(big.Int)
+	}
+	return []any{
+		numericFromBig(block.BaseFeePerGas), numericFromBig(difficulty), nonNilBytes(block.Nonce),
+		nonNilBytes(block.ExtraData), nonNilBytes(block.StateRoot), nonNilBytes(block.TransactionsRoot),
+		nonNilBytes(block.ReceiptsRoot), nonNilBytes(block.LogsBloom), nonNilBytes(block.MixHash),
+		nonNilBytes(block.UncleHash), int64(block.Size), block.WithdrawalsRoot,
+		numericFromUint64Ptr(block.BlobGasUsed), numericFromUint64Ptr(block.ExcessBlobGas), block.ParentBeaconRoot,
+	}
+}
+
+// nonNilBytes returns b, or an empty slice for nil so it is written as an empty value instead of NULL
+func nonNilBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+// numericFromUint64 converts a uint64 to a NUMERIC value without overflow
+func numericFromUint64(v uint64) pgtype.Numeric {
+	return pgtype.Numeric{Int: new(big.Int).SetUint64(v), Valid: true}
+}
+
+// numericFromUint64Ptr converts an optional uint64 to a NUMERIC value (NULL for nil)
+func numericFromUint64Ptr(v *uint64) pgtype.Numeric {
+	if v == nil {
+		return pgtype.Numeric{}
+	}
+	return numericFromUint64(*v)
+}
+
+// numericFromBig converts a big integer to a NUMERIC value (NULL for nil)
+func numericFromBig(v *big.Int) pgtype.Numeric {
+	if v == nil {
+		return pgtype.Numeric{}
+	}
+	return pgtype.Numeric{Int: new(big.Int).Set(v), Valid: true}
+}
+
+// txFeeWei returns the execution fee of a transaction: gas_used * gas_price
+// Blob gas is paid on top (blob_gas_used * blob_gas_price) and is not included
+func txFeeWei(txn index.Transaction) *big.Int {
+	gasPrice := txn.GasPrice
+	if gasPrice == nil {
+		gasPrice = new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(txn.GasUsed), gasPrice)
+}
+
+// blobGasUsed returns the receipt's blob gas as NUMERIC (NULL unless a blob receipt was ingested)
+func blobGasUsed(txn index.Transaction) pgtype.Numeric {
+	if txn.BlobGasUsed == 0 {
+		return pgtype.Numeric{}
+	}
+	return numericFromUint64(txn.BlobGasUsed)
+}
+
+// accessListJSON encodes an access list for the access_list JSONB column (NULL for nil)
+func accessListJSON(accessList types.AccessList) ([]byte, error) {
+	if accessList == nil {
+		return nil, nil
+	}
+	entries := make([]AccessListEntry, 0, len(accessList))
+	for _, tuple := range accessList {
+		entry := AccessListEntry{
+			Address:     tuple.Address.Hex(),
+			StorageKeys: make([]string, 0, len(tuple.StorageKeys)),
+		}
+		for _, key := range tuple.StorageKeys {
+			entry.StorageKeys = append(entry.StorageKeys, key.Hex())
+		}
+		entries = append(entries, entry)
+	}
+	return json.Marshal(entries)
+}
+
+// numericFromString converts a base-10 integer string (e.g. wei amounts) to a NUMERIC value
+func numericFromString(s string) (pgtype.Numeric, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return pgtype.Numeric{}, fmt.Errorf("not a base-10 integer: %q", s)
+	}
+	return pgtype.Numeric{Int: v, Valid: true}, nil
+}
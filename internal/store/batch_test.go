@@ -0,0 +1,285 @@
+package store
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCopyRows(t *testing.T) {
+	toAddr := []byte{0x02}
+	blocks := []*index.Block{
+		{
+			Height:  100,
+			Hash:    []byte{0xaa},
+			TxCount: 2,
+			Transactions: []index.Transaction{
+				{
+					Hash:     []byte{0x01},
+					TxIndex:  0,
+					ToAddr:   &toAddr,
+					ValueWei: "1000000000000000000000", // larger than uint64
+					GasUsed:  21000,
+					GasPrice: big.NewInt(2),
+					Success:  true,
+					Logs: []index.Log{
+						{LogIndex: 0, Address: []byte{0x03}, Topics: [4][]byte{{0x04}}, Data: []byte{}},
+						{LogIndex: 1, Address: []byte{0x03}, Data: []byte{0x05}},
+					},
+				},
+				{Hash: []byte{0x06}, TxIndex: 1, ValueWei: "0"},
+			},
+		},
+		{Height: 101, Hash: []byte{0xbb}},
+	}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	blockRows, txRows, logRows := rows.blocks, rows.txs, rows.logs
+	require.Len(t, blockRows, 2)
+	require.Len(t, txRows, 2)
+	require.Len(t, logRows, 2)
+	assert.Empty(t, rows.internalTxs, "no internal transactions without call tracing")
+
+	assert.Equal(t, int64(100), blockRows[0][0])
+	assert.Equal(t, int64(101), blockRows[1][0])
+
+	// Transaction rows carry the block hash, block height and NUMERIC amounts
+	assert.Equal(t, []byte{0xaa}, txRows[0][1])
+	assert.Equal(t, int64(100), txRows[0][2])
+	value := txRows[0][6].(pgtype.Numeric)
+	assert.Equal(t, "1000000000000000000000", value.Int.String())
+	fee := txRows[0][7].(pgtype.Numeric)
+	assert.Equal(t, big.NewInt(42000), fee.Int)
+
+	// Missing topics stay nil so they are copied as NULL
+	assert.Equal(t, []byte{0x04}, logRows[0][4])
+	assert.Nil(t, logRows[1][4])
+}
+
+func TestBuildCopyRows_DuplicateHeight(t *testing.T) {
+	blocks := []*index.Block{
+		{Height: 100, Hash: []byte{0xaa}, Transactions: []index.Transaction{{Hash: []byte{0x01}, ValueWei: "0"}}},
+		{Height: 101, Hash: []byte{0xbb}},
+		{Height: 100, Hash: []byte{0xcc}},
+	}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+
+	// Only the last block at height 100 is kept, with none of the replaced block's transactions
+	require.Len(t, rows.blocks, 2)
+	assert.Equal(t, []byte{0xbb}, rows.blocks[0][1])
+	assert.Equal(t, []byte{0xcc}, rows.blocks[1][1])
+	assert.Empty(t, rows.txs)
+}
+
+func TestBuildCopyRows_Errors(t *testing.T) {
+	_, err := buildCopyRows([]*index.Block{nil})
+	assert.Error(t, err)
+
+	_, err = buildCopyRows([]*index.Block{{
+		Height:       1,
+		Transactions: []index.Transaction{{Hash: []byte{0x01}, ValueWei: "not-a-number"}},
+	}})
+	assert.Error(t, err)
+}
+
+func TestBuildCopyRows_TypedTransactions(t *testing.T) {
+	blocks := []*index.Block{{
+		Height: 100,
+		Hash:   []byte{0xaa},
+		Transactions: []index.Transaction{
+			{Hash: []byte{0x01}, ValueWei: "0", Type: types.LegacyTxType, GasPrice: big.NewInt(1), Input: []byte{}},
+			{
+				Hash:                 []byte{0x02},
+				ValueWei:             "0",
+				Type:                 types.BlobTxType,
+				GasUsed:              21000,
+				GasPrice:             big.NewInt(10),
+				MaxFeePerGas:         big.NewInt(20),
+				MaxPriorityFeePerGas: big.NewInt(2),
+				EffectiveGasPrice:    big.NewInt(10),
+				Input:                []byte{0xab},
+				AccessList: types.AccessList{{
+					Address:     common.BytesToAddress([]byte{0x03}),
+					StorageKeys: []common.Hash{common.BytesToHash([]byte{0x04})},
+				}},
+				MaxFeePerBlobGas: big.NewInt(30),
+				BlobHashes:       [][]byte{{0x01, 0x05}},
+				BlobGasUsed:      131072,
+				BlobGasPrice:     big.NewInt(3),
+			},
+		},
+	}}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	txRows := rows.txs
+	require.Len(t, txRows, 2)
+
+	// Columns after contract_address: tx_type, fee caps, effective price, input, access list, blob fields
+	legacy := txRows[0][13:]
+	assert.Equal(t, int16(0), legacy[0])
+	assert.False(t, legacy[1].(pgtype.Numeric).Valid, "no fee cap for legacy transactions")
+	assert.Nil(t, legacy[5], "no access list for legacy transactions")
+	assert.False(t, legacy[8].(pgtype.Numeric).Valid, "no blob gas without a blob receipt")
+
+	blob := txRows[1][13:]
+	assert.Equal(t, int16(3), blob[0])
+	assert.Equal(t, big.NewInt(20), blob[1].(pgtype.Numeric).Int)
+	assert.Equal(t, big.NewInt(2), blob[2].(pgtype.Numeric).Int)
+	assert.Equal(t, []byte{0xab}, blob[4])
+	assert.JSONEq(t, `[{"address":"0x0000000000000000000000000000000000000003",
+		"storage_keys":["0x0000000000000000000000000000000000000000000000000000000000000004"]}]`, string(blob[5].([]byte)))
+	assert.Equal(t, big.NewInt(30), blob[6].(pgtype.Numeric).Int)
+	assert.Equal(t, [][]byte{{0x01, 0x05}}, blob[7])
+	assert.Equal(t, big.NewInt(131072), blob[8].(pgtype.Numeric).Int)
+	assert.Equal(t, big.NewInt(210000), txRows[1][7].(pgtype.Numeric).Int, "fee_wei is gas_used * gas_price")
+}
+
+func TestBuildCopyRows_HeaderAndWithdrawals(t *testing.T) {
+	blobGasUsed := uint64(131072)
+	blocks := []*index.Block{
+		{
+			Height:           200,
+			Hash:             []byte{0xaa},
+			GasLimit:         30000000,
+			BaseFeePerGas:    big.NewInt(7),
+			Difficulty:       big.NewInt(0),
+			Nonce:            make([]byte, 8),
+			StateRoot:        []byte{0x01},
+			WithdrawalsRoot:  []byte{0x02},
+			BlobGasUsed:      &blobGasUsed,
+			ParentBeaconRoot: []byte{0x03},
+			Withdrawals: []index.Withdrawal{
+				{Index: 10, ValidatorIndex: 500, Address: []byte{0x04}, AmountGwei: 32000000000},
+				{Index: 11, ValidatorIndex: 501, Address: []byte{0x05}, AmountGwei: 1},
+			},
+		},
+		{Height: 100, Hash: []byte{0xbb}}, // pre-London header without fork fields
+	}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	blockRows, withdrawalRows := rows.blocks, rows.withdrawals
+	require.Len(t, blockRows, 2)
+	require.Len(t, withdrawalRows, 2)
+
+	assert.Equal(t, big.NewInt(30000000), blockRows[0][5].(pgtype.Numeric).Int, "gas_limit")
+
+	// Columns after tx_count follow blockHeaderColumns
+	header := blockRows[0][8:]
+	require.Len(t, header, len(blockHeaderColumns))
+	assert.Equal(t, big.NewInt(7), header[0].(pgtype.Numeric).Int)
+	assert.Equal(t, []byte{0x01}, header[4])
+	assert.Equal(t, []byte{0x02}, header[11])
+	assert.Equal(t, big.NewInt(131072), header[12].(pgtype.Numeric).Int)
+	assert.False(t, header[13].(pgtype.Numeric).Valid, "no excess blob gas")
+
+	// NOT NULL columns get zero values, fork fields stay NULL
+	legacy := blockRows[1][8:]
+	assert.False(t, legacy[0].(pgtype.Numeric).Valid, "no base fee before London")
+	assert.Equal(t, big.NewInt(0), legacy[1].(pgtype.Numeric).Int)
+	assert.Equal(t, []byte{}, legacy[2])
+	assert.Nil(t, legacy[11])
+	assert.Nil(t, legacy[14])
+
+	// Withdrawal rows carry the block hash and height
+	assert.Equal(t, []any{[]byte{0xaa}, int64(200), int64(10), int64(500), []byte{0x04},
+		numericFromUint64(32000000000)}, withdrawalRows[0])
+}
+
+func TestBuildCopyRows_InternalTransactions(t *testing.T) {
+	toAddr := []byte{0x03}
+	blocks := []*index.Block{{
+		Height: 100,
+		Hash:   []byte{0xaa},
+		Transactions: []index.Transaction{{
+			Hash:     []byte{0x01},
+			ValueWei: "0",
+			InternalTxs: []index.InternalTransaction{
+				{TraceAddress: []int{0}, Depth: 1, Type: "CALL", FromAddr: []byte{0x02}, ToAddr: toAddr,
+					ValueWei: big.NewInt(5), Gas: 3000, GasUsed: 2100},
+				{TraceAddress: []int{0, 1}, Depth: 2, Type: "CREATE", FromAddr: []byte{0x03}, Error: "out of gas"},
+			},
+		}},
+	}}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	require.Len(t, rows.internalTxs, 2)
+
+	assert.Equal(t, []any{[]byte{0x01}, []byte{0xaa}, int64(100), []int32{0}, "CALL", []byte{0x02}, toAddr,
+		numericFromUint64(5), numericFromUint64(3000), numericFromUint64(2100), int32(1), (*string)(nil)},
+		rows.internalTxs[0])
+
+	failed := rows.internalTxs[1]
+	assert.Equal(t, []int32{0, 1}, failed[3])
+	assert.Nil(t, failed[6], "failed creation has no address")
+	assert.Equal(t, big.NewInt(0), failed[7].(pgtype.Numeric).Int, "missing value is written as zero")
+	require.NotNil(t, failed[11])
+	assert.Equal(t, "out of gas", *failed[11].(*string))
+}
+
+func TestBuildCopyRows_TokenTransfers(t *testing.T) {
+	transfer := index.Log{
+		LogIndex: 7,
+		Address:  []byte{0x0a},
+		Topics: [4][]byte{transferEventTopic, common.BytesToHash([]byte{0x01}).Bytes(),
+			common.BytesToHash([]byte{0x02}).Bytes()},
+		Data: common.BigToHash(big.NewInt(500)).Bytes(),
+	}
+	blocks := []*index.Block{{
+		Height: 100,
+		Hash:   []byte{0xaa},
+		Transactions: []index.Transaction{{
+			Hash:     []byte{0x01},
+			ValueWei: "0",
+			Logs:     []index.Log{transfer, {LogIndex: 8, Address: []byte{0x0a}, Data: []byte{}}},
+		}},
+	}}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	require.Len(t, rows.logs, 2, "every log is stored")
+	require.Len(t, rows.tokenTransfers, 1, "only Transfer events are decoded")
+
+	assert.Equal(t, []any{[]byte{0x01}, []byte{0xaa}, int32(7), int64(100), []byte{0x0a},
+		common.BytesToAddress([]byte{0x01}).Bytes(), common.BytesToAddress([]byte{0x02}).Bytes(),
+		numericFromUint64(500)}, rows.tokenTransfers[0])
+}
+
+func TestBuildCopyRows_NFTTransfers(t *testing.T) {
+	erc721 := index.Log{
+		LogIndex: 3,
+		Address:  []byte{0x0b},
+		Topics: [4][]byte{transferEventTopic, common.BytesToHash([]byte{0x01}).Bytes(),
+			common.BytesToHash([]byte{0x02}).Bytes(), common.BigToHash(big.NewInt(42)).Bytes()},
+		Data: []byte{},
+	}
+	blocks := []*index.Block{{
+		Height: 100,
+		Hash:   []byte{0xaa},
+		Transactions: []index.Transaction{{
+			Hash:     []byte{0x01},
+			ValueWei: "0",
+			Logs:     []index.Log{erc721},
+		}},
+	}}
+
+	rows, err := buildCopyRows(blocks)
+	require.NoError(t, err)
+	assert.Empty(t, rows.tokenTransfers, "ERC-721 transfers are not ERC-20 transfers")
+	require.Len(t, rows.nftTransfers, 1)
+
+	assert.Equal(t, []any{[]byte{0x01}, []byte{0xaa}, int32(3), int32(0), int64(100), []byte{0x0b},
+		numericFromUint64(42), standardERC721, common.BytesToAddress([]byte{0x01}).Bytes(),
+		common.BytesToAddress([]byte{0x02}).Bytes(), numericFromUint64(1)}, rows.nftTransfers[0])
+}
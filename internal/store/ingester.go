@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+)
+
+// ReceiptFetcher fetches transaction receipts from RPC (implemented by rpc.Client and rpc.Pool)
+type ReceiptFetcher interface {
+	GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error)
+	GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// BatchReceiptFetcher fetches many transaction receipts per JSON-RPC batch request
+// Used in transaction receipt mode when the fetcher supports it (rpc.Client and rpc.Pool do)
+type BatchReceiptFetcher interface {
+	GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error)
+}
+
+// TraceFetcher traces the calls of every transaction in a block (implemented by rpc.Client and rpc.Pool)
+// Requires the debug API (debug_traceBlockByHash) on the RPC node
+type TraceFetcher interface {
+	TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]rpc.TxCallTrace, error)
+}
+
+// BlockIngester implements index.BlockIngester
+// In basic mode it only parses the block; in receipt mode it also fetches receipts and fills in
+// gas used, effective gas price, blob gas, status, contract address and logs for every transaction.
+// With call tracing enabled it also records the internal transactions of every transaction.
+type BlockIngester struct {
+	fetcher ReceiptFetcher
+	tracer  TraceFetcher // nil unless call tracing is enabled
+	config  *index.IngestConfig
+}
+
+// NewBlockIngester creates a new block ingester
+// fetcher may be nil only when the config fetches neither receipts nor traces; with call tracing
+// enabled it must also implement TraceFetcher
+func NewBlockIngester(fetcher ReceiptFetcher, config *index.IngestConfig) (*BlockIngester, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if config.FetchReceipts() && fetcher == nil {
+		return nil, fmt.Errorf("fetcher cannot be nil in receipt mode %q", config.ReceiptMode)
+	}
+
+	bi := &BlockIngester{
+		fetcher: fetcher,
+		config:  config,
+	}
+
+	if config.TraceCalls {
+		tracer, ok := fetcher.(TraceFetcher)
+		if !ok {
+			return nil, fmt.Errorf("fetcher must support call tracing when trace mode is enabled")
+		}
+		bi.tracer = tracer
+	}
+
+	return bi, nil
+}
+
+// ParseBlock converts an RPC block to the domain model, fetching receipts and call traces if configured
+func (bi *BlockIngester) ParseBlock(ctx context.Context, rpcBlock *types.Block) (*index.Block, error) {
+	if rpcBlock == nil {
+		return nil, fmt.Errorf("rpcBlock cannot be nil")
+	}
+
+	block := ParseRPCBlock(rpcBlock)
+	if len(block.Transactions) == 0 {
+		return block, nil
+	}
+
+	if bi.config.FetchReceipts() {
+		receipts, err := bi.fetchReceipts(ctx, rpcBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch receipts for block %d: %w", block.Height, err)
+		}
+
+		if err := ApplyReceipts(block, receipts); err != nil {
+			return nil, fmt.Errorf("failed to apply receipts for block %d: %w", block.Height, err)
+		}
+	}
+
+	if bi.tracer != nil {
+		// Trace by hash: tracing by height could return another block's traces after a reorg
+		traces, err := bi.tracer.TraceBlockCalls(ctx, rpcBlock.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to trace block %d: %w", block.Height, err)
+		}
+
+		if err := ApplyTraces(block, traces); err != nil {
+			return nil, fmt.Errorf("failed to apply traces for block %d: %w", block.Height, err)
+		}
+	}
+
+	return block, nil
+}
+
+// fetchReceipts fetches the receipts of all transactions in the block using the configured mode
+func (bi *BlockIngester) fetchReceipts(ctx context.Context, rpcBlock *types.Block) ([]*types.Receipt, error) {
+	if bi.config.ReceiptMode == index.ReceiptModeBlock {
+		return bi.fetcher.GetBlockReceipts(ctx, rpcBlock.NumberU64())
+	}
+
+	if batchFetcher, ok := bi.fetcher.(BatchReceiptFetcher); ok {
+		txHashes := make([]common.Hash, 0, len(rpcBlock.Transactions()))
+		for _, tx := range rpcBlock.Transactions() {
+			txHashes = append(txHashes, tx.Hash())
+		}
+		return batchFetcher.GetTransactionReceipts(ctx, txHashes)
+	}
+
+	receipts := make([]*types.Receipt, 0, len(rpcBlock.Transactions()))
+	for _, tx := range rpcBlock.Transactions() {
+		receipt, err := bi.fetcher.GetTransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch receipt for tx %s: %w", tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+// ApplyReceipts fills in receipt data (gas used, effective gas price, blob gas, status, contract address, logs)
+// for every transaction of the block. Receipts are matched by transaction hash, and every
+// transaction must have a receipt
+func ApplyReceipts(block *index.Block, receipts []*types.Receipt) error {
+	if len(receipts) != len(block.Transactions) {
+		return fmt.Errorf("receipt count (%d) does not match transaction count (%d)",
+			len(receipts), len(block.Transactions))
+	}
+
+	byHash := make(map[common.Hash]*types.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		if receipt == nil {
+			return fmt.Errorf("nil receipt in block %d", block.Height)
+		}
+		byHash[receipt.TxHash] = receipt
+	}
+
+	for i := range block.Transactions {
+		txn := &block.Transactions[i]
+		receipt, ok := byHash[common.BytesToHash(txn.Hash)]
+		if !ok {
+			return fmt.Errorf("missing receipt for tx %x", txn.Hash)
+		}
+
+		txn.GasUsed = receipt.GasUsed
+		if receipt.EffectiveGasPrice != nil {
+			txn.GasPrice = new(big.Int).Set(receipt.EffectiveGasPrice)
+			txn.EffectiveGasPrice = new(big.Int).Set(receipt.EffectiveGasPrice)
+		}
+		if txn.Type == types.BlobTxType {
+			txn.BlobGasUsed = receipt.BlobGasUsed
+			if receipt.BlobGasPrice != nil {
+				txn.BlobGasPrice = new(big.Int).Set(receipt.BlobGasPrice)
+			}
+		}
+		txn.Success = receipt.Status == types.ReceiptStatusSuccessful
+
+		if receipt.ContractAddress != (common.Address{}) {
+			contractAddr := receipt.ContractAddress.Bytes()
+			txn.ContractAddress = &contractAddr
+		}
+
+		txn.Logs = parseLogs(receipt.Logs)
+	}
+
+	return nil
+}
+
+// ApplyTraces fills in the internal transactions of every transaction of the block from its call trace
+// Traces are matched by transaction hash, or by position for nodes that do not report the hash,
+// and every transaction must have a trace
+func ApplyTraces(block *index.Block, traces []rpc.TxCallTrace) error {
+	if len(traces) != len(block.Transactions) {
+		return fmt.Errorf("trace count (%d) does not match transaction count (%d)",
+			len(traces), len(block.Transactions))
+	}
+
+	byHash := make(map[common.Hash]int, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		byHash[common.BytesToHash(txn.Hash)] = i
+	}
+
+	for i, trace := range traces {
+		txIndex := i
+		if trace.TxHash != (common.Hash{}) {
+			var ok bool
+			if txIndex, ok = byHash[trace.TxHash]; !ok {
+				return fmt.Errorf("trace for unknown tx %s", trace.TxHash.Hex())
+			}
+		}
+
+		txn := &block.Transactions[txIndex]
+		if trace.Error != "" {
+			return fmt.Errorf("tracing tx %x failed: %s", txn.Hash, trace.Error)
+		}
+		if trace.Result == nil {
+			return fmt.Errorf("missing trace for tx %x", txn.Hash)
+		}
+
+		txn.InternalTxs = flattenCalls(trace.Result, nil, []index.InternalTransaction{})
+	}
+
+	return nil
+}
+
+// flattenCalls appends the calls made by frame, and recursively the calls they made, in execution order
+// The transaction's own top-level call (the root frame) is not an internal transaction
+func flattenCalls(frame *rpc.CallFrame, path []int, calls []index.InternalTransaction) []index.InternalTransaction {
+	for i := range frame.Calls {
+		call := &frame.Calls[i]
+		traceAddress := append(append(make([]int, 0, len(path)+1), path...), i)
+
+		internal := index.InternalTransaction{
+			TraceAddress: traceAddress,
+			Depth:        len(traceAddress),
+			Type:         call.Type,
+			FromAddr:     call.From.Bytes(),
+			ValueWei:     new(big.Int),
+			Gas:          uint64(call.Gas),
+			GasUsed:      uint64(call.GasUsed),
+			Error:        call.Error,
+		}
+		if call.To != nil {
+			internal.ToAddr = call.To.Bytes()
+		}
+		if call.Value != nil {
+			internal.ValueWei.Set(call.Value.ToInt())
+		}
+
+		calls = append(calls, internal)
+		calls = flattenCalls(call, traceAddress, calls)
+	}
+	return calls
+}
+
+// parseLogs converts receipt logs to the index.Log domain model
+// LogIndex is the block-wide log index reported by the node
+func parseLogs(rpcLogs []*types.Log) []index.Log {
+	logs := make([]index.Log, 0, len(rpcLogs))
+	for _, rpcLog := range rpcLogs {
+		log := index.Log{
+			LogIndex: uint64(rpcLog.Index),
+			Address:  rpcLog.Address.Bytes(),
+			Data:     append([]byte{}, rpcLog.Data...), // logs.data is NOT NULL
+		}
+		for i, topic := range rpcLog.Topics {
+			if i >= len(log.Topics) {
+				break // Only 4 topics are valid in the EVM
+			}
+			log.Topics[i] = topic.Bytes()
+		}
+		logs = append(logs, log)
+	}
+	return logs
+}
@@ -0,0 +1,398 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/hieutt50/go-blockchain-explorer/internal/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReceiptFetcher returns canned receipts keyed by transaction hash
+type mockReceiptFetcher struct {
+	receipts        map[common.Hash]*types.Receipt
+	blockCalls      int
+	txCalls         int
+	blockReceiptErr error
+}
+
+func (m *mockReceiptFetcher) GetBlockReceipts(ctx context.Context, height uint64) ([]*types.Receipt, error) {
+	m.blockCalls++
+	if m.blockReceiptErr != nil {
+		return nil, m.blockReceiptErr
+	}
+	receipts := make([]*types.Receipt, 0, len(m.receipts))
+	for _, receipt := range m.receipts {
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+func (m *mockReceiptFetcher) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.txCalls++
+	receipt, ok := m.receipts[txHash]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	return receipt, nil
+}
+
+// generateBlockWithTxs creates a test block with count legacy transactions
+func generateBlockWithTxs(height uint64, count int) *types.Block {
+	txs := make([]*types.Transaction, 0, count)
+	for i := 0; i < count; i++ {
+		to := common.BytesToAddress([]byte{byte(i + 1)})
+		txs = append(txs, types.NewTransaction(uint64(i), to, big.NewInt(1000), 100000, big.NewInt(30_000_000_000), nil))
+	}
+	header := &types.Header{Number: big.NewInt(int64(height)), GasLimit: 30_000_000}
+	return types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs})
+}
+
+// receiptsFor builds one receipt per transaction with a single log each
+func receiptsFor(block *types.Block) map[common.Hash]*types.Receipt {
+	receipts := make(map[common.Hash]*types.Receipt)
+	for i, tx := range block.Transactions() {
+		receipts[tx.Hash()] = &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			TxHash:            tx.Hash(),
+			GasUsed:           21000,
+			EffectiveGasPrice: big.NewInt(12_000_000_000),
+			Logs: []*types.Log{{
+				Address: common.BytesToAddress([]byte{0xaa}),
+				Topics:  []common.Hash{common.BytesToHash([]byte{0x01}), common.BytesToHash([]byte{0x02})},
+				Data:    []byte{0xde, 0xad},
+				Index:   uint(i),
+			}},
+		}
+	}
+	return receipts
+}
+
+func TestBlockIngester_BasicMode(t *testing.T) {
+	fetcher := &mockReceiptFetcher{}
+	ingester, err := NewBlockIngester(fetcher, index.DefaultIngestConfig())
+	require.NoError(t, err)
+
+	block, err := ingester.ParseBlock(context.Background(), generateBlockWithTxs(10, 2))
+	require.NoError(t, err)
+
+	assert.Len(t, block.Transactions, 2)
+	assert.Equal(t, uint64(100000), block.Transactions[0].GasUsed, "basic mode uses gas limit")
+	assert.True(t, block.Transactions[0].Success, "basic mode assumes success")
+	assert.Empty(t, block.Transactions[0].Logs)
+	assert.Equal(t, 0, fetcher.blockCalls+fetcher.txCalls, "basic mode must not fetch receipts")
+}
+
+func TestBlockIngester_BlockReceiptMode(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 3)
+	fetcher := &mockReceiptFetcher{receipts: receiptsFor(rpcBlock)}
+	failed := fetcher.receipts[rpcBlock.Transactions()[1].Hash()]
+	failed.Status = types.ReceiptStatusFailed
+
+	ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeBlock})
+	require.NoError(t, err)
+
+	block, err := ingester.ParseBlock(context.Background(), rpcBlock)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fetcher.blockCalls)
+	assert.Equal(t, 0, fetcher.txCalls)
+	require.Len(t, block.Transactions, 3)
+	assert.Equal(t, uint64(21000), block.Transactions[0].GasUsed)
+	assert.Equal(t, big.NewInt(12_000_000_000), block.Transactions[0].GasPrice)
+	assert.Equal(t, big.NewInt(12_000_000_000), block.Transactions[0].EffectiveGasPrice)
+	assert.True(t, block.Transactions[0].Success)
+	assert.False(t, block.Transactions[1].Success)
+	require.Len(t, block.Transactions[2].Logs, 1)
+
+	log := block.Transactions[2].Logs[0]
+	assert.Equal(t, uint64(2), log.LogIndex)
+	assert.Equal(t, []byte{0xde, 0xad}, log.Data)
+	assert.NotNil(t, log.Topics[1])
+	assert.Nil(t, log.Topics[2], "missing topics stay nil (stored as NULL)")
+}
+
+func TestBlockIngester_TransactionReceiptMode(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 2)
+	fetcher := &mockReceiptFetcher{receipts: receiptsFor(rpcBlock)}
+	contract := common.BytesToAddress([]byte{0xcc})
+	fetcher.receipts[rpcBlock.Transactions()[0].Hash()].ContractAddress = contract
+
+	ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeTransaction})
+	require.NoError(t, err)
+
+	block, err := ingester.ParseBlock(context.Background(), rpcBlock)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fetcher.txCalls)
+	require.NotNil(t, block.Transactions[0].ContractAddress)
+	assert.Equal(t, contract.Bytes(), *block.Transactions[0].ContractAddress)
+	assert.Nil(t, block.Transactions[1].ContractAddress)
+}
+
+// mockBatchReceiptFetcher adds batch receipt fetching to mockReceiptFetcher
+type mockBatchReceiptFetcher struct {
+	*mockReceiptFetcher
+	batchCalls int
+}
+
+func (m *mockBatchReceiptFetcher) GetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]*types.Receipt, error) {
+	m.batchCalls++
+	receipts := make([]*types.Receipt, len(txHashes))
+	for i, txHash := range txHashes {
+		receipts[i] = m.receipts[txHash]
+	}
+	return receipts, nil
+}
+
+func TestBlockIngester_TransactionReceiptMode_Batch(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 3)
+	fetcher := &mockBatchReceiptFetcher{mockReceiptFetcher: &mockReceiptFetcher{receipts: receiptsFor(rpcBlock)}}
+
+	ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeTransaction})
+	require.NoError(t, err)
+
+	block, err := ingester.ParseBlock(context.Background(), rpcBlock)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fetcher.batchCalls)
+	assert.Equal(t, 0, fetcher.txCalls)
+	for _, txn := range block.Transactions {
+		assert.Equal(t, uint64(21000), txn.GasUsed)
+	}
+}
+
+func TestParseRPCBlock_TypedTransactions(t *testing.T) {
+	to := common.BytesToAddress([]byte{0x01})
+	hugePrice, _ := new(big.Int).SetString("100000000000000000000", 10) // above uint64
+	accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{common.BytesToHash([]byte{0x02})}}}
+
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{To: &to, Gas: 21000, GasPrice: hugePrice, Data: []byte{0xab}}),
+		types.NewTx(&types.AccessListTx{ChainID: big.NewInt(1), To: &to, Gas: 30000, GasPrice: big.NewInt(7), AccessList: accessList}),
+		types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &to, Gas: 50000, GasFeeCap: big.NewInt(30), GasTipCap: big.NewInt(2)}),
+	}
+	header := &types.Header{Number: big.NewInt(10)}
+	block := ParseRPCBlock(types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs}))
+	require.Len(t, block.Transactions, 3)
+
+	legacy := block.Transactions[0]
+	assert.Equal(t, uint8(types.LegacyTxType), legacy.Type)
+	assert.Equal(t, hugePrice, legacy.GasPrice, "gas price must not overflow")
+	assert.Equal(t, []byte{0xab}, legacy.Input)
+	assert.Nil(t, legacy.AccessList)
+	assert.Nil(t, legacy.MaxFeePerGas)
+
+	accessListTx := block.Transactions[1]
+	assert.Equal(t, uint8(types.AccessListTxType), accessListTx.Type)
+	assert.Equal(t, accessList, accessListTx.AccessList)
+	assert.Equal(t, []byte{}, accessListTx.Input, "empty input is stored as empty, not NULL")
+	assert.Nil(t, accessListTx.MaxPriorityFeePerGas)
+
+	dynamicFee := block.Transactions[2]
+	assert.Equal(t, uint8(types.DynamicFeeTxType), dynamicFee.Type)
+	assert.Equal(t, big.NewInt(30), dynamicFee.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(2), dynamicFee.MaxPriorityFeePerGas)
+	assert.Equal(t, types.AccessList{}, dynamicFee.AccessList)
+	assert.Nil(t, dynamicFee.EffectiveGasPrice, "effective gas price needs a receipt")
+	assert.Nil(t, dynamicFee.MaxFeePerBlobGas)
+}
+
+func TestApplyReceipts_BlobGas(t *testing.T) {
+	block := &index.Block{Height: 10, Transactions: []index.Transaction{
+		{Hash: []byte{0x01}, Type: types.BlobTxType},
+		{Hash: []byte{0x02}, Type: types.DynamicFeeTxType},
+	}}
+	receipts := []*types.Receipt{
+		{TxHash: common.BytesToHash([]byte{0x01}), GasUsed: 21000, EffectiveGasPrice: big.NewInt(5),
+			BlobGasUsed: 131072, BlobGasPrice: big.NewInt(3)},
+		{TxHash: common.BytesToHash([]byte{0x02}), GasUsed: 21000, EffectiveGasPrice: big.NewInt(6)},
+	}
+
+	require.NoError(t, ApplyReceipts(block, receipts))
+	assert.Equal(t, uint64(131072), block.Transactions[0].BlobGasUsed)
+	assert.Equal(t, big.NewInt(3), block.Transactions[0].BlobGasPrice)
+	assert.Equal(t, big.NewInt(6), block.Transactions[1].EffectiveGasPrice)
+	assert.Zero(t, block.Transactions[1].BlobGasUsed)
+	assert.Nil(t, block.Transactions[1].BlobGasPrice)
+}
+
+func TestBlockIngester_ReceiptErrors(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 2)
+
+	t.Run("fetch error", func(t *testing.T) {
+		fetcher := &mockReceiptFetcher{blockReceiptErr: errors.New("method not found")}
+		ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeBlock})
+		require.NoError(t, err)
+
+		_, err = ingester.ParseBlock(context.Background(), rpcBlock)
+		assert.Error(t, err)
+	})
+
+	t.Run("receipt count mismatch", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		err := ApplyReceipts(block, []*types.Receipt{})
+		assert.Error(t, err)
+	})
+
+	t.Run("receipt for unknown transaction", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		err := ApplyReceipts(block, []*types.Receipt{
+			{TxHash: common.BytesToHash([]byte{0x01})},
+			{TxHash: common.BytesToHash([]byte{0x02})},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewBlockIngester_Validation(t *testing.T) {
+	_, err := NewBlockIngester(nil, nil)
+	assert.Error(t, err)
+
+	_, err = NewBlockIngester(nil, &index.IngestConfig{ReceiptMode: index.ReceiptModeBlock})
+	assert.Error(t, err, "receipt mode requires a fetcher")
+
+	_, err = NewBlockIngester(nil, index.DefaultIngestConfig())
+	assert.NoError(t, err, "basic mode does not need a fetcher")
+
+	traceConfig := &index.IngestConfig{ReceiptMode: index.ReceiptModeNone, TraceCalls: true}
+	_, err = NewBlockIngester(nil, traceConfig)
+	assert.Error(t, err, "trace mode requires a fetcher")
+
+	_, err = NewBlockIngester(&mockReceiptFetcher{}, traceConfig)
+	assert.Error(t, err, "trace mode requires a fetcher that can trace")
+
+	_, err = NewBlockIngester(&mockTraceFetcher{}, traceConfig)
+	assert.NoError(t, err)
+}
+
+// mockTraceFetcher returns canned call traces for every block
+type mockTraceFetcher struct {
+	mockReceiptFetcher
+	traces      []rpc.TxCallTrace
+	traceErr    error
+	traceCalls  int
+	tracedBlock common.Hash // Hash of the last traced block
+}
+
+func (m *mockTraceFetcher) TraceBlockCalls(ctx context.Context, blockHash common.Hash) ([]rpc.TxCallTrace, error) {
+	m.traceCalls++
+	m.tracedBlock = blockHash
+	if m.traceErr != nil {
+		return nil, m.traceErr
+	}
+	return m.traces, nil
+}
+
+func TestBlockIngester_TraceCalls(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 2)
+	txs := rpcBlock.Transactions()
+
+	contract := common.HexToAddress("0xc0ffee")
+	token := common.HexToAddress("0x70ce")
+	created := common.HexToAddress("0xbeef")
+	fetcher := &mockTraceFetcher{traces: []rpc.TxCallTrace{
+		// Second transaction first: traces are matched by hash
+		{TxHash: txs[1].Hash(), Result: &rpc.CallFrame{Type: "CALL", From: common.HexToAddress("0x01"), To: &contract}},
+		{TxHash: txs[0].Hash(), Result: &rpc.CallFrame{
+			Type: "CALL", From: common.HexToAddress("0x01"), To: &contract,
+			Calls: []rpc.CallFrame{
+				{Type: "DELEGATECALL", From: contract, To: &token, Gas: 5000, GasUsed: 1200,
+					Calls: []rpc.CallFrame{
+						{Type: "CALL", From: contract, To: &created, Value: (*hexutil.Big)(big.NewInt(7)), Error: "execution reverted"},
+					}},
+				{Type: "CREATE2", From: contract, Error: "out of gas"},
+			},
+		}},
+	}}
+
+	ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeNone, TraceCalls: true})
+	require.NoError(t, err)
+
+	block, err := ingester.ParseBlock(context.Background(), rpcBlock)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetcher.traceCalls)
+	assert.Equal(t, rpcBlock.Hash(), fetcher.tracedBlock, "the fetched block is traced by hash")
+	assert.Zero(t, fetcher.blockCalls, "receipts are not fetched in mode none")
+
+	internalTxs := block.Transactions[0].InternalTxs
+	require.Len(t, internalTxs, 3, "the root call is not an internal transaction")
+
+	assert.Equal(t, []int{0}, internalTxs[0].TraceAddress)
+	assert.Equal(t, 1, internalTxs[0].Depth)
+	assert.Equal(t, "DELEGATECALL", internalTxs[0].Type)
+	assert.Equal(t, contract.Bytes(), internalTxs[0].FromAddr)
+	assert.Equal(t, token.Bytes(), internalTxs[0].ToAddr)
+	assert.Equal(t, big.NewInt(0), internalTxs[0].ValueWei)
+	assert.Equal(t, uint64(5000), internalTxs[0].Gas)
+	assert.Equal(t, uint64(1200), internalTxs[0].GasUsed)
+
+	assert.Equal(t, []int{0, 0}, internalTxs[1].TraceAddress)
+	assert.Equal(t, 2, internalTxs[1].Depth)
+	assert.Equal(t, big.NewInt(7), internalTxs[1].ValueWei)
+	assert.Equal(t, "execution reverted", internalTxs[1].Error)
+
+	assert.Equal(t, []int{1}, internalTxs[2].TraceAddress)
+	assert.Nil(t, internalTxs[2].ToAddr, "failed creation has no address")
+	assert.Equal(t, "out of gas", internalTxs[2].Error)
+
+	assert.NotNil(t, block.Transactions[1].InternalTxs)
+	assert.Empty(t, block.Transactions[1].InternalTxs)
+}
+
+func TestApplyTraces_Errors(t *testing.T) {
+	rpcBlock := generateBlockWithTxs(10, 2)
+	root := &rpc.CallFrame{Type: "CALL"}
+
+	t.Run("trace error", func(t *testing.T) {
+		fetcher := &mockTraceFetcher{traceErr: errors.New("the method debug_traceBlockByHash does not exist/is not available")}
+		ingester, err := NewBlockIngester(fetcher, &index.IngestConfig{ReceiptMode: index.ReceiptModeNone, TraceCalls: true})
+		require.NoError(t, err)
+
+		_, err = ingester.ParseBlock(context.Background(), rpcBlock)
+		assert.Error(t, err)
+	})
+
+	t.Run("matched by position without hashes", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		err := ApplyTraces(block, []rpc.TxCallTrace{
+			{Result: root},
+			{Result: &rpc.CallFrame{Type: "CALL", Calls: []rpc.CallFrame{{Type: "STATICCALL"}}}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, block.Transactions[0].InternalTxs)
+		assert.Len(t, block.Transactions[1].InternalTxs, 1)
+	})
+
+	t.Run("trace count mismatch", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		assert.Error(t, ApplyTraces(block, []rpc.TxCallTrace{{Result: root}}))
+	})
+
+	t.Run("trace for unknown transaction", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		err := ApplyTraces(block, []rpc.TxCallTrace{
+			{TxHash: common.BytesToHash([]byte{0x01}), Result: root},
+			{TxHash: common.BytesToHash([]byte{0x02}), Result: root},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("transaction failed to trace", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		err := ApplyTraces(block, []rpc.TxCallTrace{{Result: root}, {Error: "execution timeout"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing result", func(t *testing.T) {
+		block := ParseRPCBlock(rpcBlock)
+		assert.Error(t, ApplyTraces(block, []rpc.TxCallTrace{{Result: root}, {}}))
+	})
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// transferLedger keeps running balances of decoded transfers (token_transfers, nft_transfers) in sync with
+// the canonical chain: a balance is the sum of the holder's transfers in canonical blocks. Transfers are
+// applied when inserted for a canonical block, reversed when their block is orphaned and re-applied when
+// it becomes canonical again. The transfers table has the asset columns, from_addr, to_addr, amount and
+// block_hash; the balances table has the asset columns, the holder column, the balance column and updated_at.
+type transferLedger struct {
+	transfers     string // Transfers table
+	balances      string // Balances table
+	assetColumns  string // Columns identifying the asset in both tables
+	holderColumn  string
+	balanceColumn string
+}
+
+var (
+	// tokenLedger keeps ERC-20 balances per (token, holder)
+	tokenLedger = transferLedger{
+		transfers:     "token_transfers",
+		balances:      "token_balances",
+		assetColumns:  "token_address",
+		holderColumn:  "holder",
+		balanceColumn: "balance",
+	}
+
+	// nftLedger keeps NFT ownership per (contract, token ID, owner); ERC-721 quantities are 0 or 1
+	nftLedger = transferLedger{
+		transfers:     "nft_transfers",
+		balances:      "nft_owners",
+		assetColumns:  "contract_address, token_id",
+		holderColumn:  "owner",
+		balanceColumn: "quantity",
+	}
+
+	// ledgers are adjusted whenever blocks join or leave the canonical chain
+	ledgers = []transferLedger{tokenLedger, nftLedger}
+)
+
+// applySQL adds the transfers selected by a "transfers" CTE (asset columns, from_addr, to_addr, amount) to the
+// balances: the amount is credited to the recipient and debited from the sender, so negated amounts reverse
+// transfers. The zero address (counterparty of mints and burns) is not tracked. Rows are written in key
+// order so concurrent writers lock them in the same order.
+func (l transferLedger) applySQL() string {
+	return `
+	INSERT INTO ` + l.balances + ` (` + l.assetColumns + `, ` + l.holderColumn + `, ` + l.balanceColumn + `)
+	SELECT ` + l.assetColumns + `, holder, SUM(delta)
+	FROM (
+		SELECT ` + l.assetColumns + `, to_addr AS holder, amount AS delta FROM transfers
+		UNION ALL
+		SELECT ` + l.assetColumns + `, from_addr AS holder, -amount AS delta FROM transfers
+	) deltas
+	WHERE holder <> '\x0000000000000000000000000000000000000000'::BYTEA
+	GROUP BY ` + l.assetColumns + `, holder
+	ORDER BY ` + l.assetColumns + `, holder
+	ON CONFLICT (` + l.assetColumns + `, ` + l.holderColumn + `) DO UPDATE SET
+		` + l.balanceColumn + ` = ` + l.balances + `.` + l.balanceColumn + ` + EXCLUDED.` + l.balanceColumn + `,
+		updated_at = NOW()
+`
+}
+
+// blockTransfersSQL returns a statement that applies (or, with reverse, reverses) the transfers stored for
+// the blocks whose hashes are passed as $1
+func (l transferLedger) blockTransfersSQL(reverse bool) string {
+	amount := "amount"
+	if reverse {
+		amount = "-amount"
+	}
+	return `
+	WITH transfers AS (
+		SELECT ` + l.assetColumns + `, from_addr, to_addr, ` + amount + ` AS amount
+		FROM ` + l.transfers + `
+		WHERE block_hash = ANY($1)
+	)` + l.applySQL()
+}
+
+// newTransfersSQL returns a statement that runs insertSQL, an INSERT INTO the transfers table ... ON CONFLICT
+// DO NOTHING for transfers of canonical blocks, and applies the newly inserted transfers to the balances
+func (l transferLedger) newTransfersSQL(insertSQL string) string {
+	return `
+	WITH transfers AS (` + insertSQL + `
+		RETURNING ` + l.assetColumns + `, from_addr, to_addr, amount
+	)` + l.applySQL()
+}
+
+// queryBlockHashes runs a statement returning block hashes, e.g. UPDATE blocks ... RETURNING hash
+func queryBlockHashes(ctx context.Context, tx pgx.Tx, sql string, args ...any) ([][]byte, error) {
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes [][]byte
+	for rows.Next() {
+		var hash []byte
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// applyBlockTransfers applies the stored transfers of blocks that became canonical to every ledger, or
+// with reverse, reverses those of blocks that were orphaned
+func applyBlockTransfers(ctx context.Context, tx pgx.Tx, blockHashes [][]byte, reverse bool) error {
+	if len(blockHashes) == 0 {
+		return nil
+	}
+	for _, ledger := range ledgers {
+		if _, err := tx.Exec(ctx, ledger.blockTransfersSQL(reverse), blockHashes); err != nil {
+			return fmt.Errorf("failed to update %s: %w", ledger.balances, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferLedger_BlockTransfersSQL(t *testing.T) {
+	apply := tokenLedger.blockTransfersSQL(false)
+	assert.Contains(t, apply, "SELECT token_address, from_addr, to_addr, amount AS amount")
+	assert.Contains(t, apply, "FROM token_transfers")
+	assert.Contains(t, apply, "WHERE block_hash = ANY($1)")
+	assert.Contains(t, apply, "INSERT INTO token_balances (token_address, holder, balance)")
+	assert.Contains(t, apply, "ON CONFLICT (token_address, holder) DO UPDATE SET")
+	assert.NotContains(t, apply, "-amount AS amount")
+
+	reverse := nftLedger.blockTransfersSQL(true)
+	assert.Contains(t, reverse, "SELECT contract_address, token_id, from_addr, to_addr, -amount AS amount",
+		"orphaned transfers are reversed")
+	assert.Contains(t, reverse, "INSERT INTO nft_owners (contract_address, token_id, owner, quantity)")
+	assert.Contains(t, reverse, "quantity = nft_owners.quantity + EXCLUDED.quantity")
+}
+
+func TestTransferLedger_NewTransfersSQL(t *testing.T) {
+	sql := tokenLedger.newTransfersSQL("INSERT INTO token_transfers SELECT * FROM token_transfers_staging ON CONFLICT DO NOTHING")
+	assert.Contains(t, sql, "WITH transfers AS (INSERT INTO token_transfers SELECT * FROM token_transfers_staging ON CONFLICT DO NOTHING")
+	assert.Contains(t, sql, "RETURNING token_address, from_addr, to_addr, amount")
+	assert.Contains(t, sql, "INSERT INTO token_balances")
+}
+
+func TestLedgers(t *testing.T) {
+	// Every ledger is adjusted on canonical changes
+	assert.Equal(t, []transferLedger{tokenLedger, nftLedger}, ledgers)
+}
@@ -12,14 +12,45 @@ type Block struct {
 	GasLimit    string    `json:"gas_limit"`    // String to avoid precision loss
 	Timestamp   int64     `json:"timestamp"`    // Unix timestamp
 	TxCount     int       `json:"tx_count"`
-	Orphaned    bool      `json:"orphaned"`
+	Orphaned    bool      `json:"orphaned"`     // True if the block is no longer canonical at its height
+	Finality    string    `json:"finality"`     // latest, safe or finalized
 	CreatedAt   time.Time `json:"created_at,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+
+	// Header fields; fork-specific fields are null for blocks before the fork that introduced them
+	BaseFeePerGas         *string `json:"base_fee_per_gas"`         // EIP-1559 (London), wei string
+	Difficulty            string  `json:"difficulty"`               // String to avoid precision loss, 0 after the merge
+	Nonce                 string  `json:"nonce"`                    // 0x-prefixed hex (8 bytes), zero after the merge
+	ExtraData             string  `json:"extra_data"`               // 0x-prefixed hex
+	StateRoot             string  `json:"state_root"`               // 0x-prefixed hex
+	TransactionsRoot      string  `json:"transactions_root"`        // 0x-prefixed hex
+	ReceiptsRoot          string  `json:"receipts_root"`            // 0x-prefixed hex
+	LogsBloom             string  `json:"logs_bloom"`               // 0x-prefixed hex (256 bytes)
+	MixHash               string  `json:"mix_hash"`                 // 0x-prefixed hex, PREVRANDAO after the merge
+	Sha3Uncles            string  `json:"sha3_uncles"`              // 0x-prefixed hex
+	Size                  int64   `json:"size"`                     // Block size in bytes
+	WithdrawalsRoot       *string `json:"withdrawals_root"`         // 0x-prefixed hex, EIP-4895 (Shanghai)
+	BlobGasUsed           *string `json:"blob_gas_used"`            // EIP-4844 (Cancun)
+	ExcessBlobGas         *string `json:"excess_blob_gas"`          // EIP-4844 (Cancun)
+	ParentBeaconBlockRoot *string `json:"parent_beacon_block_root"` // 0x-prefixed hex, EIP-4788 (Cancun)
+}
+
+// Withdrawal represents a validator withdrawal processed by a block (EIP-4895)
+type Withdrawal struct {
+	Index          int64  `json:"index"`
+	ValidatorIndex int64  `json:"validator_index"`
+	Address        string `json:"address"`     // 0x-prefixed hex, recipient of the withdrawal
+	AmountGwei     string `json:"amount_gwei"` // String to avoid precision loss
+	BlockHash      string `json:"block_hash"`  // 0x-prefixed hex
+	BlockHeight    int64  `json:"block_height"`
+	BlockTimestamp int64  `json:"block_timestamp"` // Unix timestamp
+	Finality       string `json:"finality"`        // Finality of the including block: latest, safe or finalized
 }
 
 // Transaction represents a blockchain transaction
 type Transaction struct {
 	Hash           string    `json:"hash"`             // 0x-prefixed hex
+	BlockHash      string    `json:"block_hash"`       // 0x-prefixed hex
 	BlockHeight    int64     `json:"block_height"`
 	BlockTimestamp *int64    `json:"block_timestamp,omitempty"` // Unix timestamp, nullable for compatibility
 	TxIndex        int       `json:"tx_index"`
@@ -31,7 +62,46 @@ type Transaction struct {
 	GasPrice       string    `json:"gas_price"`        // String to avoid precision loss
 	Nonce          int64     `json:"nonce"`
 	Success        bool      `json:"success"`
+	Orphaned       bool      `json:"orphaned"`         // True if the including block is no longer canonical
+	Finality       string    `json:"finality"`         // Finality of the including block: latest, safe or finalized
+	ContractAddress *string  `json:"contract_address,omitempty"` // 0x-prefixed hex, set for contract creation with receipts
 	CreatedAt      time.Time `json:"created_at,omitempty"`
+
+	// Typed transaction fields (EIP-2718); fee amounts are wei strings to avoid precision loss
+	Type                 int               `json:"type"`                            // 0 legacy, 1 access list, 2 dynamic fee, 3 blob, 4 set code
+	MaxFeePerGas         *string           `json:"max_fee_per_gas"`                 // EIP-1559 fee cap, null for types 0 and 1
+	MaxPriorityFeePerGas *string           `json:"max_priority_fee_per_gas"`        // EIP-1559 tip cap, null for types 0 and 1
+	EffectiveGasPrice    *string           `json:"effective_gas_price"`             // From the receipt, null without receipt ingestion
+	Input                string            `json:"input"`                           // 0x-prefixed hex call data
+	AccessList           []AccessListEntry `json:"access_list,omitempty"`           // EIP-2930 access list, omitted when empty
+	MaxFeePerBlobGas     *string           `json:"max_fee_per_blob_gas,omitempty"`  // EIP-4844 blob fee cap (blob transactions only)
+	BlobVersionedHashes  []string          `json:"blob_versioned_hashes,omitempty"` // 0x-prefixed hex (blob transactions only)
+	BlobGasUsed          *string           `json:"blob_gas_used,omitempty"`         // From the receipt (blob transactions only)
+	BlobGasPrice         *string           `json:"blob_gas_price,omitempty"`        // From the receipt (blob transactions only)
+}
+
+// InternalTransaction represents a call made during a transaction's execution, from its call trace
+type InternalTransaction struct {
+	TxHash         string  `json:"tx_hash"`    // 0x-prefixed hex
+	BlockHash      string  `json:"block_hash"` // 0x-prefixed hex
+	BlockHeight    int64   `json:"block_height"`
+	BlockTimestamp int64   `json:"block_timestamp"` // Unix timestamp
+	TraceAddress   []int   `json:"trace_address"`   // Path of call indices from the transaction's top-level call
+	Type           string  `json:"type"`            // CALL, STATICCALL, DELEGATECALL, CALLCODE, CREATE, CREATE2, SELFDESTRUCT
+	FromAddr       string  `json:"from_addr"`       // 0x-prefixed hex
+	ToAddr         *string `json:"to_addr"`         // 0x-prefixed hex, nullable for a failed contract creation
+	ValueWei       string  `json:"value_wei"`       // String to avoid precision loss
+	Gas            string  `json:"gas"`             // String to avoid precision loss
+	GasUsed        string  `json:"gas_used"`        // String to avoid precision loss
+	Depth          int     `json:"depth"`           // 1 for calls made directly by the transaction
+	Error          *string `json:"error"`           // Null unless the call reverted or failed
+	Finality       string  `json:"finality"`        // Finality of the including block: latest, safe or finalized
+}
+
+// AccessListEntry is one address of an EIP-2930 access list with the storage keys it pre-declares
+type AccessListEntry struct {
+	Address     string   `json:"address"`      // 0x-prefixed hex
+	StorageKeys []string `json:"storage_keys"` // 0x-prefixed hex
 }
 
 // Log represents an event log
@@ -48,6 +118,92 @@ type Log struct {
 	CreatedAt time.Time `json:"created_at,omitempty"`
 }
 
+// TokenTransfer represents an ERC-20 Transfer event
+type TokenTransfer struct {
+	TxHash         string `json:"tx_hash"` // 0x-prefixed hex
+	LogIndex       int    `json:"log_index"`
+	BlockHash      string `json:"block_hash"` // 0x-prefixed hex
+	BlockHeight    int64  `json:"block_height"`
+	BlockTimestamp int64  `json:"block_timestamp"` // Unix timestamp
+	TokenAddress   string `json:"token_address"`   // 0x-prefixed hex
+	FromAddr       string `json:"from_addr"`       // 0x-prefixed hex, zero address for mints
+	ToAddr         string `json:"to_addr"`         // 0x-prefixed hex, zero address for burns
+	Amount         string `json:"amount"`          // Raw amount in the token's smallest unit, string to avoid precision loss
+	Finality       string `json:"finality"`        // Finality of the including block: latest, safe or finalized
+}
+
+// TokenBalance represents the balance of a token held by an address, from indexed canonical transfers
+type TokenBalance struct {
+	TokenAddress string `json:"token_address"` // 0x-prefixed hex
+	Holder       string `json:"holder"`        // 0x-prefixed hex
+	Balance      string `json:"balance"`       // Raw amount in the token's smallest unit, string to avoid precision loss
+}
+
+// Token represents the metadata of a token or NFT contract, resolved by the worker with eth_call
+type Token struct {
+	Address     string     `json:"address"`      // 0x-prefixed hex
+	Standard    *string    `json:"standard"`     // ERC-20, ERC-721, ERC-1155 or unknown; null until resolved
+	Name        *string    `json:"name"`         // null if the contract does not implement name()
+	Symbol      *string    `json:"symbol"`       // null if the contract does not implement symbol()
+	Decimals    *int       `json:"decimals"`     // ERC-20 only
+	TotalSupply *string    `json:"total_supply"` // Raw amount at resolution time, string to avoid precision loss
+	ResolvedAt  *time.Time `json:"resolved_at"`  // null while queued
+}
+
+// NFTTransfer represents an ERC-721 Transfer or one token of an ERC-1155 TransferSingle/TransferBatch event
+type NFTTransfer struct {
+	TxHash          string `json:"tx_hash"` // 0x-prefixed hex
+	LogIndex        int    `json:"log_index"`
+	BatchIndex      int    `json:"batch_index"` // Position in a TransferBatch, 0 otherwise
+	BlockHash       string `json:"block_hash"`  // 0x-prefixed hex
+	BlockHeight     int64  `json:"block_height"`
+	BlockTimestamp  int64  `json:"block_timestamp"`  // Unix timestamp
+	ContractAddress string `json:"contract_address"` // 0x-prefixed hex
+	TokenID         string `json:"token_id"`         // Decimal, string to avoid precision loss
+	Standard        string `json:"standard"`         // ERC-721 or ERC-1155
+	FromAddr        string `json:"from_addr"`        // 0x-prefixed hex, zero address for mints
+	ToAddr          string `json:"to_addr"`          // 0x-prefixed hex, zero address for burns
+	Amount          string `json:"amount"`           // Always 1 for ERC-721
+	Finality        string `json:"finality"`         // Finality of the including block: latest, safe or finalized
+}
+
+// NFTOwnership represents the quantity of an NFT owned by an address, from indexed canonical transfers
+type NFTOwnership struct {
+	ContractAddress string `json:"contract_address"` // 0x-prefixed hex
+	TokenID         string `json:"token_id"`         // Decimal, string to avoid precision loss
+	Owner           string `json:"owner"`            // 0x-prefixed hex
+	Quantity        string `json:"quantity"`         // Always 1 for ERC-721
+}
+
+// NFT represents a single NFT with its current owners and paginated transfer history
+type NFT struct {
+	ContractAddress string         `json:"contract_address"` // 0x-prefixed hex
+	TokenID         string         `json:"token_id"`         // Decimal
+	Owners          []NFTOwnership `json:"owners"`           // A single owner for ERC-721
+	Transfers       []NFTTransfer  `json:"transfers"`        // Newest first
+}
+
+// Reorg represents a recorded chain reorganization
+type Reorg struct {
+	ID              int64        `json:"id"`
+	DetectedAt      time.Time    `json:"detected_at"`
+	ForkPointHeight int64        `json:"fork_point_height"`
+	ForkPointHash   *string      `json:"fork_point_hash"`  // 0x-prefixed hex, null for genesis
+	Depth           int          `json:"depth"`
+	OldHeadHeight   int64        `json:"old_head_height"`
+	OldHeadHash     string       `json:"old_head_hash"`    // 0x-prefixed hex
+	NewHeadHeight   int64        `json:"new_head_height"`
+	NewHeadHash     string       `json:"new_head_hash"`    // 0x-prefixed hex
+	Blocks          []ReorgBlock `json:"blocks,omitempty"` // Only set for single reorg lookups
+}
+
+// ReorgBlock represents the replaced and replacing block at one height of a reorg
+type ReorgBlock struct {
+	Height        int64  `json:"height"`
+	ReplacedHash  string `json:"replaced_hash"`  // 0x-prefixed hex, orphaned block
+	ReplacingHash string `json:"replacing_hash"` // 0x-prefixed hex, canonical block
+}
+
 // ChainStats represents blockchain statistics
 type ChainStats struct {
 	LatestBlock        int64     `json:"latest_block"`
@@ -60,11 +216,12 @@ type ChainStats struct {
 
 // HealthStatus represents system health status
 type HealthStatus struct {
-	Status              string    `json:"status"`               // "healthy" or "unhealthy"
-	Database            string    `json:"database"`             // "connected" or "disconnected"
-	IndexerLastBlock    int64     `json:"indexer_last_block"`
-	IndexerLastUpdated  time.Time `json:"indexer_last_updated"`
-	IndexerLagSeconds   int64     `json:"indexer_lag_seconds"`
-	Version             string    `json:"version"`
-	Errors              []string  `json:"errors,omitempty"`
+	Status                   string    `json:"status"`                     // "healthy", "degraded" or "unhealthy"
+	Database                 string    `json:"database"`                   // "connected" or "disconnected"
+	IndexerLastBlock         int64     `json:"indexer_last_block"`
+	IndexerLastUpdated       time.Time `json:"indexer_last_updated"`
+	IndexerLagSeconds        int64     `json:"indexer_lag_seconds"`
+	IntegrityMismatchHeights int64     `json:"integrity_mismatch_heights"` // Heights with unresolved integrity mismatches
+	Version                  string    `json:"version"`
+	Errors                   []string  `json:"errors,omitempty"`
 }
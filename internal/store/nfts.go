@@ -0,0 +1,126 @@
+package store
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+)
+
+var (
+	// transferSingleTopic is topic0 of the ERC-1155 TransferSingle event
+	transferSingleTopic = crypto.Keccak256([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+
+	// transferBatchTopic is topic0 of the ERC-1155 TransferBatch event
+	transferBatchTopic = crypto.Keccak256([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// NFT standards stored in nft_transfers.standard
+const (
+	standardERC721  = "ERC-721"
+	standardERC1155 = "ERC-1155"
+)
+
+// nftTransfer is an ERC-721 or ERC-1155 transfer decoded from a log
+// A TransferBatch log yields one transfer per token ID, numbered by BatchIndex
+type nftTransfer struct {
+	LogIndex   uint64
+	BatchIndex int
+	Contract   []byte // Address of the NFT contract (the log emitter)
+	Standard   string
+	TokenID    *big.Int
+	From       []byte
+	To         []byte
+	Amount     *big.Int // Always 1 for ERC-721
+}
+
+// decodeNFTTransfers returns the ERC-721 and ERC-1155 transfers among a transaction's logs
+// ERC-721 Transfer indexes the sender, recipient and token ID (unlike ERC-20, which has the amount as data);
+// ERC-1155 TransferSingle and TransferBatch index the operator, sender and recipient. Malformed logs are skipped.
+func decodeNFTTransfers(logs []index.Log) []nftTransfer {
+	var transfers []nftTransfer
+	for _, log := range logs {
+		if log.Topics[1] == nil || log.Topics[2] == nil || log.Topics[3] == nil {
+			continue
+		}
+
+		switch {
+		case bytes.Equal(log.Topics[0], transferEventTopic) && len(log.Data) == 0:
+			transfers = append(transfers, nftTransfer{
+				LogIndex: log.LogIndex,
+				Contract: log.Address,
+				Standard: standardERC721,
+				TokenID:  new(big.Int).SetBytes(log.Topics[3]),
+				From:     common.BytesToAddress(log.Topics[1]).Bytes(),
+				To:       common.BytesToAddress(log.Topics[2]).Bytes(),
+				Amount:   big.NewInt(1),
+			})
+
+		case bytes.Equal(log.Topics[0], transferSingleTopic) && len(log.Data) == 64:
+			transfers = append(transfers, nftTransfer{
+				LogIndex: log.LogIndex,
+				Contract: log.Address,
+				Standard: standardERC1155,
+				TokenID:  new(big.Int).SetBytes(log.Data[:32]),
+				From:     common.BytesToAddress(log.Topics[2]).Bytes(),
+				To:       common.BytesToAddress(log.Topics[3]).Bytes(),
+				Amount:   new(big.Int).SetBytes(log.Data[32:]),
+			})
+
+		case bytes.Equal(log.Topics[0], transferBatchTopic):
+			ids, idsOK := decodeUint256Array(log.Data, 0)
+			amounts, amountsOK := decodeUint256Array(log.Data, 32)
+			if !idsOK || !amountsOK || len(ids) != len(amounts) {
+				continue
+			}
+			for i := range ids {
+				transfers = append(transfers, nftTransfer{
+					LogIndex:   log.LogIndex,
+					BatchIndex: i,
+					Contract:   log.Address,
+					Standard:   standardERC1155,
+					TokenID:    ids[i],
+					From:       common.BytesToAddress(log.Topics[2]).Bytes(),
+					To:         common.BytesToAddress(log.Topics[3]).Bytes(),
+					Amount:     amounts[i],
+				})
+			}
+		}
+	}
+	return transfers
+}
+
+// nftTransferValues returns the values of an nft_transfers row after its tx_hash and block_hash:
+// log_index, batch_index, block_height, contract_address, token_id, standard, from_addr, to_addr and amount
+func nftTransferValues(transfer nftTransfer, blockHeight uint64) []any {
+	return []any{
+		int32(transfer.LogIndex), int32(transfer.BatchIndex), int64(blockHeight), transfer.Contract,
+		numericFromBig(transfer.TokenID), transfer.Standard, transfer.From, transfer.To, numericFromBig(transfer.Amount),
+	}
+}
+
+// decodeUint256Array decodes an ABI-encoded uint256[] argument whose offset is stored at position head of data
+func decodeUint256Array(data []byte, head uint64) ([]*big.Int, bool) {
+	offset, ok := index.ABIWordUint64(data, head)
+	if !ok {
+		return nil, false
+	}
+	length, ok := index.ABIWordUint64(data, offset)
+	if !ok {
+		return nil, false
+	}
+
+	start := offset + 32
+	if length > (uint64(len(data))-start)/32 {
+		return nil, false
+	}
+
+	values := make([]*big.Int, length)
+	for i := range values {
+		pos := start + uint64(i)*32
+		values[i] = new(big.Int).SetBytes(data[pos : pos+32])
+	}
+	return values, true
+}
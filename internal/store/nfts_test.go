@@ -0,0 +1,111 @@
+package store
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addressTopic left-pads an address to an indexed topic
+func addressTopic(address common.Address) []byte {
+	return common.BytesToHash(address.Bytes()).Bytes()
+}
+
+// uint256Words ABI-encodes values as consecutive 32-byte words
+func uint256Words(values ...int64) []byte {
+	var data []byte
+	for _, v := range values {
+		data = append(data, common.BigToHash(big.NewInt(v)).Bytes()...)
+	}
+	return data
+}
+
+func TestDecodeNFTTransfers(t *testing.T) {
+	contract := common.HexToAddress("0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d")
+	operator := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	logs := []index.Log{
+		{ // ERC-721 transfer of token 42
+			LogIndex: 1,
+			Address:  contract.Bytes(),
+			Topics:   [4][]byte{transferEventTopic, addressTopic(from), addressTopic(to), common.BigToHash(big.NewInt(42)).Bytes()},
+			Data:     []byte{},
+		},
+		{ // ERC-20 transfer
+			LogIndex: 2,
+			Address:  contract.Bytes(),
+			Topics:   [4][]byte{transferEventTopic, addressTopic(from), addressTopic(to)},
+			Data:     uint256Words(100),
+		},
+		{ // ERC-1155 TransferSingle of 5 units of token 7
+			LogIndex: 3,
+			Address:  contract.Bytes(),
+			Topics:   [4][]byte{transferSingleTopic, addressTopic(operator), addressTopic(from), addressTopic(to)},
+			Data:     uint256Words(7, 5),
+		},
+		{ // ERC-1155 TransferBatch of tokens [8, 9] with amounts [1, 3]
+			LogIndex: 4,
+			Address:  contract.Bytes(),
+			Topics:   [4][]byte{transferBatchTopic, addressTopic(operator), addressTopic(from), addressTopic(to)},
+			Data:     uint256Words(64, 160, 2, 8, 9, 2, 1, 3),
+		},
+	}
+
+	transfers := decodeNFTTransfers(logs)
+	require.Len(t, transfers, 4)
+
+	assert.Equal(t, nftTransfer{LogIndex: 1, Contract: contract.Bytes(), Standard: standardERC721, TokenID: big.NewInt(42),
+		From: from.Bytes(), To: to.Bytes(), Amount: big.NewInt(1)}, transfers[0])
+
+	assert.Equal(t, nftTransfer{LogIndex: 3, Contract: contract.Bytes(), Standard: standardERC1155, TokenID: big.NewInt(7),
+		From: from.Bytes(), To: to.Bytes(), Amount: big.NewInt(5)}, transfers[1], "the operator is not the sender")
+
+	assert.Equal(t, uint64(4), transfers[2].LogIndex)
+	assert.Equal(t, 0, transfers[2].BatchIndex)
+	assert.Equal(t, big.NewInt(8), transfers[2].TokenID)
+	assert.Equal(t, big.NewInt(1), transfers[2].Amount)
+	assert.Equal(t, 1, transfers[3].BatchIndex)
+	assert.Equal(t, big.NewInt(9), transfers[3].TokenID)
+	assert.Equal(t, big.NewInt(3), transfers[3].Amount)
+}
+
+func TestDecodeNFTTransfers_Malformed(t *testing.T) {
+	topics := [4][]byte{transferBatchTopic, addressTopic(common.Address{}), addressTopic(common.Address{}),
+		addressTopic(common.Address{})}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty data", nil},
+		{"offset out of bounds", uint256Words(1000, 64)},
+		{"length larger than data", uint256Words(64, 96, 5, 1)},
+		{"mismatched lengths", uint256Words(64, 128, 1, 8, 2, 1, 3)},
+		{"offset overflows uint64", append(common.MaxHash.Bytes(), uint256Words(64)...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := []index.Log{{Address: []byte{0x01}, Topics: topics, Data: tt.data}}
+			assert.Empty(t, decodeNFTTransfers(logs))
+		})
+	}
+
+	// TransferSingle with a truncated payload
+	single := index.Log{Address: []byte{0x01}, Topics: topics, Data: uint256Words(7)}
+	single.Topics[0] = transferSingleTopic
+	assert.Empty(t, decodeNFTTransfers([]index.Log{single}))
+}
+
+func TestNFTEventTopics(t *testing.T) {
+	assert.Equal(t, "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62",
+		common.BytesToHash(transferSingleTopic).Hex())
+	assert.Equal(t, "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb",
+		common.BytesToHash(transferBatchTopic).Hex())
+}
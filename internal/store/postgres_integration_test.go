@@ -4,9 +4,13 @@ package store
 
 import (
 	"context"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hieutt50/go-blockchain-explorer/internal/db"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
 	"github.com/hieutt50/go-blockchain-explorer/internal/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -258,7 +262,7 @@ func TestDatabaseIntegration_Migrations(t *testing.T) {
 
 	// Verify key indexes exist
 	indexes := []string{
-		"idx_blocks_orphaned_height",
+		"idx_blocks_canonical_height",
 		"idx_tx_from_addr_block",
 		"idx_tx_to_addr_block",
 	}
@@ -324,3 +328,91 @@ func TestDatabaseIntegration_CleanupBetweenTests(t *testing.T) {
 		assert.Equal(t, 0, count, "Logs table should be empty after cleanup")
 	})
 }
+
+// TestDatabaseIntegration_ReindexReplacesTamperedBlock tests that re-ingesting a stored block rewrites its contents
+// A corrupted copy of the block (wrong transaction value, extra transaction, wrong transfer amount) is replaced
+// by the fetched block instead of being kept next to it
+func TestDatabaseIntegration_ReindexReplacesTamperedBlock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	testDB, cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	adapter := NewIndexerAdapter(&db.Pool{Pool: testDB.Pool})
+
+	token := common.HexToAddress("0x7000000000000000000000000000000000000007").Bytes()
+	from := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	to := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	txHash := common.HexToHash("0x01").Bytes()
+
+	// newBlock returns block 1 with a transaction of value wei carrying a token transfer of amount
+	newBlock := func(value string, amount int64, extra ...index.Transaction) *index.Block {
+		transfer := index.Log{
+			Address: token,
+			Topics: [4][]byte{transferEventTopic, common.BytesToHash(from.Bytes()).Bytes(),
+				common.BytesToHash(to.Bytes()).Bytes()},
+			Data: common.BigToHash(big.NewInt(amount)).Bytes(),
+		}
+		txs := append([]index.Transaction{{
+			Hash: txHash, FromAddr: from.Bytes(), ValueWei: value, GasUsed: 21000, GasPrice: big.NewInt(1),
+			Success: true, Logs: []index.Log{transfer},
+		}}, extra...)
+		return &index.Block{
+			Height:       1,
+			Hash:         common.HexToHash("0xb1").Bytes(),
+			ParentHash:   common.HexToHash("0xb0").Bytes(),
+			Miner:        common.HexToAddress("0x03").Bytes(),
+			TxCount:      len(txs),
+			Transactions: txs,
+		}
+	}
+
+	inserts := map[string]func(context.Context, *index.Block) error{
+		"InsertBlock": adapter.InsertBlock,
+		"InsertBlocks": func(ctx context.Context, block *index.Block) error {
+			return adapter.InsertBlocks(ctx, []*index.Block{block})
+		},
+	}
+
+	for name, insert := range inserts {
+		t.Run(name, func(t *testing.T) {
+			test.CleanDatabase(t, testDB.Pool)
+
+			bogus := index.Transaction{Hash: common.HexToHash("0xbad").Bytes(), TxIndex: 1, FromAddr: from.Bytes(), ValueWei: "0"}
+			require.NoError(t, insert(ctx, newBlock("999", 500, bogus)))
+
+			// Reindex the block with the fetched (correct) contents
+			require.NoError(t, insert(ctx, newBlock("1", 100)))
+
+			var hashes [][]byte
+			rows, err := testDB.Pool.Query(ctx, "SELECT hash FROM transactions WHERE block_height = 1")
+			require.NoError(t, err)
+			for rows.Next() {
+				var hash []byte
+				require.NoError(t, rows.Scan(&hash))
+				hashes = append(hashes, hash)
+			}
+			require.NoError(t, rows.Err())
+			assert.Equal(t, [][]byte{txHash}, hashes, "tampered transaction should be removed")
+
+			var value, balance string
+			err = testDB.Pool.QueryRow(ctx, "SELECT value_wei::TEXT FROM transactions WHERE hash = $1", txHash).Scan(&value)
+			require.NoError(t, err)
+			assert.Equal(t, "1", value)
+
+			var transfers int
+			err = testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM token_transfers").Scan(&transfers)
+			require.NoError(t, err)
+			assert.Equal(t, 1, transfers)
+
+			err = testDB.Pool.QueryRow(ctx, "SELECT balance::TEXT FROM token_balances WHERE token_address = $1 AND holder = $2",
+				token, to.Bytes()).Scan(&balance)
+			require.NoError(t, err)
+			assert.Equal(t, "100", balance, "balance should only include the reindexed transfer")
+		})
+	}
+}
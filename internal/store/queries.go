@@ -3,8 +3,10 @@ package store
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -26,14 +28,86 @@ func NewStore(pool *pgxpool.Pool) *Store {
 	return &Store{pool: pool}
 }
 
-// ListBlocks returns a paginated list of non-orphaned blocks
+// txTypeColumns are the typed transaction columns selected after the base columns of every transaction query
+const txTypeColumns = `t.tx_type, t.max_fee_per_gas, t.max_priority_fee_per_gas, t.effective_gas_price, t.input,
+		       t.access_list, t.max_fee_per_blob_gas, t.blob_versioned_hashes, t.blob_gas_used, t.blob_gas_price`
+
+// txTypeFields receives the binary typed transaction columns of a row before conversion
+type txTypeFields struct {
+	input      []byte
+	accessList []byte
+	blobHashes [][]byte
+}
+
+// dest returns the scan destinations for txTypeColumns
+func (f *txTypeFields) dest(tx *Transaction) []any {
+	return []any{&tx.Type, &tx.MaxFeePerGas, &tx.MaxPriorityFeePerGas, &tx.EffectiveGasPrice, &f.input,
+		&f.accessList, &tx.MaxFeePerBlobGas, &f.blobHashes, &tx.BlobGasUsed, &tx.BlobGasPrice}
+}
+
+// apply converts the scanned binary columns to their hex and JSON representations
+func (f *txTypeFields) apply(tx *Transaction) error {
+	tx.Input = "0x" + hex.EncodeToString(f.input)
+	if f.accessList != nil {
+		if err := json.Unmarshal(f.accessList, &tx.AccessList); err != nil {
+			return fmt.Errorf("invalid access list: %w", err)
+		}
+	}
+	for _, blobHash := range f.blobHashes {
+		tx.BlobVersionedHashes = append(tx.BlobVersionedHashes, "0x"+hex.EncodeToString(blobHash))
+	}
+	return nil
+}
+
+// blockHeaderFields receives the binary header columns (blockHeaderColumns) of a block row before conversion
+type blockHeaderFields struct {
+	nonce            []byte
+	extraData        []byte
+	stateRoot        []byte
+	transactionsRoot []byte
+	receiptsRoot     []byte
+	logsBloom        []byte
+	mixHash          []byte
+	sha3Uncles       []byte
+	withdrawalsRoot  []byte
+	parentBeaconRoot []byte
+}
+
+// dest returns the scan destinations for blockHeaderColumns
+func (f *blockHeaderFields) dest(b *Block) []any {
+	return []any{&b.BaseFeePerGas, &b.Difficulty, &f.nonce, &f.extraData, &f.stateRoot, &f.transactionsRoot,
+		&f.receiptsRoot, &f.logsBloom, &f.mixHash, &f.sha3Uncles, &b.Size, &f.withdrawalsRoot, &b.BlobGasUsed,
+		&b.ExcessBlobGas, &f.parentBeaconRoot}
+}
+
+// apply converts the scanned binary columns to their hex representations
+func (f *blockHeaderFields) apply(b *Block) {
+	b.Nonce = "0x" + hex.EncodeToString(f.nonce)
+	b.ExtraData = "0x" + hex.EncodeToString(f.extraData)
+	b.StateRoot = "0x" + hex.EncodeToString(f.stateRoot)
+	b.TransactionsRoot = "0x" + hex.EncodeToString(f.transactionsRoot)
+	b.ReceiptsRoot = "0x" + hex.EncodeToString(f.receiptsRoot)
+	b.LogsBloom = "0x" + hex.EncodeToString(f.logsBloom)
+	b.MixHash = "0x" + hex.EncodeToString(f.mixHash)
+	b.Sha3Uncles = "0x" + hex.EncodeToString(f.sha3Uncles)
+	if f.withdrawalsRoot != nil {
+		withdrawalsRoot := "0x" + hex.EncodeToString(f.withdrawalsRoot)
+		b.WithdrawalsRoot = &withdrawalsRoot
+	}
+	if f.parentBeaconRoot != nil {
+		parentBeaconRoot := "0x" + hex.EncodeToString(f.parentBeaconRoot)
+		b.ParentBeaconBlockRoot = &parentBeaconRoot
+	}
+}
+
+// ListBlocks returns a paginated list of canonical blocks
 func (s *Store) ListBlocks(ctx context.Context, limit, offset int) ([]Block, int64, error) {
-	// Get total count of non-orphaned blocks
+	// Get total count of canonical blocks
 	var total int64
 	err := s.pool.QueryRow(ctx, `
 		SELECT COUNT(*)
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 	`).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count blocks: %w", err)
@@ -41,9 +115,10 @@ func (s *Store) ListBlocks(ctx context.Context, limit, offset int) ([]Block, int
 
 	// Get paginated blocks
 	rows, err := s.pool.Query(ctx, `
-		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, orphaned
+		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, NOT canonical, finality,
+		       `+strings.Join(blockHeaderColumns, ", ")+`
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 		ORDER BY height DESC
 		LIMIT $1 OFFSET $2
 	`, limit, offset)
@@ -55,13 +130,16 @@ func (s *Store) ListBlocks(ctx context.Context, limit, offset int) ([]Block, int
 	blocks := make([]Block, 0, limit)
 	for rows.Next() {
 		var b Block
+		var header blockHeaderFields
 		var hashBytes, parentHashBytes, minerBytes []byte
 
-		err := rows.Scan(&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
-			&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned)
+		err := rows.Scan(append([]any{&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
+			&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned, &b.Finality},
+			header.dest(&b)...)...)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan block: %w", err)
 		}
+		header.apply(&b)
 
 		// Convert bytes to 0x-prefixed hex strings
 		b.Hash = "0x" + hex.EncodeToString(hashBytes)
@@ -78,17 +156,20 @@ func (s *Store) ListBlocks(ctx context.Context, limit, offset int) ([]Block, int
 	return blocks, total, nil
 }
 
-// GetBlockByHeight returns a single block by height
+// GetBlockByHeight returns the canonical block at the given height
 func (s *Store) GetBlockByHeight(ctx context.Context, height int64) (*Block, error) {
 	var b Block
+	var header blockHeaderFields
 	var hashBytes, parentHashBytes, minerBytes []byte
 
 	err := s.pool.QueryRow(ctx, `
-		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, orphaned
+		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, NOT canonical, finality,
+		       `+strings.Join(blockHeaderColumns, ", ")+`
 		FROM blocks
-		WHERE height = $1 AND orphaned = FALSE
-	`, height).Scan(&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
-		&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned)
+		WHERE height = $1 AND canonical = TRUE
+	`, height).Scan(append([]any{&b.Height, &hashBytes, &parentHashBytes, &minerBytes,
+		&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned, &b.Finality},
+		header.dest(&b)...)...)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -100,11 +181,13 @@ func (s *Store) GetBlockByHeight(ctx context.Context, height int64) (*Block, err
 	b.Hash = "0x" + hex.EncodeToString(hashBytes)
 	b.ParentHash = "0x" + hex.EncodeToString(parentHashBytes)
 	b.Miner = "0x" + hex.EncodeToString(minerBytes)
+	header.apply(&b)
 
 	return &b, nil
 }
 
 // GetBlockByHash returns a single block by hash
+// Orphaned blocks are returned too, with Orphaned set
 func (s *Store) GetBlockByHash(ctx context.Context, blockHash string) (*Block, error) {
 	// Remove 0x prefix if present
 	hashStr := blockHash
@@ -118,14 +201,17 @@ func (s *Store) GetBlockByHash(ctx context.Context, blockHash string) (*Block, e
 	}
 
 	var b Block
+	var header blockHeaderFields
 	var hashBytesResult, parentHashBytes, minerBytes []byte
 
 	err = s.pool.QueryRow(ctx, `
-		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, orphaned
+		SELECT height, hash, parent_hash, miner, gas_used, gas_limit, timestamp, tx_count, NOT canonical, finality,
+		       `+strings.Join(blockHeaderColumns, ", ")+`
 		FROM blocks
-		WHERE hash = $1 AND orphaned = FALSE
-	`, hashBytes).Scan(&b.Height, &hashBytesResult, &parentHashBytes, &minerBytes,
-		&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned)
+		WHERE hash = $1
+	`, hashBytes).Scan(append([]any{&b.Height, &hashBytesResult, &parentHashBytes, &minerBytes,
+		&b.GasUsed, &b.GasLimit, &b.Timestamp, &b.TxCount, &b.Orphaned, &b.Finality},
+		header.dest(&b)...)...)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -137,11 +223,14 @@ func (s *Store) GetBlockByHash(ctx context.Context, blockHash string) (*Block, e
 	b.Hash = "0x" + hex.EncodeToString(hashBytesResult)
 	b.ParentHash = "0x" + hex.EncodeToString(parentHashBytes)
 	b.Miner = "0x" + hex.EncodeToString(minerBytes)
+	header.apply(&b)
 
 	return &b, nil
 }
 
 // GetTransaction returns a single transaction by hash
+// If the transaction was included in several blocks (e.g. re-included after a reorg), the canonical
+// inclusion is returned; a transaction only found in orphaned blocks is returned with Orphaned set
 func (s *Store) GetTransaction(ctx context.Context, txHash string) (*Transaction, error) {
 	// Remove 0x prefix if present
 	hashStr := txHash
@@ -155,16 +244,22 @@ func (s *Store) GetTransaction(ctx context.Context, txHash string) (*Transaction
 	}
 
 	var tx Transaction
-	var hashBytesResult, fromBytes []byte
-	var toAddr *[]byte
+	var typed txTypeFields
+	var hashBytesResult, blockHashBytes, fromBytes []byte
+	var toAddr, contractAddr *[]byte
 
 	err = s.pool.QueryRow(ctx, `
-		SELECT hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei,
-		       gas_used, gas_price, nonce, success
-		FROM transactions
-		WHERE hash = $1
-	`, hashBytes).Scan(&hashBytesResult, &tx.BlockHeight, &tx.TxIndex, &fromBytes, &toAddr,
-		&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success)
+		SELECT t.hash, t.block_hash, t.block_height, t.tx_index, t.from_addr, t.to_addr, t.value_wei, t.fee_wei,
+		       t.gas_used, t.gas_price, t.nonce, t.success, t.contract_address, NOT b.canonical, b.finality,
+		       `+txTypeColumns+`
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE t.hash = $1
+		ORDER BY b.canonical DESC, b.height DESC
+		LIMIT 1
+	`, hashBytes).Scan(append([]any{&hashBytesResult, &blockHashBytes, &tx.BlockHeight, &tx.TxIndex, &fromBytes, &toAddr,
+		&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success, &contractAddr, &tx.Orphaned, &tx.Finality},
+		typed.dest(&tx)...)...)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -174,6 +269,7 @@ func (s *Store) GetTransaction(ctx context.Context, txHash string) (*Transaction
 	}
 
 	tx.Hash = "0x" + hex.EncodeToString(hashBytesResult)
+	tx.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
 	tx.FromAddr = "0x" + hex.EncodeToString(fromBytes)
 
 	if toAddr != nil {
@@ -181,10 +277,19 @@ func (s *Store) GetTransaction(ctx context.Context, txHash string) (*Transaction
 		tx.ToAddr = &toAddrStr
 	}
 
+	if contractAddr != nil {
+		contractAddrStr := "0x" + hex.EncodeToString(*contractAddr)
+		tx.ContractAddress = &contractAddrStr
+	}
+
+	if err := typed.apply(&tx); err != nil {
+		return nil, err
+	}
+
 	return &tx, nil
 }
 
-// GetAddressTransactions returns paginated transactions for an address
+// GetAddressTransactions returns paginated transactions for an address in canonical blocks
 func (s *Store) GetAddressTransactions(ctx context.Context, address string, limit, offset int) ([]Transaction, int64, error) {
 	// Remove 0x prefix if present
 	addrStr := address
@@ -201,8 +306,9 @@ func (s *Store) GetAddressTransactions(ctx context.Context, address string, limi
 	var total int64
 	err = s.pool.QueryRow(ctx, `
 		SELECT COUNT(*)
-		FROM transactions
-		WHERE from_addr = $1 OR to_addr = $1
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.canonical = TRUE AND (t.from_addr = $1 OR t.to_addr = $1)
 	`, addrBytes).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
@@ -210,11 +316,12 @@ func (s *Store) GetAddressTransactions(ctx context.Context, address string, limi
 
 	// Get paginated transactions with block timestamp
 	rows, err := s.pool.Query(ctx, `
-		SELECT t.hash, t.block_height, b.timestamp, t.tx_index, t.from_addr, t.to_addr,
-		       t.value_wei, t.fee_wei, t.gas_used, t.gas_price, t.nonce, t.success
+		SELECT t.hash, t.block_hash, t.block_height, b.timestamp, t.tx_index, t.from_addr, t.to_addr,
+		       t.value_wei, t.fee_wei, t.gas_used, t.gas_price, t.nonce, t.success, b.finality,
+		       `+txTypeColumns+`
 		FROM transactions t
-		LEFT JOIN blocks b ON t.block_height = b.height AND b.orphaned = FALSE
-		WHERE t.from_addr = $1 OR t.to_addr = $1
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.canonical = TRUE AND (t.from_addr = $1 OR t.to_addr = $1)
 		ORDER BY t.block_height DESC, t.tx_index DESC
 		LIMIT $2 OFFSET $3
 	`, addrBytes, limit, offset)
@@ -226,16 +333,22 @@ func (s *Store) GetAddressTransactions(ctx context.Context, address string, limi
 	txs := make([]Transaction, 0, limit)
 	for rows.Next() {
 		var tx Transaction
-		var hashBytes, fromBytes []byte
+		var typed txTypeFields
+		var hashBytes, blockHashBytes, fromBytes []byte
 		var toAddr *[]byte
 
-		err := rows.Scan(&hashBytes, &tx.BlockHeight, &tx.BlockTimestamp, &tx.TxIndex, &fromBytes, &toAddr,
-			&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success)
+		err := rows.Scan(append([]any{&hashBytes, &blockHashBytes, &tx.BlockHeight, &tx.BlockTimestamp, &tx.TxIndex, &fromBytes, &toAddr,
+			&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success, &tx.Finality},
+			typed.dest(&tx)...)...)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan transaction: %w", err)
 		}
+		if err := typed.apply(&tx); err != nil {
+			return nil, 0, err
+		}
 
 		tx.Hash = "0x" + hex.EncodeToString(hashBytes)
+		tx.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
 		tx.FromAddr = "0x" + hex.EncodeToString(fromBytes)
 
 		if toAddr != nil {
@@ -253,14 +366,15 @@ func (s *Store) GetAddressTransactions(ctx context.Context, address string, limi
 	return txs, total, nil
 }
 
-// GetBlockTransactions returns paginated transactions for a specific block, ordered by transaction index
+// GetBlockTransactions returns paginated transactions for the canonical block at a height, ordered by transaction index
 func (s *Store) GetBlockTransactions(ctx context.Context, blockHeight int64, limit, offset int) ([]Transaction, int64, error) {
 	// Get total count of transactions in this block
 	var total int64
 	err := s.pool.QueryRow(ctx, `
 		SELECT COUNT(*)
-		FROM transactions
-		WHERE block_height = $1
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.height = $1 AND b.canonical = TRUE
 	`, blockHeight).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count block transactions: %w", err)
@@ -268,10 +382,13 @@ func (s *Store) GetBlockTransactions(ctx context.Context, blockHeight int64, lim
 
 	// Get paginated transactions ordered by tx_index
 	rows, err := s.pool.Query(ctx, `
-		SELECT hash, block_height, tx_index, from_addr, to_addr, value_wei, fee_wei, gas_used, gas_price, nonce, success
-		FROM transactions
-		WHERE block_height = $1
-		ORDER BY tx_index ASC
+		SELECT t.hash, t.block_hash, t.block_height, t.tx_index, t.from_addr, t.to_addr, t.value_wei, t.fee_wei,
+		       t.gas_used, t.gas_price, t.nonce, t.success, b.finality,
+		       `+txTypeColumns+`
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.height = $1 AND b.canonical = TRUE
+		ORDER BY t.tx_index ASC
 		LIMIT $2 OFFSET $3
 	`, blockHeight, limit, offset)
 	if err != nil {
@@ -282,16 +399,22 @@ func (s *Store) GetBlockTransactions(ctx context.Context, blockHeight int64, lim
 	txs := make([]Transaction, 0, limit)
 	for rows.Next() {
 		var tx Transaction
-		var hashBytes, fromBytes []byte
+		var typed txTypeFields
+		var hashBytes, blockHashBytes, fromBytes []byte
 		var toAddr *[]byte
 
-		err := rows.Scan(&hashBytes, &tx.BlockHeight, &tx.TxIndex, &fromBytes, &toAddr,
-			&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success)
+		err := rows.Scan(append([]any{&hashBytes, &blockHashBytes, &tx.BlockHeight, &tx.TxIndex, &fromBytes, &toAddr,
+			&tx.ValueWei, &tx.FeeWei, &tx.GasUsed, &tx.GasPrice, &tx.Nonce, &tx.Success, &tx.Finality},
+			typed.dest(&tx)...)...)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan transaction: %w", err)
 		}
+		if err := typed.apply(&tx); err != nil {
+			return nil, 0, err
+		}
 
 		tx.Hash = "0x" + hex.EncodeToString(hashBytes)
+		tx.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
 		tx.FromAddr = "0x" + hex.EncodeToString(fromBytes)
 
 		if toAddr != nil {
@@ -309,11 +432,748 @@ func (s *Store) GetBlockTransactions(ctx context.Context, blockHeight int64, lim
 	return txs, total, nil
 }
 
-// QueryLogs returns paginated event logs with optional filters
+// GetBlockWithdrawals returns paginated withdrawals of the canonical block at a height, ordered by withdrawal index
+func (s *Store) GetBlockWithdrawals(ctx context.Context, blockHeight int64, limit, offset int) ([]Withdrawal, int64, error) {
+	// Get total count of withdrawals in this block
+	var total int64
+	err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM withdrawals w
+		JOIN blocks b ON b.hash = w.block_hash
+		WHERE b.height = $1 AND b.canonical = TRUE
+	`, blockHeight).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count block withdrawals: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT w.withdrawal_index, w.validator_index, w.address, w.amount_gwei, w.block_hash, w.block_height,
+		       b.timestamp, b.finality
+		FROM withdrawals w
+		JOIN blocks b ON b.hash = w.block_hash
+		WHERE b.height = $1 AND b.canonical = TRUE
+		ORDER BY w.withdrawal_index ASC
+		LIMIT $2 OFFSET $3
+	`, blockHeight, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query block withdrawals: %w", err)
+	}
+
+	withdrawals, err := scanWithdrawals(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return withdrawals, total, nil
+}
+
+// GetAddressWithdrawals returns paginated withdrawals received by an address in canonical blocks, newest first
+func (s *Store) GetAddressWithdrawals(ctx context.Context, address string, limit, offset int) ([]Withdrawal, int64, error) {
+	// Remove 0x prefix if present
+	addrStr := address
+	if len(addrStr) > 2 && addrStr[:2] == "0x" {
+		addrStr = addrStr[2:]
+	}
+
+	addrBytes, err := hex.DecodeString(addrStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM withdrawals w
+		JOIN blocks b ON b.hash = w.block_hash
+		WHERE b.canonical = TRUE AND w.address = $1
+	`, addrBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count withdrawals: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT w.withdrawal_index, w.validator_index, w.address, w.amount_gwei, w.block_hash, w.block_height,
+		       b.timestamp, b.finality
+		FROM withdrawals w
+		JOIN blocks b ON b.hash = w.block_hash
+		WHERE b.canonical = TRUE AND w.address = $1
+		ORDER BY w.block_height DESC, w.withdrawal_index DESC
+		LIMIT $2 OFFSET $3
+	`, addrBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query withdrawals: %w", err)
+	}
+
+	withdrawals, err := scanWithdrawals(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return withdrawals, total, nil
+}
+
+// scanWithdrawals reads withdrawal rows and closes them
+func scanWithdrawals(rows pgx.Rows, limit int) ([]Withdrawal, error) {
+	defer rows.Close()
+
+	withdrawals := make([]Withdrawal, 0, limit)
+	for rows.Next() {
+		var w Withdrawal
+		var addressBytes, blockHashBytes []byte
+
+		err := rows.Scan(&w.Index, &w.ValidatorIndex, &addressBytes, &w.AmountGwei, &blockHashBytes, &w.BlockHeight,
+			&w.BlockTimestamp, &w.Finality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan withdrawal: %w", err)
+		}
+
+		w.Address = "0x" + hex.EncodeToString(addressBytes)
+		w.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
+
+		withdrawals = append(withdrawals, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating withdrawals: %w", err)
+	}
+
+	return withdrawals, nil
+}
+
+// internalTxColumns are the columns read by scanInternalTxs, from internal_transactions i joined with blocks b
+const internalTxColumns = `i.tx_hash, i.block_hash, i.block_height, b.timestamp, i.trace_address, i.call_type,
+		       i.from_addr, i.to_addr, i.value_wei, i.gas, i.gas_used, i.depth, i.error, b.finality`
+
+// GetTransactionInternalTxs returns paginated internal transactions of a transaction in execution order
+// Like GetTransaction, the canonical inclusion of the transaction is used; ErrNotFound is returned for
+// an unknown transaction. Transactions indexed without call tracing have no internal transactions.
+func (s *Store) GetTransactionInternalTxs(ctx context.Context, txHash string, limit, offset int) ([]InternalTransaction, int64, error) {
+	// Remove 0x prefix if present
+	hashStr := txHash
+	if len(hashStr) > 2 && hashStr[:2] == "0x" {
+		hashStr = hashStr[2:]
+	}
+
+	hashBytes, err := hex.DecodeString(hashStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid transaction hash: %w", err)
+	}
+
+	var blockHash []byte
+	err = s.pool.QueryRow(ctx, `
+		SELECT t.block_hash
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE t.hash = $1
+		ORDER BY b.canonical DESC, b.height DESC
+		LIMIT 1
+	`, hashBytes).Scan(&blockHash)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, 0, ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM internal_transactions
+		WHERE tx_hash = $1 AND block_hash = $2
+	`, hashBytes, blockHash).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count internal transactions: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+internalTxColumns+`
+		FROM internal_transactions i
+		JOIN blocks b ON b.hash = i.block_hash
+		WHERE i.tx_hash = $1 AND i.block_hash = $2
+		ORDER BY i.trace_address ASC
+		LIMIT $3 OFFSET $4
+	`, hashBytes, blockHash, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query internal transactions: %w", err)
+	}
+
+	internalTxs, err := scanInternalTxs(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return internalTxs, total, nil
+}
+
+// GetAddressInternalTxs returns paginated internal transactions sent or received by an address in
+// canonical blocks, newest first
+func (s *Store) GetAddressInternalTxs(ctx context.Context, address string, limit, offset int) ([]InternalTransaction, int64, error) {
+	// Remove 0x prefix if present
+	addrStr := address
+	if len(addrStr) > 2 && addrStr[:2] == "0x" {
+		addrStr = addrStr[2:]
+	}
+
+	addrBytes, err := hex.DecodeString(addrStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM internal_transactions i
+		JOIN blocks b ON b.hash = i.block_hash
+		WHERE b.canonical = TRUE AND (i.from_addr = $1 OR i.to_addr = $1)
+	`, addrBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count internal transactions: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+internalTxColumns+`
+		FROM internal_transactions i
+		JOIN blocks b ON b.hash = i.block_hash
+		JOIN transactions t ON t.hash = i.tx_hash AND t.block_hash = i.block_hash
+		WHERE b.canonical = TRUE AND (i.from_addr = $1 OR i.to_addr = $1)
+		ORDER BY i.block_height DESC, t.tx_index DESC, i.trace_address DESC
+		LIMIT $2 OFFSET $3
+	`, addrBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query internal transactions: %w", err)
+	}
+
+	internalTxs, err := scanInternalTxs(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return internalTxs, total, nil
+}
+
+// scanInternalTxs reads internal transaction rows selected with internalTxColumns and closes them
+func scanInternalTxs(rows pgx.Rows, limit int) ([]InternalTransaction, error) {
+	defer rows.Close()
+
+	internalTxs := make([]InternalTransaction, 0, limit)
+	for rows.Next() {
+		var itx InternalTransaction
+		var txHashBytes, blockHashBytes, fromBytes []byte
+		var toAddr *[]byte
+		var traceAddress []int32
+
+		err := rows.Scan(&txHashBytes, &blockHashBytes, &itx.BlockHeight, &itx.BlockTimestamp, &traceAddress, &itx.Type,
+			&fromBytes, &toAddr, &itx.ValueWei, &itx.Gas, &itx.GasUsed, &itx.Depth, &itx.Error, &itx.Finality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan internal transaction: %w", err)
+		}
+
+		itx.TxHash = "0x" + hex.EncodeToString(txHashBytes)
+		itx.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
+		itx.FromAddr = "0x" + hex.EncodeToString(fromBytes)
+
+		if toAddr != nil {
+			toAddrStr := "0x" + hex.EncodeToString(*toAddr)
+			itx.ToAddr = &toAddrStr
+		}
+
+		itx.TraceAddress = make([]int, len(traceAddress))
+		for i, position := range traceAddress {
+			itx.TraceAddress[i] = int(position)
+		}
+
+		internalTxs = append(internalTxs, itx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating internal transactions: %w", err)
+	}
+
+	return internalTxs, nil
+}
+
+// GetAddressTokens returns paginated non-zero token balances of an address, ordered by token address
+func (s *Store) GetAddressTokens(ctx context.Context, address string, limit, offset int) ([]TokenBalance, int64, error) {
+	// Remove 0x prefix if present
+	addrStr := address
+	if len(addrStr) > 2 && addrStr[:2] == "0x" {
+		addrStr = addrStr[2:]
+	}
+
+	addrBytes, err := hex.DecodeString(addrStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM token_balances
+		WHERE holder = $1 AND balance <> 0
+	`, addrBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count token balances: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT token_address, holder, balance
+		FROM token_balances
+		WHERE holder = $1 AND balance <> 0
+		ORDER BY token_address ASC
+		LIMIT $2 OFFSET $3
+	`, addrBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query token balances: %w", err)
+	}
+
+	balances, err := scanTokenBalances(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return balances, total, nil
+}
+
+// GetToken returns the metadata of a token or NFT contract
+// Returns ErrNotFound for contracts without indexed transfers; queued contracts have null metadata
+func (s *Store) GetToken(ctx context.Context, token string) (*Token, error) {
+	// Remove 0x prefix if present
+	tokenStr := token
+	if len(tokenStr) > 2 && tokenStr[:2] == "0x" {
+		tokenStr = tokenStr[2:]
+	}
+
+	tokenBytes, err := hex.DecodeString(tokenStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	var t Token
+	var addressBytes []byte
+
+	err = s.pool.QueryRow(ctx, `
+		SELECT address, standard, name, symbol, decimals, total_supply, resolved_at
+		FROM tokens
+		WHERE address = $1
+	`, tokenBytes).Scan(&addressBytes, &t.Standard, &t.Name, &t.Symbol, &t.Decimals, &t.TotalSupply, &t.ResolvedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	t.Address = "0x" + hex.EncodeToString(addressBytes)
+
+	return &t, nil
+}
+
+// GetTokenHolders returns paginated holders of a token with a positive balance, largest balance first
+func (s *Store) GetTokenHolders(ctx context.Context, token string, limit, offset int) ([]TokenBalance, int64, error) {
+	// Remove 0x prefix if present
+	tokenStr := token
+	if len(tokenStr) > 2 && tokenStr[:2] == "0x" {
+		tokenStr = tokenStr[2:]
+	}
+
+	tokenBytes, err := hex.DecodeString(tokenStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM token_balances
+		WHERE token_address = $1 AND balance > 0
+	`, tokenBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count token holders: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT token_address, holder, balance
+		FROM token_balances
+		WHERE token_address = $1 AND balance > 0
+		ORDER BY balance DESC, holder ASC
+		LIMIT $2 OFFSET $3
+	`, tokenBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query token holders: %w", err)
+	}
+
+	balances, err := scanTokenBalances(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return balances, total, nil
+}
+
+// scanTokenBalances reads token balance rows and closes them
+func scanTokenBalances(rows pgx.Rows, limit int) ([]TokenBalance, error) {
+	defer rows.Close()
+
+	balances := make([]TokenBalance, 0, limit)
+	for rows.Next() {
+		var balance TokenBalance
+		var tokenBytes, holderBytes []byte
+
+		if err := rows.Scan(&tokenBytes, &holderBytes, &balance.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan token balance: %w", err)
+		}
+
+		balance.TokenAddress = "0x" + hex.EncodeToString(tokenBytes)
+		balance.Holder = "0x" + hex.EncodeToString(holderBytes)
+
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating token balances: %w", err)
+	}
+
+	return balances, nil
+}
+
+// GetTokenTransfers returns paginated transfers of a token in canonical blocks, newest first
+func (s *Store) GetTokenTransfers(ctx context.Context, token string, limit, offset int) ([]TokenTransfer, int64, error) {
+	// Remove 0x prefix if present
+	tokenStr := token
+	if len(tokenStr) > 2 && tokenStr[:2] == "0x" {
+		tokenStr = tokenStr[2:]
+	}
+
+	tokenBytes, err := hex.DecodeString(tokenStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM token_transfers tt
+		JOIN blocks b ON b.hash = tt.block_hash
+		WHERE b.canonical = TRUE AND tt.token_address = $1
+	`, tokenBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count token transfers: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT tt.tx_hash, tt.log_index, tt.block_hash, tt.block_height, b.timestamp, tt.token_address,
+		       tt.from_addr, tt.to_addr, tt.amount, b.finality
+		FROM token_transfers tt
+		JOIN blocks b ON b.hash = tt.block_hash
+		WHERE b.canonical = TRUE AND tt.token_address = $1
+		ORDER BY tt.block_height DESC, tt.log_index DESC
+		LIMIT $2 OFFSET $3
+	`, tokenBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query token transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make([]TokenTransfer, 0, limit)
+	for rows.Next() {
+		var transfer TokenTransfer
+		var txHashBytes, blockHashBytes, tokenBytes, fromBytes, toBytes []byte
+
+		err := rows.Scan(&txHashBytes, &transfer.LogIndex, &blockHashBytes, &transfer.BlockHeight, &transfer.BlockTimestamp,
+			&tokenBytes, &fromBytes, &toBytes, &transfer.Amount, &transfer.Finality)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan token transfer: %w", err)
+		}
+
+		transfer.TxHash = "0x" + hex.EncodeToString(txHashBytes)
+		transfer.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
+		transfer.TokenAddress = "0x" + hex.EncodeToString(tokenBytes)
+		transfer.FromAddr = "0x" + hex.EncodeToString(fromBytes)
+		transfer.ToAddr = "0x" + hex.EncodeToString(toBytes)
+
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating token transfers: %w", err)
+	}
+
+	return transfers, total, nil
+}
+
+// nftTransferColumns are the columns read by scanNFTTransfers, from nft_transfers n joined with blocks b
+const nftTransferColumns = `n.tx_hash, n.log_index, n.batch_index, n.block_hash, n.block_height, b.timestamp,
+		       n.contract_address, n.token_id, n.standard, n.from_addr, n.to_addr, n.amount, b.finality`
+
+// GetAddressNFTs returns paginated NFTs currently owned by an address, ordered by contract and token ID
+func (s *Store) GetAddressNFTs(ctx context.Context, address string, limit, offset int) ([]NFTOwnership, int64, error) {
+	// Remove 0x prefix if present
+	addrStr := address
+	if len(addrStr) > 2 && addrStr[:2] == "0x" {
+		addrStr = addrStr[2:]
+	}
+
+	addrBytes, err := hex.DecodeString(addrStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM nft_owners
+		WHERE owner = $1 AND quantity > 0
+	`, addrBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count NFTs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT contract_address, token_id, owner, quantity
+		FROM nft_owners
+		WHERE owner = $1 AND quantity > 0
+		ORDER BY contract_address ASC, token_id ASC
+		LIMIT $2 OFFSET $3
+	`, addrBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query NFTs: %w", err)
+	}
+
+	owned, err := scanNFTOwnerships(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return owned, total, nil
+}
+
+// GetNFT returns the current owners of an NFT and its paginated transfers in canonical blocks, newest first
+// The returned total counts transfers; ErrNotFound is returned if the NFT has no canonical transfer.
+func (s *Store) GetNFT(ctx context.Context, contract, tokenID string, limit, offset int) (*NFT, int64, error) {
+	// Remove 0x prefix if present
+	contractStr := contract
+	if len(contractStr) > 2 && contractStr[:2] == "0x" {
+		contractStr = contractStr[2:]
+	}
+
+	contractBytes, err := hex.DecodeString(contractStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	tokenIDNumeric, err := numericFromString(tokenID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid token ID: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM nft_transfers n
+		JOIN blocks b ON b.hash = n.block_hash
+		WHERE b.canonical = TRUE AND n.contract_address = $1 AND n.token_id = $2
+	`, contractBytes, tokenIDNumeric).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count NFT transfers: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, ErrNotFound
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT contract_address, token_id, owner, quantity
+		FROM nft_owners
+		WHERE contract_address = $1 AND token_id = $2 AND quantity > 0
+		ORDER BY quantity DESC, owner ASC
+	`, contractBytes, tokenIDNumeric)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query NFT owners: %w", err)
+	}
+
+	owners, err := scanNFTOwnerships(rows, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT `+nftTransferColumns+`
+		FROM nft_transfers n
+		JOIN blocks b ON b.hash = n.block_hash
+		WHERE b.canonical = TRUE AND n.contract_address = $1 AND n.token_id = $2
+		ORDER BY n.block_height DESC, n.log_index DESC, n.batch_index DESC
+		LIMIT $3 OFFSET $4
+	`, contractBytes, tokenIDNumeric, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query NFT transfers: %w", err)
+	}
+
+	transfers, err := scanNFTTransfers(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nft := &NFT{
+		ContractAddress: "0x" + hex.EncodeToString(contractBytes),
+		TokenID:         tokenIDNumeric.Int.String(),
+		Owners:          owners,
+		Transfers:       transfers,
+	}
+	return nft, total, nil
+}
+
+// GetCollectionTokens returns paginated owned tokens of an NFT collection, ordered by token ID
+// An ERC-1155 token held by several addresses is listed once per owner.
+func (s *Store) GetCollectionTokens(ctx context.Context, contract string, limit, offset int) ([]NFTOwnership, int64, error) {
+	// Remove 0x prefix if present
+	contractStr := contract
+	if len(contractStr) > 2 && contractStr[:2] == "0x" {
+		contractStr = contractStr[2:]
+	}
+
+	contractBytes, err := hex.DecodeString(contractStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM nft_owners
+		WHERE contract_address = $1 AND quantity > 0
+	`, contractBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count collection tokens: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT contract_address, token_id, owner, quantity
+		FROM nft_owners
+		WHERE contract_address = $1 AND quantity > 0
+		ORDER BY token_id ASC, owner ASC
+		LIMIT $2 OFFSET $3
+	`, contractBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query collection tokens: %w", err)
+	}
+
+	tokens, err := scanNFTOwnerships(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tokens, total, nil
+}
+
+// GetCollectionTransfers returns paginated transfers of an NFT collection in canonical blocks, newest first
+func (s *Store) GetCollectionTransfers(ctx context.Context, contract string, limit, offset int) ([]NFTTransfer, int64, error) {
+	// Remove 0x prefix if present
+	contractStr := contract
+	if len(contractStr) > 2 && contractStr[:2] == "0x" {
+		contractStr = contractStr[2:]
+	}
+
+	contractBytes, err := hex.DecodeString(contractStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	// Get total count
+	var total int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM nft_transfers n
+		JOIN blocks b ON b.hash = n.block_hash
+		WHERE b.canonical = TRUE AND n.contract_address = $1
+	`, contractBytes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count NFT transfers: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+nftTransferColumns+`
+		FROM nft_transfers n
+		JOIN blocks b ON b.hash = n.block_hash
+		WHERE b.canonical = TRUE AND n.contract_address = $1
+		ORDER BY n.block_height DESC, n.log_index DESC, n.batch_index DESC
+		LIMIT $2 OFFSET $3
+	`, contractBytes, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query NFT transfers: %w", err)
+	}
+
+	transfers, err := scanNFTTransfers(rows, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
+
+// scanNFTOwnerships reads NFT ownership rows and closes them
+func scanNFTOwnerships(rows pgx.Rows, limit int) ([]NFTOwnership, error) {
+	defer rows.Close()
+
+	owned := make([]NFTOwnership, 0, limit)
+	for rows.Next() {
+		var ownership NFTOwnership
+		var contractBytes, ownerBytes []byte
+
+		if err := rows.Scan(&contractBytes, &ownership.TokenID, &ownerBytes, &ownership.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan NFT ownership: %w", err)
+		}
+
+		ownership.ContractAddress = "0x" + hex.EncodeToString(contractBytes)
+		ownership.Owner = "0x" + hex.EncodeToString(ownerBytes)
+
+		owned = append(owned, ownership)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating NFT ownerships: %w", err)
+	}
+
+	return owned, nil
+}
+
+// scanNFTTransfers reads rows selected with nftTransferColumns and closes them
+func scanNFTTransfers(rows pgx.Rows, limit int) ([]NFTTransfer, error) {
+	defer rows.Close()
+
+	transfers := make([]NFTTransfer, 0, limit)
+	for rows.Next() {
+		var transfer NFTTransfer
+		var txHashBytes, blockHashBytes, contractBytes, fromBytes, toBytes []byte
+
+		err := rows.Scan(&txHashBytes, &transfer.LogIndex, &transfer.BatchIndex, &blockHashBytes, &transfer.BlockHeight,
+			&transfer.BlockTimestamp, &contractBytes, &transfer.TokenID, &transfer.Standard, &fromBytes, &toBytes,
+			&transfer.Amount, &transfer.Finality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan NFT transfer: %w", err)
+		}
+
+		transfer.TxHash = "0x" + hex.EncodeToString(txHashBytes)
+		transfer.BlockHash = "0x" + hex.EncodeToString(blockHashBytes)
+		transfer.ContractAddress = "0x" + hex.EncodeToString(contractBytes)
+		transfer.FromAddr = "0x" + hex.EncodeToString(fromBytes)
+		transfer.ToAddr = "0x" + hex.EncodeToString(toBytes)
+
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating NFT transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// QueryLogs returns paginated event logs of canonical blocks with optional filters
 func (s *Store) QueryLogs(ctx context.Context, address, topic0 *string, limit, offset int) ([]Log, int64, error) {
 	// Build dynamic query based on filters
-	query := `SELECT id, tx_hash, log_index, address, topic0, topic1, topic2, topic3, data FROM logs WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM logs WHERE 1=1`
+	query := `SELECT l.id, l.tx_hash, l.log_index, l.address, l.topic0, l.topic1, l.topic2, l.topic3, l.data
+		FROM logs l JOIN blocks b ON b.hash = l.block_hash WHERE b.canonical = TRUE`
+	countQuery := `SELECT COUNT(*) FROM logs l JOIN blocks b ON b.hash = l.block_hash WHERE b.canonical = TRUE`
 	args := []interface{}{}
 	argCount := 0
 
@@ -331,8 +1191,8 @@ func (s *Store) QueryLogs(ctx context.Context, address, topic0 *string, limit, o
 			return nil, 0, fmt.Errorf("invalid address: %w", err)
 		}
 		argCount++
-		query += fmt.Sprintf(" AND address = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND address = $%d", argCount)
+		query += fmt.Sprintf(" AND l.address = $%d", argCount)
+		countQuery += fmt.Sprintf(" AND l.address = $%d", argCount)
 		args = append(args, addressBytes)
 	}
 
@@ -347,8 +1207,8 @@ func (s *Store) QueryLogs(ctx context.Context, address, topic0 *string, limit, o
 			return nil, 0, fmt.Errorf("invalid topic0: %w", err)
 		}
 		argCount++
-		query += fmt.Sprintf(" AND topic0 = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND topic0 = $%d", argCount)
+		query += fmt.Sprintf(" AND l.topic0 = $%d", argCount)
+		countQuery += fmt.Sprintf(" AND l.topic0 = $%d", argCount)
 		args = append(args, topic0Bytes)
 	}
 
@@ -361,7 +1221,7 @@ func (s *Store) QueryLogs(ctx context.Context, address, topic0 *string, limit, o
 
 	// Add pagination
 	argCount++
-	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", argCount)
+	query += fmt.Sprintf(" ORDER BY l.id DESC LIMIT $%d", argCount)
 	args = append(args, limit)
 
 	argCount++
@@ -427,7 +1287,7 @@ func (s *Store) GetChainStats(ctx context.Context) (*ChainStats, error) {
 	err := s.pool.QueryRow(ctx, `
 		SELECT COALESCE(MAX(height), 0), COALESCE(MAX(timestamp), 0)
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 	`).Scan(&stats.LatestBlock, &latestTimestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest block: %w", err)
@@ -437,16 +1297,18 @@ func (s *Store) GetChainStats(ctx context.Context) (*ChainStats, error) {
 	err = s.pool.QueryRow(ctx, `
 		SELECT COUNT(*)
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 	`).Scan(&stats.TotalBlocks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count blocks: %w", err)
 	}
 
-	// Get total transactions
+	// Get total transactions in canonical blocks
 	err = s.pool.QueryRow(ctx, `
 		SELECT COUNT(*)
-		FROM transactions
+		FROM transactions t
+		JOIN blocks b ON b.hash = t.block_hash
+		WHERE b.canonical = TRUE
 	`).Scan(&stats.TotalTransactions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count transactions: %w", err)
@@ -486,7 +1348,7 @@ func (s *Store) CheckHealth(ctx context.Context) (*HealthStatus, error) {
 	err = s.pool.QueryRow(ctx, `
 		SELECT COALESCE(MAX(height), 0), COALESCE(MAX(timestamp), 0), COALESCE(MAX(updated_at), NOW())
 		FROM blocks
-		WHERE orphaned = FALSE
+		WHERE canonical = TRUE
 	`).Scan(&status.IndexerLastBlock, &latestTimestamp, &status.IndexerLastUpdated)
 	if err != nil {
 		// Not a critical error, just log it
@@ -496,10 +1358,24 @@ func (s *Store) CheckHealth(ctx context.Context) (*HealthStatus, error) {
 		status.IndexerLagSeconds = currentTime - latestTimestamp
 	}
 
+	// Stored blocks the integrity verifier found to differ from the chain
+	err = s.pool.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT height)
+		FROM integrity_mismatches
+		WHERE resolved_at IS NULL
+	`).Scan(&status.IntegrityMismatchHeights)
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to get integrity status: %v", err))
+	} else if status.IntegrityMismatchHeights > 0 {
+		// Still serving, but some indexed data is known to be wrong
+		status.Status = "degraded"
+		status.Errors = append(status.Errors, fmt.Sprintf("%d heights with unresolved integrity mismatches", status.IntegrityMismatchHeights))
+	}
+
 	return status, nil
 }
 
-// MarkBlocksOrphaned marks all blocks in the height range [startHeight, endHeight] as orphaned
+// MarkBlocksOrphaned marks the canonical blocks in the height range [startHeight, endHeight] as orphaned
 // This method is used during chain reorganization (reorg) handling to mark blocks that are no
 // longer part of the canonical chain. Uses a database transaction for atomicity.
 // Story 1.5: Chain Reorganization Detection and Recovery - AC3
@@ -511,19 +1387,16 @@ func (s *Store) MarkBlocksOrphaned(ctx context.Context, startHeight, endHeight u
 	}
 	defer tx.Rollback(ctx) // Rollback if commit not reached
 
-	// Execute UPDATE statement to mark blocks as orphaned
-	// Soft delete pattern: SET orphaned = true (never DELETE)
-	query := `UPDATE blocks SET orphaned = true, updated_at = NOW() WHERE height >= $1 AND height <= $2`
-	result, err := tx.Exec(ctx, query, startHeight, endHeight)
+	// Execute UPDATE statement to mark blocks as orphaned and reverse their token and NFT transfers
+	// Soft delete pattern: SET canonical = false (never DELETE)
+	// Updating no blocks is not an error - blocks may already be orphaned or not exist
+	query := `UPDATE blocks SET canonical = false, finality = 'latest', updated_at = NOW() WHERE height >= $1 AND height <= $2 AND canonical = true RETURNING hash`
+	orphaned, err := queryBlockHashes(ctx, tx, query, startHeight, endHeight)
 	if err != nil {
 		return fmt.Errorf("failed to mark blocks as orphaned: %w", err)
 	}
-
-	// Verify that blocks were actually updated
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		// This is not necessarily an error - blocks may already be orphaned or not exist
-		// Log warning but don't fail the transaction
+	if err := applyBlockTransfers(ctx, tx, orphaned, true); err != nil {
+		return fmt.Errorf("failed to reverse transfers of orphaned blocks: %w", err)
 	}
 
 	// Commit transaction
@@ -533,3 +1406,110 @@ func (s *Store) MarkBlocksOrphaned(ctx context.Context, startHeight, endHeight u
 
 	return nil
 }
+
+// ListReorgs returns a paginated list of recorded reorgs, most recent first
+func (s *Store) ListReorgs(ctx context.Context, limit, offset int) ([]Reorg, int64, error) {
+	var total int64
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM reorgs`).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reorgs: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, detected_at, fork_point_height, fork_point_hash, depth,
+		       old_head_height, old_head_hash, new_head_height, new_head_hash
+		FROM reorgs
+		ORDER BY detected_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query reorgs: %w", err)
+	}
+	defer rows.Close()
+
+	reorgs := make([]Reorg, 0, limit)
+	for rows.Next() {
+		reorg, err := scanReorg(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan reorg: %w", err)
+		}
+		reorgs = append(reorgs, *reorg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating reorgs: %w", err)
+	}
+
+	return reorgs, total, nil
+}
+
+// GetReorg returns a single reorg by ID with its replaced and replacing block hashes
+func (s *Store) GetReorg(ctx context.Context, id int64) (*Reorg, error) {
+	reorg, err := scanReorg(s.pool.QueryRow(ctx, `
+		SELECT id, detected_at, fork_point_height, fork_point_hash, depth,
+		       old_head_height, old_head_hash, new_head_height, new_head_hash
+		FROM reorgs
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get reorg: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT height, old_hash, new_hash
+		FROM reorg_blocks
+		WHERE reorg_id = $1
+		ORDER BY height ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reorg blocks: %w", err)
+	}
+	defer rows.Close()
+
+	reorg.Blocks = make([]ReorgBlock, 0, reorg.Depth)
+	for rows.Next() {
+		var b ReorgBlock
+		var oldHash, newHash []byte
+
+		if err := rows.Scan(&b.Height, &oldHash, &newHash); err != nil {
+			return nil, fmt.Errorf("failed to scan reorg block: %w", err)
+		}
+
+		b.ReplacedHash = "0x" + hex.EncodeToString(oldHash)
+		b.ReplacingHash = "0x" + hex.EncodeToString(newHash)
+
+		reorg.Blocks = append(reorg.Blocks, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reorg blocks: %w", err)
+	}
+
+	return reorg, nil
+}
+
+// scanReorg scans a reorgs row and converts hashes to 0x-prefixed hex strings
+func scanReorg(row pgx.Row) (*Reorg, error) {
+	var r Reorg
+	var oldHeadHash, newHeadHash []byte
+	var forkPointHash *[]byte
+
+	err := row.Scan(&r.ID, &r.DetectedAt, &r.ForkPointHeight, &forkPointHash, &r.Depth,
+		&r.OldHeadHeight, &oldHeadHash, &r.NewHeadHeight, &newHeadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	r.OldHeadHash = "0x" + hex.EncodeToString(oldHeadHash)
+	r.NewHeadHash = "0x" + hex.EncodeToString(newHeadHash)
+
+	if forkPointHash != nil {
+		forkPointHashStr := "0x" + hex.EncodeToString(*forkPointHash)
+		r.ForkPointHash = &forkPointHashStr
+	}
+
+	return &r, nil
+}
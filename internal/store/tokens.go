@@ -0,0 +1,53 @@
+package store
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+)
+
+// transferEventTopic is topic0 of Transfer(address,address,uint256), emitted by ERC-20 and ERC-721 contracts
+var transferEventTopic = crypto.Keccak256([]byte("Transfer(address,address,uint256)"))
+
+// queueTokensSQL queues the token and NFT contracts passed as $1 for metadata resolution (see
+// index.TokenMetadataResolver); contracts already queued or resolved are left unchanged
+const queueTokensSQL = `
+	INSERT INTO tokens (address)
+	SELECT DISTINCT address FROM unnest($1::BYTEA[]) AS contracts(address)
+	ORDER BY address
+	ON CONFLICT (address) DO NOTHING
+`
+
+// tokenTransfer is an ERC-20 Transfer event decoded from a log
+type tokenTransfer struct {
+	LogIndex uint64
+	Token    []byte // Address of the token contract (the log emitter)
+	From     []byte
+	To       []byte
+	Amount   *big.Int
+}
+
+// decodeTokenTransfers returns the ERC-20 transfers among a transaction's logs
+// An ERC-20 transfer indexes the sender and recipient and carries the amount as its only data word;
+// ERC-721 transfers share the signature but also index the token ID, so they are skipped
+func decodeTokenTransfers(logs []index.Log) []tokenTransfer {
+	var transfers []tokenTransfer
+	for _, log := range logs {
+		if !bytes.Equal(log.Topics[0], transferEventTopic) || log.Topics[1] == nil || log.Topics[2] == nil ||
+			log.Topics[3] != nil || len(log.Data) != 32 {
+			continue
+		}
+
+		transfers = append(transfers, tokenTransfer{
+			LogIndex: log.LogIndex,
+			Token:    log.Address,
+			From:     common.BytesToAddress(log.Topics[1]).Bytes(),
+			To:       common.BytesToAddress(log.Topics[2]).Bytes(),
+			Amount:   new(big.Int).SetBytes(log.Data),
+		})
+	}
+	return transfers
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hieutt50/go-blockchain-explorer/internal/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTokenTransfers(t *testing.T) {
+	token := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	amount := new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil) // larger than uint64
+
+	logs := []index.Log{
+		{ // ERC-20 transfer
+			LogIndex: 3,
+			Address:  token.Bytes(),
+			Topics:   [4][]byte{transferEventTopic, common.BytesToHash(from.Bytes()).Bytes(), common.BytesToHash(to.Bytes()).Bytes()},
+			Data:     common.BigToHash(amount).Bytes(),
+		},
+		{ // ERC-721 transfer: token ID indexed, no data
+			LogIndex: 4,
+			Address:  token.Bytes(),
+			Topics: [4][]byte{transferEventTopic, common.BytesToHash(from.Bytes()).Bytes(),
+				common.BytesToHash(to.Bytes()).Bytes(), common.BigToHash(big.NewInt(1)).Bytes()},
+			Data: []byte{},
+		},
+		{ // Other event
+			LogIndex: 5,
+			Address:  token.Bytes(),
+			Topics:   [4][]byte{common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925").Bytes()},
+			Data:     common.BigToHash(amount).Bytes(),
+		},
+		{ // Malformed data
+			LogIndex: 6,
+			Address:  token.Bytes(),
+			Topics:   [4][]byte{transferEventTopic, common.BytesToHash(from.Bytes()).Bytes(), common.BytesToHash(to.Bytes()).Bytes()},
+			Data:     []byte{0x01},
+		},
+	}
+
+	transfers := decodeTokenTransfers(logs)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, uint64(3), transfers[0].LogIndex)
+	assert.Equal(t, token.Bytes(), transfers[0].Token)
+	assert.Equal(t, from.Bytes(), transfers[0].From)
+	assert.Equal(t, to.Bytes(), transfers[0].To)
+	assert.Equal(t, amount, transfers[0].Amount)
+
+	assert.Empty(t, decodeTokenTransfers(nil))
+}
+
+func TestTransferEventTopic(t *testing.T) {
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		common.BytesToHash(transferEventTopic).Hex())
+}
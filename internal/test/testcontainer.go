@@ -138,8 +138,9 @@ func CleanDatabase(t *testing.T, pool *pgxpool.Pool) {
 
 	ctx := context.Background()
 
-	// Truncate tables in reverse dependency order (logs, transactions, blocks)
-	tables := []string{"logs", "transactions", "blocks"}
+	// Truncate tables in reverse dependency order (logs, transactions, blocks), then the balances
+	// derived from transfers, which have no foreign keys to cascade from
+	tables := []string{"logs", "transactions", "blocks", "token_balances", "nft_owners"}
 
 	for _, table := range tables {
 		_, err := pool.Exec(ctx, fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
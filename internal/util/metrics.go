@@ -107,6 +107,11 @@ func SetIndexLagSeconds(lag float64) {
 // RecordRPCError increments the RPC errors counter for a specific error type
 // errorType should be one of: network, rate_limit, invalid_param, timeout, other
 func RecordRPCError(errorType string) {
+	if RPCErrors.MetricVec == nil {
+		Warn("RPCErrors metric not initialized")
+		return
+	}
+
 	// Validate error type to prevent high-cardinality label explosion
 	switch errorType {
 	case "network", "rate_limit", "invalid_param", "timeout", "other":
@@ -0,0 +1,2 @@
+-- Receipt-backed ingestion: contract address created by a transaction (NULL otherwise)
+ALTER TABLE transactions ADD COLUMN contract_address BYTEA;
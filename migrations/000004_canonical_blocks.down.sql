@@ -0,0 +1,35 @@
+-- Revert to blocks keyed by height. Orphaned blocks cannot be represented once height is
+-- the primary key, so they are deleted along with their transactions and logs (cascade).
+
+DELETE FROM blocks WHERE canonical = FALSE;
+
+-- Logs
+DROP INDEX IF EXISTS idx_logs_block_hash;
+ALTER TABLE logs DROP CONSTRAINT logs_tx_hash_block_hash_fkey;
+ALTER TABLE logs DROP CONSTRAINT logs_tx_hash_block_hash_log_index_key;
+ALTER TABLE logs DROP COLUMN block_hash;
+ALTER TABLE logs ADD CONSTRAINT logs_tx_hash_log_index_key UNIQUE (tx_hash, log_index);
+
+-- Transactions
+DROP INDEX IF EXISTS idx_tx_block_hash;
+ALTER TABLE transactions DROP CONSTRAINT transactions_block_hash_fkey;
+ALTER TABLE transactions DROP CONSTRAINT transactions_pkey;
+ALTER TABLE transactions DROP COLUMN block_hash;
+ALTER TABLE transactions ADD PRIMARY KEY (hash);
+
+-- Blocks
+DROP INDEX IF EXISTS idx_blocks_height;
+DROP INDEX IF EXISTS idx_blocks_canonical_height;
+ALTER TABLE blocks DROP CONSTRAINT blocks_pkey;
+ALTER TABLE blocks ADD PRIMARY KEY (height);
+ALTER TABLE blocks ADD CONSTRAINT blocks_hash_key UNIQUE (hash);
+
+ALTER TABLE blocks ADD COLUMN orphaned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE blocks DROP COLUMN canonical;
+CREATE INDEX idx_blocks_orphaned_height ON blocks(orphaned, height DESC);
+
+-- Restore foreign keys
+ALTER TABLE transactions ADD CONSTRAINT transactions_block_height_fkey
+    FOREIGN KEY (block_height) REFERENCES blocks(height) ON DELETE CASCADE;
+ALTER TABLE logs ADD CONSTRAINT logs_tx_hash_fkey
+    FOREIGN KEY (tx_hash) REFERENCES transactions(hash) ON DELETE CASCADE;
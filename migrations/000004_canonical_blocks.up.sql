@@ -0,0 +1,45 @@
+-- Key blocks by hash so orphaned blocks are kept when a new block is indexed at the same height.
+-- The canonical flag replaces orphaned, and at most one canonical block exists per height.
+
+-- Drop foreign keys that depend on the old keys
+ALTER TABLE logs DROP CONSTRAINT logs_tx_hash_fkey;
+ALTER TABLE transactions DROP CONSTRAINT transactions_block_height_fkey;
+
+-- Blocks: hash becomes the primary key, canonical replaces orphaned
+ALTER TABLE blocks ADD COLUMN canonical BOOLEAN NOT NULL DEFAULT TRUE;
+UPDATE blocks SET canonical = NOT orphaned;
+
+DROP INDEX IF EXISTS idx_blocks_orphaned_height;
+ALTER TABLE blocks DROP COLUMN orphaned;
+
+ALTER TABLE blocks DROP CONSTRAINT blocks_pkey;
+ALTER TABLE blocks DROP CONSTRAINT blocks_hash_key;
+ALTER TABLE blocks ADD PRIMARY KEY (hash);
+
+CREATE UNIQUE INDEX idx_blocks_canonical_height ON blocks(height DESC) WHERE canonical;
+CREATE INDEX idx_blocks_height ON blocks(height);
+
+-- Transactions: belong to a block by hash, so the same transaction can be kept
+-- in both an orphaned block and the canonical block that re-included it
+ALTER TABLE transactions ADD COLUMN block_hash BYTEA;
+UPDATE transactions t SET block_hash = b.hash FROM blocks b WHERE b.height = t.block_height;
+ALTER TABLE transactions ALTER COLUMN block_hash SET NOT NULL;
+
+ALTER TABLE transactions DROP CONSTRAINT transactions_pkey;
+ALTER TABLE transactions ADD PRIMARY KEY (hash, block_hash);
+ALTER TABLE transactions ADD CONSTRAINT transactions_block_hash_fkey
+    FOREIGN KEY (block_hash) REFERENCES blocks(hash) ON DELETE CASCADE;
+
+CREATE INDEX idx_tx_block_hash ON transactions(block_hash, tx_index);
+
+-- Logs: belong to a transaction inclusion (tx hash + block hash)
+ALTER TABLE logs ADD COLUMN block_hash BYTEA;
+UPDATE logs l SET block_hash = t.block_hash FROM transactions t WHERE t.hash = l.tx_hash;
+ALTER TABLE logs ALTER COLUMN block_hash SET NOT NULL;
+
+ALTER TABLE logs DROP CONSTRAINT logs_tx_hash_log_index_key;
+ALTER TABLE logs ADD CONSTRAINT logs_tx_hash_block_hash_log_index_key UNIQUE (tx_hash, block_hash, log_index);
+ALTER TABLE logs ADD CONSTRAINT logs_tx_hash_block_hash_fkey
+    FOREIGN KEY (tx_hash, block_hash) REFERENCES transactions(hash, block_hash) ON DELETE CASCADE;
+
+CREATE INDEX idx_logs_block_hash ON logs(block_hash);
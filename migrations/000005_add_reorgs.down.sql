@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reorgs_detected_at;
+DROP TABLE IF EXISTS reorg_blocks;
+DROP TABLE IF EXISTS reorgs;
@@ -0,0 +1,24 @@
+-- Reorg history for auditing chain instability
+CREATE TABLE reorgs (
+    id BIGSERIAL PRIMARY KEY,
+    detected_at TIMESTAMP NOT NULL,
+    fork_point_height BIGINT NOT NULL,
+    fork_point_hash BYTEA,  -- NULL when the fork point is the genesis block
+    depth INTEGER NOT NULL,
+    old_head_height BIGINT NOT NULL,
+    old_head_hash BYTEA NOT NULL,
+    new_head_height BIGINT NOT NULL,
+    new_head_hash BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Replaced (orphaned) and replacing (canonical) block hash per height of a reorg
+CREATE TABLE reorg_blocks (
+    reorg_id BIGINT NOT NULL REFERENCES reorgs(id) ON DELETE CASCADE,
+    height BIGINT NOT NULL,
+    old_hash BYTEA NOT NULL,
+    new_hash BYTEA NOT NULL,
+    PRIMARY KEY (reorg_id, height)
+);
+
+CREATE INDEX idx_reorgs_detected_at ON reorgs(detected_at DESC);
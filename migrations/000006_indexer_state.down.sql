@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_indexer_state_range;
+DROP TABLE IF EXISTS indexer_state;
@@ -0,0 +1,11 @@
+-- Completed backfill height ranges, so the worker can resume and skip verified history.
+-- Adjacent and overlapping ranges are merged on write.
+CREATE TABLE indexer_state (
+    id BIGSERIAL PRIMARY KEY,
+    start_height BIGINT NOT NULL,
+    end_height BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (start_height <= end_height)
+);
+
+CREATE INDEX idx_indexer_state_range ON indexer_state(start_height, end_height);
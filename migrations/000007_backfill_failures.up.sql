@@ -0,0 +1,8 @@
+-- Dead-letter list of heights that backfill could not fetch or parse after all retries.
+-- Rows are removed once the height is indexed and checkpointed.
+CREATE TABLE backfill_failures (
+    height BIGINT PRIMARY KEY,
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
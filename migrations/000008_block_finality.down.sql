@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_blocks_not_finalized;
+ALTER TABLE blocks DROP COLUMN IF EXISTS finality;
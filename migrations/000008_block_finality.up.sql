@@ -0,0 +1,8 @@
+-- Finality status per block from the post-merge "safe" and "finalized" block tags.
+-- Blocks are indexed as latest; the finality tracker promotes canonical blocks as the
+-- chain's safe and finalized heights advance. Orphaned blocks are always latest.
+ALTER TABLE blocks ADD COLUMN finality TEXT NOT NULL DEFAULT 'latest'
+    CHECK (finality IN ('latest', 'safe', 'finalized'));
+
+-- Canonical blocks still waiting to be finalized (kept small by the tracker)
+CREATE INDEX idx_blocks_not_finalized ON blocks(height) WHERE canonical AND finality <> 'finalized';
@@ -0,0 +1,15 @@
+-- Differences between the index and the chain found by the integrity verifier.
+-- One open row per height and field; a row is resolved once a later round finds the
+-- height correct again (e.g. after re-indexing with explorerctl verify --repair).
+CREATE TABLE integrity_mismatches (
+    id BIGSERIAL PRIMARY KEY,
+    height BIGINT NOT NULL,
+    field TEXT NOT NULL,
+    stored TEXT NOT NULL,
+    chain TEXT NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_integrity_mismatches_open ON integrity_mismatches(height, field) WHERE resolved_at IS NULL;
@@ -0,0 +1,18 @@
+-- Typed transaction fields (EIP-2718): per-type fees, call data, access lists (EIP-2930)
+-- and blob fields (EIP-4844). Fee caps are NULL for types that do not have them; receipt
+-- values (effective gas price, blob gas used and price) are NULL without receipt ingestion.
+-- Rows indexed before this migration report type 0 and empty input until they are re-indexed.
+ALTER TABLE transactions
+    ADD COLUMN tx_type SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN max_fee_per_gas NUMERIC,
+    ADD COLUMN max_priority_fee_per_gas NUMERIC,
+    ADD COLUMN effective_gas_price NUMERIC,
+    ADD COLUMN input BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN access_list JSONB,
+    ADD COLUMN max_fee_per_blob_gas NUMERIC,
+    ADD COLUMN blob_versioned_hashes BYTEA[],
+    ADD COLUMN blob_gas_used NUMERIC,
+    ADD COLUMN blob_gas_price NUMERIC;
+
+-- Per-type fee analytics over block ranges
+CREATE INDEX idx_tx_type_block_height ON transactions(tx_type, block_height);
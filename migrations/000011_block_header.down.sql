@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS withdrawals;
+
+ALTER TABLE blocks
+    DROP COLUMN IF EXISTS base_fee_per_gas,
+    DROP COLUMN IF EXISTS difficulty,
+    DROP COLUMN IF EXISTS nonce,
+    DROP COLUMN IF EXISTS extra_data,
+    DROP COLUMN IF EXISTS state_root,
+    DROP COLUMN IF EXISTS transactions_root,
+    DROP COLUMN IF EXISTS receipts_root,
+    DROP COLUMN IF EXISTS logs_bloom,
+    DROP COLUMN IF EXISTS mix_hash,
+    DROP COLUMN IF EXISTS sha3_uncles,
+    DROP COLUMN IF EXISTS size,
+    DROP COLUMN IF EXISTS withdrawals_root,
+    DROP COLUMN IF EXISTS blob_gas_used,
+    DROP COLUMN IF EXISTS excess_blob_gas,
+    DROP COLUMN IF EXISTS parent_beacon_block_root;
@@ -0,0 +1,36 @@
+-- Full block header (post-Shanghai and post-Cancun). Fork-specific fields are NULL for
+-- blocks before the fork that introduced them: base fee (London), withdrawals root
+-- (Shanghai), blob gas and parent beacon block root (Cancun). Blocks indexed before this
+-- migration report zero values (and NULL fork fields) until they are re-indexed.
+ALTER TABLE blocks
+    ADD COLUMN base_fee_per_gas NUMERIC,
+    ADD COLUMN difficulty NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN nonce BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN extra_data BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN state_root BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN transactions_root BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN receipts_root BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN logs_bloom BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN mix_hash BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN sha3_uncles BYTEA NOT NULL DEFAULT ''::BYTEA,
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN withdrawals_root BYTEA,
+    ADD COLUMN blob_gas_used NUMERIC,
+    ADD COLUMN excess_blob_gas NUMERIC,
+    ADD COLUMN parent_beacon_block_root BYTEA;
+
+-- Validator withdrawals (EIP-4895), kept per block like transactions so orphaned
+-- blocks keep their withdrawals
+CREATE TABLE withdrawals (
+    block_hash BYTEA NOT NULL REFERENCES blocks(hash) ON DELETE CASCADE,
+    block_height BIGINT NOT NULL,
+    withdrawal_index BIGINT NOT NULL,
+    validator_index BIGINT NOT NULL,
+    address BYTEA NOT NULL,
+    amount_gwei NUMERIC NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (block_hash, withdrawal_index)
+);
+
+-- Withdrawals received by an address, newest first
+CREATE INDEX idx_withdrawals_address_block ON withdrawals(address, block_height DESC, withdrawal_index DESC);
@@ -0,0 +1,25 @@
+-- Internal transactions: the calls made by a transaction's execution, flattened from the
+-- debug_traceBlockByHash call tracer. Only populated when call tracing is enabled
+-- (INGEST_TRACES). trace_address is the path of child indices from the transaction's
+-- top-level call, so ordering by it gives execution order.
+CREATE TABLE internal_transactions (
+    tx_hash BYTEA NOT NULL,
+    block_hash BYTEA NOT NULL,
+    block_height BIGINT NOT NULL,
+    trace_address INTEGER[] NOT NULL,
+    call_type TEXT NOT NULL,
+    from_addr BYTEA NOT NULL,
+    to_addr BYTEA,
+    value_wei NUMERIC NOT NULL,
+    gas NUMERIC NOT NULL,
+    gas_used NUMERIC NOT NULL,
+    depth INTEGER NOT NULL,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tx_hash, block_hash, trace_address),
+    FOREIGN KEY (tx_hash, block_hash) REFERENCES transactions(hash, block_hash) ON DELETE CASCADE
+);
+
+-- Internal transactions sent or received by an address, newest first
+CREATE INDEX idx_internal_txs_from_addr_block ON internal_transactions(from_addr, block_height DESC);
+CREATE INDEX idx_internal_txs_to_addr_block ON internal_transactions(to_addr, block_height DESC);
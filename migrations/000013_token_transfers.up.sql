@@ -0,0 +1,39 @@
+-- ERC-20 Transfer(address,address,uint256) events decoded from logs, kept per block like
+-- logs so orphaned blocks keep their transfers. Only populated with receipt ingestion
+-- (INGEST_RECEIPTS); logs stored before this migration are decoded when their blocks are
+-- re-indexed.
+CREATE TABLE token_transfers (
+    tx_hash BYTEA NOT NULL,
+    block_hash BYTEA NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_height BIGINT NOT NULL,
+    token_address BYTEA NOT NULL,
+    from_addr BYTEA NOT NULL,
+    to_addr BYTEA NOT NULL,
+    amount NUMERIC NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tx_hash, block_hash, log_index),
+    FOREIGN KEY (tx_hash, block_hash) REFERENCES transactions(hash, block_hash) ON DELETE CASCADE
+);
+
+-- Transfers of a token, newest first
+CREATE INDEX idx_token_transfers_token_block ON token_transfers(token_address, block_height DESC, log_index DESC);
+-- Transfers of orphaned or re-canonicalized blocks when balances are adjusted
+CREATE INDEX idx_token_transfers_block_hash ON token_transfers(block_hash);
+
+-- Running token balance per holder: the sum of the holder's transfers in canonical blocks.
+-- Adjusted whenever a block becomes canonical or is orphaned. The zero address (mint and
+-- burn counterparty) is not tracked. Balances only cover indexed blocks, so they can be
+-- incomplete (or negative) when indexing did not start at the token's deployment.
+CREATE TABLE token_balances (
+    token_address BYTEA NOT NULL,
+    holder BYTEA NOT NULL,
+    balance NUMERIC NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (token_address, holder)
+);
+
+-- Largest holders of a token
+CREATE INDEX idx_token_balances_token_balance ON token_balances(token_address, balance DESC);
+-- Tokens held by an address
+CREATE INDEX idx_token_balances_holder ON token_balances(holder);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS nft_owners;
+DROP TABLE IF EXISTS nft_transfers;
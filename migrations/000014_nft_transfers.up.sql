@@ -0,0 +1,42 @@
+-- ERC-721 Transfer and ERC-1155 TransferSingle/TransferBatch events decoded from logs,
+-- kept per block like logs so orphaned blocks keep their transfers. A TransferBatch log
+-- has one row per token ID (batch_index); other logs have batch_index 0. Only populated
+-- with receipt ingestion (INGEST_RECEIPTS).
+CREATE TABLE nft_transfers (
+    tx_hash BYTEA NOT NULL,
+    block_hash BYTEA NOT NULL,
+    log_index INTEGER NOT NULL,
+    batch_index INTEGER NOT NULL,
+    block_height BIGINT NOT NULL,
+    contract_address BYTEA NOT NULL,
+    token_id NUMERIC NOT NULL,
+    standard TEXT NOT NULL,
+    from_addr BYTEA NOT NULL,
+    to_addr BYTEA NOT NULL,
+    amount NUMERIC NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tx_hash, block_hash, log_index, batch_index),
+    FOREIGN KEY (tx_hash, block_hash) REFERENCES transactions(hash, block_hash) ON DELETE CASCADE
+);
+
+-- Transfers of a collection, newest first
+CREATE INDEX idx_nft_transfers_contract_block ON nft_transfers(contract_address, block_height DESC, log_index DESC);
+-- Transfer history of a token
+CREATE INDEX idx_nft_transfers_token_block ON nft_transfers(contract_address, token_id, block_height DESC);
+-- Transfers of orphaned or re-canonicalized blocks when ownership is adjusted
+CREATE INDEX idx_nft_transfers_block_hash ON nft_transfers(block_hash);
+
+-- Current ownership per token: the quantity each owner received minus what it sent in
+-- canonical blocks (0 or 1 for ERC-721). Adjusted whenever a block becomes canonical or
+-- is orphaned, like token_balances.
+CREATE TABLE nft_owners (
+    contract_address BYTEA NOT NULL,
+    token_id NUMERIC NOT NULL,
+    owner BYTEA NOT NULL,
+    quantity NUMERIC NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (contract_address, token_id, owner)
+);
+
+-- NFTs owned by an address
+CREATE INDEX idx_nft_owners_owner ON nft_owners(owner, contract_address, token_id);
@@ -0,0 +1,27 @@
+-- Metadata of token and NFT contracts, read by the worker's token metadata resolver with
+-- eth_call (name(), symbol(), decimals(), totalSupply()) and ERC-165 supportsInterface.
+-- A contract is queued (resolved_at NULL) when its first ERC-20, ERC-721 or ERC-1155
+-- transfer is indexed and resolved once; the stored values are the cache served by the
+-- API. Contracts whose calls fail for RPC reasons are retried with a doubling delay.
+CREATE TABLE tokens (
+    address BYTEA PRIMARY KEY,
+    standard TEXT,          -- ERC-20, ERC-721, ERC-1155 or unknown; NULL until resolved
+    name TEXT,
+    symbol TEXT,
+    decimals SMALLINT,      -- ERC-20 only
+    total_supply NUMERIC,   -- At resolution time
+    resolved_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Queued contracts due for resolution
+CREATE INDEX idx_tokens_pending ON tokens(next_attempt_at) WHERE resolved_at IS NULL;
+
+-- Queue the contracts of transfers indexed before this migration
+INSERT INTO tokens (address)
+SELECT token_address FROM token_transfers
+UNION
+SELECT contract_address FROM nft_transfers
+ON CONFLICT (address) DO NOTHING;
@@ -21,9 +21,9 @@ echo ""
 echo "--- Test 1: Blocks Pagination (LIMIT 25 OFFSET 0) ---"
 psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME <<EOF
 EXPLAIN (ANALYZE, BUFFERS, FORMAT TEXT)
-SELECT height, hash, parent_hash, timestamp, orphaned
+SELECT height, hash, parent_hash, timestamp, canonical
 FROM blocks
-WHERE orphaned = false
+WHERE canonical = TRUE
 ORDER BY height DESC
 LIMIT 25 OFFSET 0;
 EOF
@@ -33,9 +33,9 @@ echo ""
 echo "--- Test 2: Blocks Pagination (LIMIT 25 OFFSET 1000) ---"
 psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME <<EOF
 EXPLAIN (ANALYZE, BUFFERS, FORMAT TEXT)
-SELECT height, hash, parent_hash, timestamp, orphaned
+SELECT height, hash, parent_hash, timestamp, canonical
 FROM blocks
-WHERE orphaned = false
+WHERE canonical = TRUE
 ORDER BY height DESC
 LIMIT 25 OFFSET 1000;
 EOF
@@ -70,7 +70,7 @@ echo ""
 echo "--- Test 5: COUNT(*) Total Blocks ---"
 psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME <<EOF
 EXPLAIN (ANALYZE, BUFFERS, FORMAT TEXT)
-SELECT COUNT(*) FROM blocks WHERE orphaned = false;
+SELECT COUNT(*) FROM blocks WHERE canonical = TRUE;
 EOF
 echo ""
 
@@ -78,9 +78,9 @@ echo ""
 echo "--- Test 6: Large Offset (LIMIT 25 OFFSET 4000) ---"
 psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME <<EOF
 EXPLAIN (ANALYZE, BUFFERS, FORMAT TEXT)
-SELECT height, hash, parent_hash, timestamp, orphaned
+SELECT height, hash, parent_hash, timestamp, canonical
 FROM blocks
-WHERE orphaned = false
+WHERE canonical = TRUE
 ORDER BY height DESC
 LIMIT 25 OFFSET 4000;
 EOF